@@ -1,6 +1,8 @@
 package abcicli
 
 import (
+	"sync"
+
 	types "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/service"
 	tmsync "github.com/tendermint/tendermint/libs/sync"
@@ -15,7 +17,11 @@ var _ Client = (*localClient)(nil)
 type localClient struct {
 	service.BaseService
 
-	mtx *tmsync.Mutex
+	mtx sync.Locker
+	// checkTxMtx and checkTxSem are only set for clients created with
+	// NewConcurrentCheckTxLocalClient.
+	checkTxMtx *tmsync.RWMutex
+	checkTxSem chan struct{}
 	types.Application
 	Callback
 }
@@ -38,6 +44,47 @@ func NewLocalClient(mtx *tmsync.Mutex, app types.Application) Client {
 	return cli
 }
 
+// NewConcurrentCheckTxLocalClient creates a local client which, unlike the one
+// returned by NewLocalClient, lets up to concurrency CheckTx calls run at the
+// same time. CheckTx calls never overlap with calls to other methods: CheckTx
+// holds mtx for reading while every other method holds it for writing, so mtx
+// should be shared by all the clients of the given app.
+//
+// The app's CheckTx must be safe for concurrent use. Responses are delivered
+// in the order in which the calls complete, which may differ from the order
+// in which they were made.
+func NewConcurrentCheckTxLocalClient(mtx *tmsync.RWMutex, app types.Application, concurrency int) Client {
+	if mtx == nil {
+		mtx = new(tmsync.RWMutex)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	cli := &localClient{
+		mtx:         mtx,
+		checkTxMtx:  mtx,
+		checkTxSem:  make(chan struct{}, concurrency),
+		Application: app,
+	}
+	cli.BaseService = *service.NewBaseService(nil, "localClient", cli)
+	return cli
+}
+
+// lockCheckTx acquires the lock needed to call the app's CheckTx and returns a
+// function releasing it.
+func (app *localClient) lockCheckTx() (unlock func()) {
+	if app.checkTxSem == nil {
+		app.mtx.Lock()
+		return app.mtx.Unlock
+	}
+	app.checkTxSem <- struct{}{}
+	app.checkTxMtx.RLock()
+	return func() {
+		app.checkTxMtx.RUnlock()
+		<-app.checkTxSem
+	}
+}
+
 func (app *localClient) SetResponseCallback(cb Callback) {
 	app.mtx.Lock()
 	app.Callback = cb
@@ -98,8 +145,7 @@ func (app *localClient) DeliverTxAsync(params types.RequestDeliverTx) *ReqRes {
 }
 
 func (app *localClient) CheckTxAsync(req types.RequestCheckTx) *ReqRes {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	defer app.lockCheckTx()()
 
 	res := app.Application.CheckTx(req)
 	return app.callback(
@@ -242,8 +288,7 @@ func (app *localClient) DeliverTxSync(req types.RequestDeliverTx) (*types.Respon
 }
 
 func (app *localClient) CheckTxSync(req types.RequestCheckTx) (*types.ResponseCheckTx, error) {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	defer app.lockCheckTx()()
 
 	res := app.Application.CheckTx(req)
 	return &res, nil
@@ -0,0 +1,151 @@
+package abcicli_test
+
+import (
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/types"
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+// concurrencyApp records the maximum number of CheckTx calls running at the
+// same time, and whether any of them overlapped with a DeliverTx call.
+type concurrencyApp struct {
+	types.BaseApplication
+
+	inCheckTx    int32
+	maxCheckTx   int32
+	inDeliverTx  int32
+	overlaps     int32
+	checkTxDelay time.Duration
+}
+
+func (app *concurrencyApp) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
+	n := atomic.AddInt32(&app.inCheckTx, 1)
+	defer atomic.AddInt32(&app.inCheckTx, -1)
+	for {
+		max := atomic.LoadInt32(&app.maxCheckTx)
+		if n <= max || atomic.CompareAndSwapInt32(&app.maxCheckTx, max, n) {
+			break
+		}
+	}
+	if atomic.LoadInt32(&app.inDeliverTx) > 0 {
+		atomic.AddInt32(&app.overlaps, 1)
+	}
+	time.Sleep(app.checkTxDelay)
+	return types.ResponseCheckTx{Code: types.CodeTypeOK}
+}
+
+func (app *concurrencyApp) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
+	atomic.AddInt32(&app.inDeliverTx, 1)
+	defer atomic.AddInt32(&app.inDeliverTx, -1)
+	if atomic.LoadInt32(&app.inCheckTx) > 0 {
+		atomic.AddInt32(&app.overlaps, 1)
+	}
+	time.Sleep(app.checkTxDelay)
+	return types.ResponseDeliverTx{Code: types.CodeTypeOK}
+}
+
+func TestLocalClientCheckTxConcurrency(t *testing.T) {
+	testCases := []struct {
+		name        string
+		newClients  func(app types.Application) (mempool, consensus abcicli.Client)
+		expectedMax int32
+	}{
+		{
+			"serial",
+			func(app types.Application) (abcicli.Client, abcicli.Client) {
+				mtx := new(tmsync.Mutex)
+				return abcicli.NewLocalClient(mtx, app), abcicli.NewLocalClient(mtx, app)
+			},
+			1,
+		},
+		{
+			"concurrent",
+			func(app types.Application) (abcicli.Client, abcicli.Client) {
+				mtx := new(tmsync.RWMutex)
+				return abcicli.NewConcurrentCheckTxLocalClient(mtx, app, 4),
+					abcicli.NewConcurrentCheckTxLocalClient(mtx, app, 4)
+			},
+			4,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			app := &concurrencyApp{checkTxDelay: 10 * time.Millisecond}
+			mempool, consensus := tc.newClients(app)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 16; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					res, err := mempool.CheckTxSync(types.RequestCheckTx{})
+					assert.NoError(t, err)
+					assert.Equal(t, types.CodeTypeOK, res.Code)
+				}()
+			}
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := consensus.DeliverTxSync(types.RequestDeliverTx{})
+					assert.NoError(t, err)
+				}()
+			}
+			wg.Wait()
+
+			require.Equal(t, tc.expectedMax, atomic.LoadInt32(&app.maxCheckTx))
+			require.Zero(t, atomic.LoadInt32(&app.overlaps), "CheckTx overlapped with DeliverTx")
+		})
+	}
+}
+
+// cpuBoundApp simulates an application whose CheckTx is CPU-bound and safe
+// for concurrent use.
+type cpuBoundApp struct {
+	types.BaseApplication
+}
+
+func (cpuBoundApp) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
+	sum := sha256.Sum256(req.Tx)
+	for i := 0; i < 1000; i++ {
+		sum = sha256.Sum256(sum[:])
+	}
+	return types.ResponseCheckTx{Code: types.CodeTypeOK, Data: sum[:]}
+}
+
+func BenchmarkLocalClientCheckTx(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		client abcicli.Client
+	}{
+		{"serial", abcicli.NewLocalClient(nil, cpuBoundApp{})},
+		{"concurrency=4", abcicli.NewConcurrentCheckTxLocalClient(nil, cpuBoundApp{}, 4)},
+		{"concurrency=8", abcicli.NewConcurrentCheckTxLocalClient(nil, cpuBoundApp{}, 8)},
+	}
+
+	for _, bm := range benchmarks {
+		bm := bm
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				req := types.RequestCheckTx{Tx: []byte("tx")}
+				for pb.Next() {
+					if _, err := bm.client.CheckTxSync(req); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/tendermint/tendermint/config"
+	tmnet "github.com/tendermint/tendermint/libs/net"
+	tmos "github.com/tendermint/tendermint/libs/os"
+)
+
+// ErrHighRiskRPCExposure is returned by check-rpc-exposure if the RPC
+// configuration has at least one high-risk finding.
+var ErrHighRiskRPCExposure = errors.New("high-risk RPC exposure")
+
+// CheckRPCExposureCmd inspects the RPC configuration and warns about the
+// settings exposing the node.
+var CheckRPCExposureCmd = &cobra.Command{
+	Use:   "check-rpc-exposure",
+	Short: "Check the RPC configuration for risky exposure",
+	Long: `
+check-rpc-exposure inspects the RPC configuration of the node, without starting
+it, and reports the settings which expose the node: an RPC, gRPC or pprof
+server bound to a public interface, the RPC server served without TLS, the
+unsafe routes enabled, a wildcard CORS origin, or an unlimited number of
+connections.
+
+The RPC server of Tendermint doesn't authenticate its clients: a server bound
+to a public interface should be served behind a proxy enforcing
+authentication. Each finding is reported along with its risk and how to fix
+it. The command fails if any of the findings is high-risk.
+`,
+	Example: `
+	tendermint check-rpc-exposure
+	tendermint check-rpc-exposure --home /path/to/home
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		findings := checkRPCExposure(config.RPC)
+		if len(findings) == 0 {
+			fmt.Println("No risky RPC exposure found")
+			return nil
+		}
+
+		high := 0
+		for _, f := range findings {
+			if f.Risk == rpcExposureRiskHigh {
+				high++
+			}
+			fmt.Printf("[%s] %s\n    fix: %s\n", strings.ToUpper(f.Risk), f.Message, f.Fix)
+		}
+		if high > 0 {
+			return fmt.Errorf("%w: %d high-risk finding(s)", ErrHighRiskRPCExposure, high)
+		}
+		return nil
+	},
+}
+
+const (
+	rpcExposureRiskHigh   = "high"
+	rpcExposureRiskMedium = "medium"
+	rpcExposureRiskLow    = "low"
+)
+
+// rpcExposureFinding is a risky setting of the RPC configuration.
+type rpcExposureFinding struct {
+	Risk    string
+	Message string
+	Fix     string
+}
+
+// checkRPCExposure returns the risky settings of the given RPC configuration,
+// most severe first.
+func checkRPCExposure(rpcConfig *cfg.RPCConfig) []rpcExposureFinding {
+	var high, medium, low []rpcExposureFinding
+	add := func(risk, msg, fix string) {
+		f := rpcExposureFinding{Risk: risk, Message: msg, Fix: fix}
+		switch risk {
+		case rpcExposureRiskHigh:
+			high = append(high, f)
+		case rpcExposureRiskMedium:
+			medium = append(medium, f)
+		default:
+			low = append(low, f)
+		}
+	}
+
+	public := isPublicListenAddress(rpcConfig.ListenAddress)
+	tlsEnabled := rpcConfig.IsTLSEnabled()
+
+	if public {
+		if tlsEnabled {
+			add(rpcExposureRiskMedium,
+				fmt.Sprintf("rpc.laddr %q is bound to a public interface; the RPC server doesn't authenticate clients",
+					rpcConfig.ListenAddress),
+				"serve the RPC behind a proxy enforcing authentication, or bind rpc.laddr to 127.0.0.1")
+		} else {
+			add(rpcExposureRiskHigh,
+				fmt.Sprintf("rpc.laddr %q is bound to a public interface without TLS nor authentication",
+					rpcConfig.ListenAddress),
+				"bind rpc.laddr to 127.0.0.1 and serve it behind a proxy enforcing TLS and authentication")
+		}
+	}
+
+	if (rpcConfig.TLSCertFile == "") != (rpcConfig.TLSKeyFile == "") {
+		add(rpcExposureRiskMedium,
+			"only one of rpc.tls_cert_file and rpc.tls_key_file is set: TLS is disabled",
+			"set both rpc.tls_cert_file and rpc.tls_key_file to enable TLS")
+	} else if tlsEnabled {
+		for _, file := range []string{rpcConfig.CertFile(), rpcConfig.KeyFile()} {
+			if !tmos.FileExists(file) {
+				add(rpcExposureRiskMedium,
+					fmt.Sprintf("TLS file %q doesn't exist: the RPC server will fail to start", file),
+					"fix the path of rpc.tls_cert_file and rpc.tls_key_file")
+			}
+		}
+	}
+
+	if rpcConfig.Unsafe {
+		if public {
+			add(rpcExposureRiskHigh,
+				"rpc.unsafe enables the unsafe routes (dial_seeds, dial_peers, unsafe_flush_mempool) on a public interface",
+				"set rpc.unsafe = false, or bind rpc.laddr to 127.0.0.1")
+		} else {
+			add(rpcExposureRiskLow,
+				"rpc.unsafe enables the unsafe routes (dial_seeds, dial_peers, unsafe_flush_mempool)",
+				"set rpc.unsafe = false unless the unsafe routes are needed")
+		}
+	}
+
+	for _, origin := range rpcConfig.CORSAllowedOrigins {
+		if origin == "*" && public {
+			add(rpcExposureRiskMedium,
+				"rpc.cors_allowed_origins allows any origin on a public interface",
+				"list the allowed origins explicitly in rpc.cors_allowed_origins")
+			break
+		}
+	}
+
+	if public && rpcConfig.MaxOpenConnections == 0 {
+		add(rpcExposureRiskMedium,
+			"rpc.max_open_connections is 0: the number of connections to a public interface is unlimited",
+			"set rpc.max_open_connections to a limit below the open files limit of the process")
+	}
+
+	if rpcConfig.GRPCListenAddress != "" && isPublicListenAddress(rpcConfig.GRPCListenAddress) {
+		add(rpcExposureRiskHigh,
+			fmt.Sprintf("rpc.grpc_laddr %q is bound to a public interface without TLS nor authentication",
+				rpcConfig.GRPCListenAddress),
+			"bind rpc.grpc_laddr to 127.0.0.1, or leave it empty to disable the gRPC server")
+	}
+
+	if rpcConfig.PprofListenAddress != "" {
+		if isPublicListenAddress(rpcConfig.PprofListenAddress) {
+			add(rpcExposureRiskHigh,
+				fmt.Sprintf("rpc.pprof_laddr %q exposes the profiling server on a public interface",
+					rpcConfig.PprofListenAddress),
+				"bind rpc.pprof_laddr to 127.0.0.1, or leave it empty to disable profiling")
+		} else {
+			add(rpcExposureRiskLow,
+				fmt.Sprintf("rpc.pprof_laddr %q enables the profiling server", rpcConfig.PprofListenAddress),
+				"leave rpc.pprof_laddr empty unless profiling is needed")
+		}
+	}
+
+	return append(append(high, medium...), low...)
+}
+
+// isPublicListenAddress returns true unless the given listen address is a
+// unix socket or is bound to a loopback interface. Host names other than
+// localhost are not resolved, and are considered public.
+func isPublicListenAddress(laddr string) bool {
+	proto, addr := tmnet.ProtocolAndAddress(laddr)
+	if proto == "unix" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || !ip.IsLoopback()
+}
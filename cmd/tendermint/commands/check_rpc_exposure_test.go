@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+)
+
+func TestIsPublicListenAddress(t *testing.T) {
+	testCases := map[string]bool{
+		"tcp://127.0.0.1:26657":        false,
+		"tcp://localhost:26657":        false,
+		"tcp://[::1]:26657":            false,
+		"unix:///tmp/tendermint.sock":  false,
+		"127.0.0.1:6060":               false,
+		"tcp://0.0.0.0:26657":          true,
+		"tcp://[::]:26657":             true,
+		"tcp://:26657":                 true,
+		"tcp://192.168.1.10:26657":     true,
+		"tcp://node.example.com:26657": true,
+	}
+	for laddr, public := range testCases {
+		require.Equal(t, public, isPublicListenAddress(laddr), laddr)
+	}
+}
+
+func TestCheckRPCExposure(t *testing.T) {
+	risks := func(findings []rpcExposureFinding) []string {
+		r := make([]string, len(findings))
+		for i, f := range findings {
+			r[i] = f.Risk
+		}
+		return r
+	}
+
+	rpcConfig := cfg.DefaultRPCConfig()
+	require.Empty(t, checkRPCExposure(rpcConfig))
+
+	rpcConfig.Unsafe = true
+	require.Equal(t, []string{rpcExposureRiskLow}, risks(checkRPCExposure(rpcConfig)))
+
+	rpcConfig.ListenAddress = "tcp://0.0.0.0:26657"
+	rpcConfig.CORSAllowedOrigins = []string{"*"}
+	require.Equal(t, []string{
+		rpcExposureRiskHigh, // no TLS
+		rpcExposureRiskHigh, // unsafe routes
+		rpcExposureRiskMedium,
+	}, risks(checkRPCExposure(rpcConfig)))
+
+	rpcConfig.Unsafe = false
+	rpcConfig.CORSAllowedOrigins = nil
+	rpcConfig.TLSCertFile = "server.crt"
+	rpcConfig.RootDir = t.TempDir()
+	require.Equal(t, []string{rpcExposureRiskHigh, rpcExposureRiskMedium},
+		risks(checkRPCExposure(rpcConfig)))
+
+	rpcConfig.TLSKeyFile = "server.key"
+	// The TLS files don't exist.
+	require.Equal(t, []string{rpcExposureRiskMedium, rpcExposureRiskMedium, rpcExposureRiskMedium},
+		risks(checkRPCExposure(rpcConfig)))
+
+	rpcConfig = cfg.DefaultRPCConfig()
+	rpcConfig.GRPCListenAddress = "tcp://0.0.0.0:26658"
+	rpcConfig.PprofListenAddress = "localhost:6060"
+	require.Equal(t, []string{rpcExposureRiskHigh, rpcExposureRiskLow}, risks(checkRPCExposure(rpcConfig)))
+}
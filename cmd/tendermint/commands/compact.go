@@ -4,6 +4,7 @@ import (
 	"errors"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -34,6 +35,10 @@ Currently, only GoLevelDB is supported.
 	},
 }
 
+// compactProgressInterval is the interval at which the progress of a
+// compaction is logged.
+var compactProgressInterval = 30 * time.Second
+
 func compactGoLevelDBs(rootDir string, logger log.Logger) {
 	dbNames := []string{"state", "blockstore"}
 	o := &opt.Options{
@@ -54,13 +59,68 @@ func compactGoLevelDBs(rootDir string, logger log.Logger) {
 			}
 			defer store.Close()
 
-			logger.Info("starting compaction...", "db", dbPath)
-
-			err = store.CompactRange(util.Range{Start: nil, Limit: nil})
-			if err != nil {
-				logger.Error("failed to compact tendermint db", "path", dbPath, "err", err)
-			}
+			compactGoLevelDB(store, dbPath, logger)
 		}()
 	}
 	wg.Wait()
 }
+
+// compactGoLevelDB compacts the whole store, logging the progress of the
+// compaction every compactProgressInterval, and a summary once it's done.
+func compactGoLevelDB(store *leveldb.DB, dbPath string, logger log.Logger) {
+	before := goLevelDBStats(store, dbPath, logger)
+	start := time.Now()
+	logger.Info("starting compaction...", "db", dbPath, "size", before.LevelSizes.Sum())
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(compactProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				stats := goLevelDBStats(store, dbPath, logger)
+				logger.Info("compaction in progress",
+					"db", dbPath,
+					"elapsed", time.Since(start).Round(time.Second),
+					// the bytes written by compactions, an approximation of the
+					// bytes compacted so far
+					"compacted", stats.LevelWrite.Sum()-before.LevelWrite.Sum(),
+				)
+			}
+		}
+	}()
+
+	err := store.CompactRange(util.Range{Start: nil, Limit: nil})
+	close(done)
+	wg.Wait()
+	if err != nil {
+		logger.Error("failed to compact tendermint db", "path", dbPath, "err", err,
+			"elapsed", time.Since(start).Round(time.Millisecond))
+		return
+	}
+
+	after := goLevelDBStats(store, dbPath, logger)
+	logger.Info("compaction completed",
+		"db", dbPath,
+		"duration", time.Since(start).Round(time.Millisecond),
+		"size_before", before.LevelSizes.Sum(),
+		"size_after", after.LevelSizes.Sum(),
+	)
+}
+
+// goLevelDBStats returns the stats of the store, or empty stats if they are
+// not available.
+func goLevelDBStats(store *leveldb.DB, dbPath string, logger log.Logger) *leveldb.DBStats {
+	stats := new(leveldb.DBStats)
+	if err := store.Stats(stats); err != nil {
+		logger.Error("failed to get the stats of tendermint db", "path", dbPath, "err", err)
+		return new(leveldb.DBStats)
+	}
+	return stats
+}
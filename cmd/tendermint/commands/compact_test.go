@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestCompactGoLevelDBs(t *testing.T) {
+	rootDir := t.TempDir()
+	for _, dbName := range []string{"state", "blockstore"} {
+		db, err := leveldb.OpenFile(filepath.Join(rootDir, "data", dbName+".db"), nil)
+		require.NoError(t, err)
+		for i := 0; i < 1000; i++ {
+			require.NoError(t, db.Put([]byte(fmt.Sprintf("key-%d", i)), bytes.Repeat([]byte{byte(i)}, 100), nil))
+		}
+		require.NoError(t, db.Close())
+	}
+
+	interval := compactProgressInterval
+	compactProgressInterval = time.Millisecond
+	defer func() { compactProgressInterval = interval }()
+
+	var buf bytes.Buffer
+	compactGoLevelDBs(rootDir, log.NewTMLogger(log.NewSyncWriter(&buf)))
+
+	out := buf.String()
+	require.NotContains(t, out, "failed")
+	for _, dbName := range []string{"state", "blockstore"} {
+		var summary string
+		for _, line := range strings.Split(out, "\n") {
+			if strings.Contains(line, "compaction completed") && strings.Contains(line, dbName+".db") {
+				summary = line
+			}
+		}
+		require.NotEmpty(t, summary, "no summary for %s in:\n%s", dbName, out)
+		require.Contains(t, summary, "duration=")
+		require.Contains(t, summary, "size_before=")
+		require.Contains(t, summary, "size_after=")
+	}
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/state"
+)
+
+// ConsensusParamsHistoryCmd prints the changes of the consensus params stored
+// in the state store.
+var ConsensusParamsHistoryCmd = &cobra.Command{
+	Use:   "consensus-params-history",
+	Short: "Export the history of the consensus params changes as JSON",
+	Long: `
+consensus-params-history is an offline tool which walks the consensus params
+stored in the state store and prints, as JSON and in chronological order, each
+set of params along with the height from which it took effect.
+
+The history starts at the earliest height retained by the node (after pruning),
+so the first entry holds the params in effect at that height, which may have
+been set earlier. The last entry may take effect at the height following the
+latest block, as the params of the next height are known once a block has been
+committed.
+
+The node should be stopped before running this command.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bs, ss, err := loadStateAndBlockStore(config)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = bs.Close()
+			_ = ss.Close()
+		}()
+
+		st, err := ss.Load()
+		if err != nil {
+			return err
+		}
+		if st.IsEmpty() {
+			return errors.New("no state found")
+		}
+
+		from := bs.Base()
+		if from < st.InitialHeight {
+			from = st.InitialHeight
+		}
+		history, err := consensusParamsHistory(ss, from, st.LastBlockHeight+1)
+		if err != nil {
+			return err
+		}
+
+		bz, err := json.MarshalIndent(history, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bz))
+		return nil
+	},
+}
+
+// ConsensusParamsChange is a set of consensus params, and the height from which
+// it took effect.
+type ConsensusParamsChange struct {
+	Height          int64                   `json:"height"`
+	ConsensusParams tmproto.ConsensusParams `json:"consensus_params"`
+}
+
+// consensusParamsHistory returns the params in effect at height from, followed
+// by every change of the params up to height to (inclusive).
+func consensusParamsHistory(ss state.Store, from, to int64) ([]ConsensusParamsChange, error) {
+	var history []ConsensusParamsChange
+	for height := from; height <= to; height++ {
+		params, err := ss.LoadConsensusParams(height)
+		if err != nil {
+			return nil, fmt.Errorf("loading the consensus params of height %d: %w", height, err)
+		}
+		if len(history) > 0 && history[len(history)-1].ConsensusParams.Equal(&params) {
+			continue
+		}
+		history = append(history, ConsensusParamsChange{Height: height, ConsensusParams: params})
+	}
+	return history, nil
+}
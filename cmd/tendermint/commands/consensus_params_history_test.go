@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/state/mocks"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestConsensusParamsHistory(t *testing.T) {
+	initial := *types.DefaultConsensusParams()
+	changed := initial
+	changed.Block.MaxBytes = 1024
+
+	mockStateStore := &mocks.Store{}
+	for h := int64(2); h <= 6; h++ {
+		params := initial
+		if h >= 4 && h < 6 {
+			params = changed
+		}
+		mockStateStore.On("LoadConsensusParams", h).Return(params, nil)
+	}
+	mockStateStore.On("LoadConsensusParams", int64(1)).Return(tmproto.ConsensusParams{}, errors.New("pruned"))
+
+	history, err := consensusParamsHistory(mockStateStore, 2, 6)
+	require.NoError(t, err)
+	require.Equal(t, []ConsensusParamsChange{
+		{Height: 2, ConsensusParams: initial},
+		{Height: 4, ConsensusParams: changed},
+		{Height: 6, ConsensusParams: initial},
+	}, history)
+
+	_, err = consensusParamsHistory(mockStateStore, 1, 6)
+	require.Error(t, err)
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/p2p/pex"
+)
+
+var (
+	exportAddrBookFile     string
+	exportAddrBookGood     bool
+	exportAddrBookRoutable bool
+	exportAddrBookFormat   string
+	exportAddrBookMax      int
+)
+
+func init() {
+	ExportAddrBookCmd.Flags().StringVar(&exportAddrBookFile, "addrbook", "",
+		"path to the address book file (defaults to the one of the node's configuration)")
+	ExportAddrBookCmd.Flags().BoolVar(&exportAddrBookGood, "good", false,
+		"only export the addresses at which a peer has successfully been connected to")
+	ExportAddrBookCmd.Flags().BoolVar(&exportAddrBookRoutable, "routable", false,
+		"only export the addresses which are routable on the public internet")
+	ExportAddrBookCmd.Flags().StringVar(&exportAddrBookFormat, "format", "list",
+		"output format: list (comma-separated addresses), persistent_peers or seeds (config.toml line)")
+	ExportAddrBookCmd.Flags().IntVar(&exportAddrBookMax, "max", 0,
+		"maximum number of addresses exported, the most recently successful first (0 exports all of them)")
+}
+
+// ExportAddrBookCmd exports the peer addresses of the address book.
+var ExportAddrBookCmd = &cobra.Command{
+	Use:   "export-addrbook",
+	Short: "Export the peer addresses of the address book",
+	Long: `
+export-addrbook reads the address book file of the node and prints the peer
+addresses it holds, in the format of the persistent_peers and seeds settings,
+so that they can be used to bootstrap new nodes. The node doesn't need to be
+running. Banned addresses are not stored in the address book, and therefore
+never exported.
+
+The addresses at which a peer has successfully been connected to come first,
+the most recently connected first.
+`,
+	Example: `
+	tendermint export-addrbook --good
+	tendermint export-addrbook --good --routable --format persistent_peers --max 20
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch exportAddrBookFormat {
+		case "list", "persistent_peers", "seeds":
+		default:
+			return fmt.Errorf("%w: unknown format %q (must be list, persistent_peers or seeds)",
+				ErrInvalidRequest, exportAddrBookFormat)
+		}
+		if exportAddrBookMax < 0 {
+			return fmt.Errorf("%w: max can't be negative", ErrInvalidRequest)
+		}
+
+		path := exportAddrBookFile
+		if path == "" {
+			path = config.P2P.AddrBookFile()
+		}
+		entries, err := pex.ReadAddrBookFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the address book: %w", err)
+		}
+
+		addrs := exportedAddrs(entries, exportAddrBookGood, exportAddrBookRoutable, exportAddrBookMax)
+		list := strings.Join(addrs, ",")
+		if exportAddrBookFormat == "list" {
+			fmt.Println(list)
+		} else {
+			fmt.Printf("%s = %q\n", exportAddrBookFormat, list)
+		}
+		logger.Info("Exported addresses", "exported", len(addrs), "total", len(entries), "addrbook", path)
+		return nil
+	},
+}
+
+// exportedAddrs returns the addresses of the entries matching the filters, the
+// good ones first, then the most recently successful first.
+func exportedAddrs(entries []pex.AddrBookEntry, goodOnly, routableOnly bool, max int) []string {
+	selected := make([]pex.AddrBookEntry, 0, len(entries))
+	for _, e := range entries {
+		if (goodOnly && !e.Good) || (routableOnly && !e.Addr.Routable()) {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if selected[i].Good != selected[j].Good {
+			return selected[i].Good
+		}
+		if !selected[i].LastSuccess.Equal(selected[j].LastSuccess) {
+			return selected[i].LastSuccess.After(selected[j].LastSuccess)
+		}
+		return selected[i].Addr.String() < selected[j].Addr.String()
+	})
+	if max > 0 && len(selected) > max {
+		selected = selected[:max]
+	}
+
+	addrs := make([]string, len(selected))
+	for i, e := range selected {
+		addrs[i] = e.Addr.String()
+	}
+	return addrs
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/pex"
+)
+
+func TestExportedAddrs(t *testing.T) {
+	now := time.Now()
+	entry := func(i int, host string, good bool, lastSuccess time.Time) pex.AddrBookEntry {
+		addr, err := p2p.NewNetAddressString(fmt.Sprintf("%s@%s:26656", strings.Repeat(fmt.Sprint(i), 40), host))
+		require.NoError(t, err)
+		return pex.AddrBookEntry{Addr: addr, Good: good, LastSuccess: lastSuccess}
+	}
+	entries := []pex.AddrBookEntry{
+		entry(1, "1.2.3.4", false, time.Time{}),
+		entry(2, "10.0.0.1", true, now.Add(-time.Minute)),
+		entry(3, "5.6.7.8", true, now.Add(-time.Hour)),
+		entry(4, "9.9.9.9", true, now),
+	}
+	str := func(idx ...int) []string {
+		addrs := make([]string, len(idx))
+		for i, j := range idx {
+			addrs[i] = entries[j].Addr.String()
+		}
+		return addrs
+	}
+
+	assert.Equal(t, str(3, 1, 2, 0), exportedAddrs(entries, false, false, 0))
+	assert.Equal(t, str(3, 1, 2), exportedAddrs(entries, true, false, 0))
+	assert.Equal(t, str(3, 2), exportedAddrs(entries, true, true, 0))
+	assert.Equal(t, str(3, 2, 0), exportedAddrs(entries, false, true, 0))
+	assert.Equal(t, str(3, 1), exportedAddrs(entries, false, false, 2))
+}
@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+var (
+	feeReportNode    string
+	feeReportFrom    int64
+	feeReportTo      int64
+	feeReportFeeAttr string
+	feeReportJSON    bool
+)
+
+// feeReportPerPage is the number of results requested per tx_search call.
+const feeReportPerPage = 100
+
+// FeeExtractor extracts the fees paid by a transaction from its result, by
+// denomination. Fees are app-specific: applications can give their own
+// extractor to NewFeeReportCmd.
+type FeeExtractor interface {
+	ExtractFees(*abci.TxResult) (map[string]*big.Int, error)
+}
+
+// EventFeeExtractor extracts the fees of a transaction from the values of the
+// event attribute with the given composite key (e.g. "tx.fee"). A value is a
+// comma-separated list of amounts followed by their denomination, e.g.
+// "100uatom,5stake".
+type EventFeeExtractor struct {
+	CompositeKey string
+}
+
+// ExtractFees implements FeeExtractor.
+func (e EventFeeExtractor) ExtractFees(r *abci.TxResult) (map[string]*big.Int, error) {
+	fees := make(map[string]*big.Int)
+	for _, event := range r.Result.Events {
+		for _, attr := range event.Attributes {
+			if event.Type+"."+string(attr.Key) != e.CompositeKey {
+				continue
+			}
+			if err := addFees(fees, string(attr.Value)); err != nil {
+				return nil, fmt.Errorf("invalid %s attribute: %w", e.CompositeKey, err)
+			}
+		}
+	}
+	return fees, nil
+}
+
+// addFees adds the amounts of a comma-separated list like "100uatom,5stake"
+// to fees.
+func addFees(fees map[string]*big.Int, value string) error {
+	for _, coin := range strings.Split(value, ",") {
+		coin = strings.TrimSpace(coin)
+		if coin == "" {
+			continue
+		}
+		i := strings.IndexFunc(coin, func(r rune) bool { return r < '0' || r > '9' })
+		if i == 0 {
+			return fmt.Errorf("%q doesn't start with an amount", coin)
+		}
+		amount, denom := coin, ""
+		if i > 0 {
+			amount, denom = coin[:i], coin[i:]
+		}
+		n, ok := new(big.Int).SetString(amount, 10)
+		if !ok {
+			return fmt.Errorf("invalid amount %q", amount)
+		}
+		if fees[denom] == nil {
+			fees[denom] = new(big.Int)
+		}
+		fees[denom].Add(fees[denom], n)
+	}
+	return nil
+}
+
+// NewFeeReportCmd returns the command reporting the gas and fees of the
+// transactions of a range of heights. If extractor is nil, the fees are read
+// from the event attribute given by --fee-attr.
+func NewFeeReportCmd(extractor FeeExtractor) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-report",
+		Short: "Report the gas and fees of the transactions of a range of heights",
+		Long: `
+fee-report aggregates the gas wanted and used, and the fees, of the
+transactions included between the --from and --to heights, using the
+transaction index of a running node. The totals of each height are printed as
+soon as they are computed, followed by the totals of the range. With --json,
+each line is a JSON object, for charts.
+
+Fees are app-specific. By default, they are read from the event attribute
+given by --fee-attr, whose values are lists of amounts followed by their
+denomination such as "100uatom,5stake". Without --fee-attr, only gas is
+reported.
+`,
+		Example: `
+	tendermint fee-report --from 100 --to 200
+	tendermint fee-report --from 100 --fee-attr tx.fee --json
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if feeReportFrom <= 0 {
+				return fmt.Errorf("%w: from must be positive", ErrInvalidRequest)
+			}
+			if feeReportTo != 0 && feeReportTo < feeReportFrom {
+				return fmt.Errorf("%w: to (%d) can't be lower than from (%d)",
+					ErrInvalidRequest, feeReportTo, feeReportFrom)
+			}
+
+			client, err := rpchttp.New(feeReportNode, "/websocket")
+			if err != nil {
+				return fmt.Errorf("failed to create new http client: %w", err)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			to := feeReportTo
+			if to == 0 {
+				status, err := client.Status(ctx)
+				if err != nil {
+					return fmt.Errorf("fetching the node status: %w", err)
+				}
+				to = status.SyncInfo.LatestBlockHeight
+			}
+
+			ex := extractor
+			if ex == nil && feeReportFeeAttr != "" {
+				ex = EventFeeExtractor{CompositeKey: feeReportFeeAttr}
+			}
+
+			printTotals := printFeeTotals
+			if feeReportJSON {
+				printTotals = printFeeTotalsJSON
+			}
+			total, err := feeReport(ctx, client, feeReportFrom, to, ex, func(t *feeTotals) error {
+				return printTotals("height", t)
+			})
+			if err != nil {
+				return err
+			}
+			return printTotals("total", total)
+		},
+	}
+
+	cmd.Flags().StringVar(&feeReportNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	cmd.Flags().Int64Var(&feeReportFrom, "from", 1, "the first height of the range")
+	cmd.Flags().Int64Var(&feeReportTo, "to", 0, "the last height of the range (0 is the latest height)")
+	cmd.Flags().StringVar(&feeReportFeeAttr, "fee-attr", "",
+		"the composite key of the event attribute holding the fees (e.g. tx.fee)")
+	cmd.Flags().BoolVar(&feeReportJSON, "json", false, "output the report as JSON lines")
+	return cmd
+}
+
+// txSearcher searches the transaction index of a node.
+type txSearcher interface {
+	TxSearch(ctx context.Context, query string, prove bool, page, perPage *int,
+		orderBy string) (*ctypes.ResultTxSearch, error)
+}
+
+// feeTotals are the gas and fees of the transactions of a height, or of a
+// range of heights.
+type feeTotals struct {
+	Height    int64               `json:"height,omitempty"`
+	Txs       int                 `json:"txs"`
+	GasWanted int64               `json:"gas_wanted"`
+	GasUsed   int64               `json:"gas_used"`
+	Fees      map[string]*big.Int `json:"fees,omitempty"`
+}
+
+func (t *feeTotals) add(o *feeTotals) {
+	t.Txs += o.Txs
+	t.GasWanted += o.GasWanted
+	t.GasUsed += o.GasUsed
+	for denom, amount := range o.Fees {
+		if t.Fees == nil {
+			t.Fees = make(map[string]*big.Int)
+		}
+		if t.Fees[denom] == nil {
+			t.Fees[denom] = new(big.Int)
+		}
+		t.Fees[denom].Add(t.Fees[denom], amount)
+	}
+}
+
+// feeReport computes the totals of each height between from and to, passing
+// them to emit one height at a time so that the results of the range aren't
+// buffered, and returns the totals of the range. The fees are only computed
+// if extractor isn't nil.
+func feeReport(
+	ctx context.Context,
+	client txSearcher,
+	from, to int64,
+	extractor FeeExtractor,
+	emit func(*feeTotals) error,
+) (*feeTotals, error) {
+	total := &feeTotals{}
+	for height := from; height <= to; height++ {
+		t := &feeTotals{Height: height}
+		query := fmt.Sprintf("tx.height = %d", height)
+		for page, seen := 1, 0; ; page++ {
+			perPage := feeReportPerPage
+			res, err := client.TxSearch(ctx, query, false, &page, &perPage, "asc")
+			if err != nil {
+				return nil, fmt.Errorf("searching the txs of height %d: %w", height, err)
+			}
+			for _, tx := range res.Txs {
+				t.Txs++
+				t.GasWanted += tx.TxResult.GasWanted
+				t.GasUsed += tx.TxResult.GasUsed
+				if extractor == nil {
+					continue
+				}
+				fees, err := extractor.ExtractFees(&abci.TxResult{
+					Height: tx.Height,
+					Index:  tx.Index,
+					Tx:     tx.Tx,
+					Result: tx.TxResult,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("extracting the fees of tx %X: %w", tx.Hash, err)
+				}
+				t.add(&feeTotals{Fees: fees})
+			}
+			seen += len(res.Txs)
+			if len(res.Txs) == 0 || seen >= res.TotalCount {
+				break
+			}
+		}
+		if err := emit(t); err != nil {
+			return nil, err
+		}
+		total.add(t)
+	}
+	return total, nil
+}
+
+func printFeeTotals(kind string, t *feeTotals) error {
+	line := fmt.Sprintf("%d txs, gas wanted %d, gas used %d", t.Txs, t.GasWanted, t.GasUsed)
+	denoms := make([]string, 0, len(t.Fees))
+	for denom := range t.Fees {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+	fees := make([]string, len(denoms))
+	for i, denom := range denoms {
+		fees[i] = t.Fees[denom].String() + denom
+	}
+	if len(fees) > 0 {
+		line += ", fees " + strings.Join(fees, ",")
+	}
+	if kind == "height" {
+		fmt.Printf("height %d: %s\n", t.Height, line)
+	} else {
+		fmt.Printf("total: %s\n", line)
+	}
+	return nil
+}
+
+func printFeeTotalsJSON(kind string, t *feeTotals) error {
+	bz, err := json.Marshal(struct {
+		Kind string `json:"kind"`
+		*feeTotals
+	}{kind, t})
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bz))
+	return nil
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// fakeTxSearcher serves the txs of each height, a page at a time.
+type fakeTxSearcher struct {
+	txs   map[int64][]*ctypes.ResultTx
+	calls int
+}
+
+func (s *fakeTxSearcher) TxSearch(
+	ctx context.Context,
+	query string,
+	prove bool,
+	page, perPage *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	s.calls++
+	var height int64
+	if _, err := fmt.Sscanf(query, "tx.height = %d", &height); err != nil {
+		return nil, err
+	}
+	txs := s.txs[height]
+	start := (*page - 1) * *perPage
+	if start > len(txs) {
+		start = len(txs)
+	}
+	end := start + *perPage
+	if end > len(txs) {
+		end = len(txs)
+	}
+	return &ctypes.ResultTxSearch{Txs: txs[start:end], TotalCount: len(txs)}, nil
+}
+
+func feeTx(height int64, index uint32, gasWanted, gasUsed int64, fee string) *ctypes.ResultTx {
+	tx := types.Tx(fmt.Sprintf("tx-%d-%d", height, index))
+	return &ctypes.ResultTx{
+		Hash:   tx.Hash(),
+		Height: height,
+		Index:  index,
+		Tx:     tx,
+		TxResult: abci.ResponseDeliverTx{
+			GasWanted: gasWanted,
+			GasUsed:   gasUsed,
+			Events: []abci.Event{{Type: "tx", Attributes: []abci.EventAttribute{
+				{Key: []byte("fee"), Value: []byte(fee), Index: true},
+			}}},
+		},
+	}
+}
+
+func TestFeeReport(t *testing.T) {
+	searcher := &fakeTxSearcher{txs: map[int64][]*ctypes.ResultTx{
+		1: {feeTx(1, 0, 10, 5, "100uatom")},
+		3: {feeTx(3, 0, 20, 20, "1uatom,2stake")},
+	}}
+	for i := uint32(1); i <= feeReportPerPage+1; i++ {
+		searcher.txs[3] = append(searcher.txs[3], feeTx(3, i, 1, 1, ""))
+	}
+
+	var heights []*feeTotals
+	total, err := feeReport(context.Background(), searcher, 1, 3, EventFeeExtractor{CompositeKey: "tx.fee"},
+		func(t *feeTotals) error {
+			heights = append(heights, t)
+			return nil
+		})
+	require.NoError(t, err)
+	// height 3 takes 2 pages
+	assert.Equal(t, 4, searcher.calls)
+
+	require.Len(t, heights, 3)
+	assert.Equal(t, &feeTotals{Height: 1, Txs: 1, GasWanted: 10, GasUsed: 5,
+		Fees: map[string]*big.Int{"uatom": big.NewInt(100)}}, heights[0])
+	assert.Equal(t, &feeTotals{Height: 2}, heights[1])
+	assert.Equal(t, &feeTotals{Height: 3, Txs: feeReportPerPage + 2, GasWanted: 20 + feeReportPerPage + 1,
+		GasUsed: 20 + feeReportPerPage + 1,
+		Fees:    map[string]*big.Int{"uatom": big.NewInt(1), "stake": big.NewInt(2)}}, heights[2])
+	assert.Equal(t, &feeTotals{Txs: feeReportPerPage + 3, GasWanted: 30 + feeReportPerPage + 1,
+		GasUsed: 25 + feeReportPerPage + 1,
+		Fees:    map[string]*big.Int{"uatom": big.NewInt(101), "stake": big.NewInt(2)}}, total)
+
+	// without an extractor, only gas is reported
+	total, err = feeReport(context.Background(), searcher, 1, 1, nil, func(*feeTotals) error { return nil })
+	require.NoError(t, err)
+	assert.Nil(t, total.Fees)
+
+	searcher.txs[1] = []*ctypes.ResultTx{feeTx(1, 0, 1, 1, "uatom")}
+	_, err = feeReport(context.Background(), searcher, 1, 1, EventFeeExtractor{CompositeKey: "tx.fee"},
+		func(*feeTotals) error { return nil })
+	assert.Error(t, err)
+}
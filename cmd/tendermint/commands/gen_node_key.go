@@ -2,9 +2,13 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	tmos "github.com/tendermint/tendermint/libs/os"
 	"github.com/tendermint/tendermint/p2p"
 )
@@ -15,20 +19,54 @@ var GenNodeKeyCmd = &cobra.Command{
 	Use:     "gen-node-key",
 	Aliases: []string{"gen_node_key"},
 	Short:   "Generate a node key for this node and print its ID",
-	PreRun:  deprecateSnakeCase,
-	RunE:    genNodeKey,
+	Example: `
+	tendermint gen-node-key
+	tendermint gen-node-key --out ./node1/node_key.json --print-id=false
+	`,
+	PreRun: deprecateSnakeCase,
+	RunE:   genNodeKey,
+}
+
+var (
+	genNodeKeyOut     string
+	genNodeKeyPrintID bool
+	genNodeKeyForce   bool
+)
+
+func init() {
+	GenNodeKeyCmd.Flags().StringVar(&genNodeKeyOut, "out", "",
+		"path to write the node key to (default the node_key file of the config)")
+	GenNodeKeyCmd.Flags().BoolVar(&genNodeKeyPrintID, "print-id", true,
+		"print the ID of the generated node key")
+	GenNodeKeyCmd.Flags().BoolVar(&genNodeKeyForce, "force", false,
+		"overwrite the node key if it already exists")
 }
 
 func genNodeKey(cmd *cobra.Command, args []string) error {
-	nodeKeyFile := config.NodeKeyFile()
-	if tmos.FileExists(nodeKeyFile) {
-		return fmt.Errorf("node key at %s already exists", nodeKeyFile)
+	nodeKeyFile := genNodeKeyOut
+	if nodeKeyFile == "" {
+		nodeKeyFile = config.NodeKeyFile()
+	}
+	return writeNodeKey(os.Stdout, nodeKeyFile, genNodeKeyPrintID, genNodeKeyForce)
+}
+
+// writeNodeKey generates a node key and saves it to nodeKeyFile, which must not
+// exist unless force is set. If printID is set, the ID of the key is printed
+// to w.
+func writeNodeKey(w io.Writer, nodeKeyFile string, printID, force bool) error {
+	if tmos.FileExists(nodeKeyFile) && !force {
+		return fmt.Errorf("node key at %s already exists (use --force to overwrite it)", nodeKeyFile)
+	}
+	if err := tmos.EnsureDir(filepath.Dir(nodeKeyFile), 0700); err != nil {
+		return err
 	}
 
-	nodeKey, err := p2p.LoadOrGenNodeKey(nodeKeyFile)
-	if err != nil {
+	nodeKey := &p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}
+	if err := nodeKey.SaveAs(nodeKeyFile); err != nil {
 		return err
 	}
-	fmt.Println(nodeKey.ID())
+	if printID {
+		fmt.Fprintln(w, nodeKey.ID())
+	}
 	return nil
 }
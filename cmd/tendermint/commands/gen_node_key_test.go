@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/p2p"
+)
+
+func TestWriteNodeKey(t *testing.T) {
+	nodeKeyFile := filepath.Join(t.TempDir(), "node1", "node_key.json")
+
+	// the printed ID is the one of the saved key
+	var buf bytes.Buffer
+	require.NoError(t, writeNodeKey(&buf, nodeKeyFile, true, false))
+	nodeKey, err := p2p.LoadNodeKey(nodeKeyFile)
+	require.NoError(t, err)
+	require.Equal(t, string(nodeKey.ID()), strings.TrimSpace(buf.String()))
+
+	// an existing key is only overwritten with force
+	buf.Reset()
+	err = writeNodeKey(&buf, nodeKeyFile, true, false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+	reloaded, err := p2p.LoadNodeKey(nodeKeyFile)
+	require.NoError(t, err)
+	require.Equal(t, nodeKey.ID(), reloaded.ID())
+
+	require.NoError(t, writeNodeKey(&buf, nodeKeyFile, false, true))
+	require.Empty(t, buf.String())
+	reloaded, err = p2p.LoadNodeKey(nodeKeyFile)
+	require.NoError(t, err)
+	require.NotEqual(t, nodeKey.ID(), reloaded.ID())
+}
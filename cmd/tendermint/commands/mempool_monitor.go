@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+var (
+	mempoolMonitorNode          string
+	mempoolMonitorOut           string
+	mempoolMonitorInterval      time.Duration
+	mempoolMonitorFlushInterval time.Duration
+)
+
+// mempoolMonitorHeader is the header of the CSV files written by
+// mempool-monitor.
+var mempoolMonitorHeader = []string{"time", "n_txs", "total_bytes"}
+
+func init() {
+	MempoolMonitorCmd.Flags().StringVar(&mempoolMonitorNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	MempoolMonitorCmd.Flags().StringVar(&mempoolMonitorOut, "out", "",
+		"the CSV file to which the samples are appended (required)")
+	MempoolMonitorCmd.Flags().DurationVar(&mempoolMonitorInterval, "interval", time.Second,
+		"the interval between two samples")
+	MempoolMonitorCmd.Flags().DurationVar(&mempoolMonitorFlushInterval, "flush-interval", 10*time.Second,
+		"the interval at which the samples are flushed to the file")
+}
+
+// MempoolMonitorCmd periodically samples the size of the mempool of a node
+// into a CSV file.
+var MempoolMonitorCmd = &cobra.Command{
+	Use:   "mempool-monitor",
+	Short: "Sample the size of the mempool of a node into a CSV file",
+	Long: `
+mempool-monitor polls the RPC server of a running node at the given interval,
+and appends the number of transactions of its mempool and their total size in
+bytes to a CSV file, along with the time of the sample. It is a lightweight way
+to investigate the capacity of a node, without a tracing pipeline.
+
+The header of the file is only written if the file is empty, so that a
+monitoring can be resumed in the same file. Samples are flushed to the file at
+the given flush interval, and when the command is interrupted. When the node
+can't be reached, no sample is written, and the monitoring goes on until it's
+reachable again.
+`,
+	Example: `
+	tendermint mempool-monitor --out mempool.csv
+	tendermint mempool-monitor --out mempool.csv --interval 100ms --flush-interval 1s
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if mempoolMonitorOut == "" {
+			return fmt.Errorf("%w: out is required", ErrInvalidRequest)
+		}
+		if mempoolMonitorInterval <= 0 || mempoolMonitorFlushInterval <= 0 {
+			return fmt.Errorf("%w: interval and flush-interval must be positive", ErrInvalidRequest)
+		}
+
+		client, err := rpchttp.New(mempoolMonitorNode, "/websocket")
+		if err != nil {
+			return fmt.Errorf("failed to create new http client: %w", err)
+		}
+
+		f, err := os.OpenFile(mempoolMonitorOut, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		logger.Info("Monitoring the mempool", "node", mempoolMonitorNode, "out", mempoolMonitorOut)
+		return monitorMempool(ctx, client, f, info.Size() == 0, mempoolMonitorInterval, mempoolMonitorFlushInterval)
+	},
+}
+
+// mempoolSizer returns the size of the mempool of a node.
+type mempoolSizer interface {
+	NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUnconfirmedTxs, error)
+}
+
+// monitorMempool samples the size of the mempool every interval into w as
+// CSV, until ctx is done. The samples are flushed every flushInterval, and
+// before returning.
+func monitorMempool(
+	ctx context.Context,
+	client mempoolSizer,
+	w io.Writer,
+	writeHeader bool,
+	interval, flushInterval time.Duration,
+) error {
+	cw := csv.NewWriter(w)
+	if writeHeader {
+		if err := cw.Write(mempoolMonitorHeader); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lastFlush := time.Now()
+	reachable := true
+	for {
+		res, err := client.NumUnconfirmedTxs(ctx)
+		now := time.Now()
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// interrupted during the request
+		case err != nil:
+			if reachable {
+				logger.Error("Failed to sample the mempool", "err", err)
+				reachable = false
+			}
+		default:
+			if !reachable {
+				logger.Info("Node reachable again")
+				reachable = true
+			}
+			if err := cw.Write([]string{
+				now.UTC().Format(time.RFC3339Nano),
+				strconv.Itoa(res.Total),
+				strconv.FormatInt(res.TotalBytes, 10),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if now.Sub(lastFlush) >= flushInterval {
+			if err := flushCSV(cw); err != nil {
+				return err
+			}
+			lastFlush = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return flushCSV(cw)
+		case <-ticker.C:
+		}
+	}
+}
+
+func flushCSV(cw *csv.Writer) error {
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// flakyMempoolSizer fails its second request, and cancels the monitoring after
+// its fourth.
+type flakyMempoolSizer struct {
+	calls  int
+	cancel context.CancelFunc
+}
+
+func (c *flakyMempoolSizer) NumUnconfirmedTxs(context.Context) (*ctypes.ResultUnconfirmedTxs, error) {
+	c.calls++
+	if c.calls == 4 {
+		c.cancel()
+	}
+	if c.calls == 2 {
+		return nil, errors.New("connection refused")
+	}
+	return &ctypes.ResultUnconfirmedTxs{Total: c.calls, TotalBytes: int64(100 * c.calls)}, nil
+}
+
+func TestMonitorMempool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &flakyMempoolSizer{cancel: cancel}
+
+	var buf bytes.Buffer
+	require.NoError(t, monitorMempool(ctx, client, &buf, true, time.Millisecond, time.Hour))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, mempoolMonitorHeader, records[0])
+	// the failed sample is skipped
+	for i, expected := range [][]string{{"1", "100"}, {"3", "300"}, {"4", "400"}} {
+		row := records[i+1]
+		_, err := time.Parse(time.RFC3339Nano, row[0])
+		require.NoError(t, err)
+		assert.Equal(t, expected, row[1:])
+	}
+
+	// resuming in the same file doesn't repeat the header
+	buf.Reset()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, monitorMempool(ctx, &flakyMempoolSizer{cancel: cancel, calls: 2}, &buf, false,
+		time.Millisecond, time.Hour))
+	records, err = csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{"3", "300"}, records[0][1:])
+}
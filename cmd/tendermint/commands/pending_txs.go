@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+)
+
+var (
+	pendingTxsNode      string
+	pendingTxsOlderThan time.Duration
+	pendingTxsLimit     int
+)
+
+func init() {
+	PendingTxsCmd.Flags().StringVar(&pendingTxsNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	PendingTxsCmd.Flags().DurationVar(&pendingTxsOlderThan, "older-than", 0,
+		"only list the transactions added to the mempool at least this long ago")
+	PendingTxsCmd.Flags().IntVar(&pendingTxsLimit, "limit", 30,
+		"the maximum number of transactions listed (max 100)")
+}
+
+// PendingTxsCmd lists the transactions of the mempool of a node, oldest first.
+var PendingTxsCmd = &cobra.Command{
+	Use:   "pending-txs",
+	Short: "List the transactions pending in the mempool, oldest first",
+	Long: `
+pending-txs lists the transactions waiting in the mempool of a running node,
+oldest first, along with their age, priority and size, and the height at which
+they were added. Transactions which stay in the mempool for many blocks are
+likely never included, e.g. because their priority is too low.
+
+--older-than only lists the transactions added at least the given duration
+ago, with the precision of a second.
+`,
+	Example: `
+	tendermint pending-txs
+	tendermint pending-txs --older-than 10m --limit 100
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pendingTxsOlderThan < 0 {
+			return fmt.Errorf("%w: older-than can't be negative", ErrInvalidRequest)
+		}
+
+		client, err := rpchttp.New(pendingTxsNode, "/websocket")
+		if err != nil {
+			return fmt.Errorf("failed to create new http client: %w", err)
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		res, err := client.UnconfirmedTxsByAge(ctx, int64(pendingTxsOlderThan/time.Second), &pendingTxsLimit)
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range res.Txs {
+			fmt.Printf("%X age=%v height=%d priority=%d size=%d\n",
+				tx.Hash, tx.Age.Round(time.Second), tx.Height, tx.Priority, tx.Size)
+		}
+		fmt.Printf("%d of %d pending txs listed\n", res.Count, res.Total)
+		return nil
+	},
+}
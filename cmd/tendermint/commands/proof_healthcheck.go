@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	proofHealthcheckNode         string
+	proofHealthcheckInterval     time.Duration
+	proofHealthcheckHeight       int64
+	proofHealthcheckHeightOffset int64
+)
+
+func init() {
+	ProofHealthcheckCmd.Flags().StringVar(&proofHealthcheckNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	ProofHealthcheckCmd.Flags().DurationVar(&proofHealthcheckInterval, "interval", time.Minute,
+		"the interval between two checks")
+	ProofHealthcheckCmd.Flags().Int64Var(&proofHealthcheckHeight, "height", 0,
+		"check the proofs of this height only, instead of following the latest height")
+	ProofHealthcheckCmd.Flags().Int64Var(&proofHealthcheckHeightOffset, "height-offset", 0,
+		"check the proofs of this many blocks below the latest height")
+}
+
+// ProofHealthcheckCmd periodically requests and verifies the proof of a
+// transaction, to detect when the node stops serving valid proofs.
+var ProofHealthcheckCmd = &cobra.Command{
+	Use:   "proof-healthcheck",
+	Short: "Periodically request and verify transaction proofs from a node",
+	Long: `
+proof-healthcheck runs until it is interrupted and, at every interval, requests
+from the RPC server of a running node the proof of a transaction included at
+the latest provable height, and verifies it against the data hash of the block.
+The result of each check is logged, so that a broken proving path (e.g. after
+an upgrade) is noticed early.
+
+By default, the latest height is checked. --height-offset checks a height below
+the latest one, and --height always checks the same height. A height without
+transactions has nothing to prove: the check is skipped.
+`,
+	Example: `
+	tendermint proof-healthcheck --interval 30s
+	tendermint proof-healthcheck --height-offset 10 --node tcp://localhost:26657
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if proofHealthcheckInterval <= 0 {
+			return fmt.Errorf("%w: interval must be positive", ErrInvalidRequest)
+		}
+		if proofHealthcheckHeight < 0 || proofHealthcheckHeightOffset < 0 {
+			return fmt.Errorf("%w: height and height-offset can't be negative", ErrInvalidRequest)
+		}
+
+		client, err := rpchttp.New(proofHealthcheckNode, "/websocket")
+		if err != nil {
+			return fmt.Errorf("failed to create new http client: %w", err)
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ticker := time.NewTicker(proofHealthcheckInterval)
+		defer ticker.Stop()
+		for {
+			res, err := checkTxProof(ctx, client, proofHealthcheckHeight, proofHealthcheckHeightOffset)
+			switch {
+			case err != nil:
+				logger.Error("Proof healthcheck failed", "height", res.Height, "err", err)
+			case res.Skipped:
+				logger.Info("Proof healthcheck skipped: no transaction to prove", "height", res.Height)
+			default:
+				logger.Info("Proof healthcheck succeeded", "height", res.Height, "index", res.Index,
+					"hash", res.Hash, "latency", res.Latency)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// proofHealthcheckClient is the subset of the RPC client used by the proof
+// healthcheck.
+type proofHealthcheckClient interface {
+	rpcclient.StatusClient
+	rpcclient.SignClient
+}
+
+// txProofCheck is the outcome of a single proof healthcheck.
+type txProofCheck struct {
+	Height  int64
+	Index   int
+	Hash    []byte
+	Latency time.Duration
+	// Skipped is true if the checked block has no transaction.
+	Skipped bool
+}
+
+// checkTxProof requests the proof of a random transaction of the block at the
+// given height, or at offset blocks below the latest height if height is 0,
+// and verifies it against the data hash of the block. The height is clamped
+// to the heights retained by the node.
+func checkTxProof(
+	ctx context.Context,
+	client proofHealthcheckClient,
+	height, offset int64,
+) (txProofCheck, error) {
+	var check txProofCheck
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		return check, fmt.Errorf("fetching the node status: %w", err)
+	}
+	check.Height = height
+	if check.Height == 0 {
+		check.Height = status.SyncInfo.LatestBlockHeight - offset
+	}
+	if check.Height < status.SyncInfo.EarliestBlockHeight {
+		check.Height = status.SyncInfo.EarliestBlockHeight
+	}
+
+	block, err := client.Block(ctx, &check.Height)
+	if err != nil {
+		return check, fmt.Errorf("fetching the block: %w", err)
+	}
+	if !bytes.Equal(block.BlockID.Hash, block.Block.Hash()) {
+		return check, fmt.Errorf("block hash %X doesn't match the header hash %X", block.BlockID.Hash, block.Block.Hash())
+	}
+	txs := block.Block.Data.Txs
+	if len(txs) == 0 {
+		check.Skipped = true
+		return check, nil
+	}
+
+	check.Index = tmrand.Intn(len(txs))
+	tx := txs[check.Index]
+	check.Hash = types.TxHash(tx)
+
+	start := time.Now()
+	res, err := client.Tx(ctx, check.Hash, true)
+	check.Latency = time.Since(start)
+	if err != nil {
+		return check, fmt.Errorf("fetching the proof of tx %X: %w", check.Hash, err)
+	}
+	// the same tx may have been included again in a later block
+	if res.Height != check.Height {
+		return check, fmt.Errorf("tx %X is indexed at height %d", check.Hash, res.Height)
+	}
+	if !bytes.Equal(res.Proof.Data, tx) {
+		return check, errors.New("the proof is for a different tx")
+	}
+	if err := res.Proof.Validate(block.Block.DataHash); err != nil {
+		return check, fmt.Errorf("invalid proof of tx %X: %w", check.Hash, err)
+	}
+	return check, nil
+}
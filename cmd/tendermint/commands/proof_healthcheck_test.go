@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// txProofClient serves the blocks and tx proofs of a single block.
+type txProofClient struct {
+	proofHealthcheckClient
+	block    *types.Block
+	badProof bool
+}
+
+func (c txProofClient) Status(context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{
+		EarliestBlockHeight: c.block.Height,
+		LatestBlockHeight:   c.block.Height + 5,
+	}}, nil
+}
+
+func (c txProofClient) Block(_ context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	if *height != c.block.Height {
+		return nil, ErrHeightNotAvailable
+	}
+	return &ctypes.ResultBlock{BlockID: types.BlockID{Hash: c.block.Hash()}, Block: c.block}, nil
+}
+
+func (c txProofClient) Tx(_ context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	// the txs are indexed by their TxHash
+	index := -1
+	for i, tx := range c.block.Data.Txs {
+		if bytes.Equal(types.TxHash(tx), hash) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("tx %X not found", hash)
+	}
+	proof := c.block.Data.Txs.Proof(index)
+	if c.badProof {
+		proof.Proof.Index = (proof.Proof.Index + 1) % proof.Proof.Total
+	}
+	return &ctypes.ResultTx{Hash: hash, Height: c.block.Height, Index: uint32(index), Proof: proof}, nil
+}
+
+func TestCheckTxProof(t *testing.T) {
+	ctx := context.Background()
+	block := types.MakeBlock(10, types.Txs{types.Tx("a"), types.Tx("b"), types.Tx("c")}, nil, nil)
+	client := txProofClient{block: block}
+
+	// the offset is clamped to the earliest retained height
+	check, err := checkTxProof(ctx, client, 0, 100)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, check.Height)
+	require.False(t, check.Skipped)
+	require.Equal(t, types.TxHash(block.Data.Txs[check.Index]), check.Hash)
+
+	// the tx is looked up by the hash it is indexed with
+	types.RegisterTxHasher(func(tx types.Tx) []byte { return append([]byte("custom-"), tx...) })
+	t.Cleanup(func() { types.RegisterTxHasher(nil) })
+	check, err = checkTxProof(ctx, client, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, append([]byte("custom-"), block.Data.Txs[check.Index]...), check.Hash)
+	types.RegisterTxHasher(nil)
+
+	check, err = checkTxProof(ctx, client, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, check.Height)
+
+	client.badProof = true
+	_, err = checkTxProof(ctx, client, 10, 0)
+	require.Error(t, err)
+
+	// a block without txs has nothing to prove
+	client = txProofClient{block: types.MakeBlock(10, nil, nil, nil)}
+	check, err = checkTxProof(ctx, client, 10, 0)
+	require.NoError(t, err)
+	require.True(t, check.Skipped)
+}
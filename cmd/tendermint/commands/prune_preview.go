@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	prunePreviewKeepRecent   int64
+	prunePreviewRetainHeight int64
+)
+
+func init() {
+	PrunePreviewCmd.Flags().Int64Var(&prunePreviewKeepRecent, "keep-recent", 0,
+		"the number of most recent blocks to retain")
+	PrunePreviewCmd.Flags().Int64Var(&prunePreviewRetainHeight, "retain-height", 0,
+		"the lowest height to retain, as returned by the application on Commit")
+}
+
+// PrunePreviewCmd reports what pruning the block store to a given height would
+// remove, without removing anything.
+var PrunePreviewCmd = &cobra.Command{
+	Use:   "prune-preview",
+	Short: "Show which blocks pruning would remove, without pruning",
+	Long: `
+prune-preview is an offline tool which reports the range of heights that would
+be pruned from the block store for a given retain height, and the disk space
+occupied by these blocks, without deleting anything.
+
+Blocks are pruned exactly like the node does when the application returns a
+retain height on Commit: every block below the retain height is removed. The
+retain height can be given directly with --retain-height, or derived from the
+number of most recent blocks to keep with --keep-recent.
+
+The node should be stopped before running this command.
+`,
+	Example: `
+	tendermint prune-preview --keep-recent 100000
+	tendermint prune-preview --retain-height 1500000
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (prunePreviewKeepRecent > 0) == (prunePreviewRetainHeight > 0) {
+			return fmt.Errorf("%w: exactly one of --keep-recent and --retain-height must be positive", ErrInvalidRequest)
+		}
+
+		bs, ss, err := loadStateAndBlockStore(config)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = bs.Close()
+			_ = ss.Close()
+		}()
+
+		base, height := bs.Base(), bs.Height()
+		if height == 0 {
+			return errors.New("the block store is empty")
+		}
+
+		retainHeight := prunePreviewRetainHeight
+		if prunePreviewKeepRecent > 0 {
+			retainHeight = height - prunePreviewKeepRecent + 1
+		}
+
+		fmt.Printf("block store: heights %d to %d\n", base, height)
+		// like the node, nothing is pruned for a retain height at or below the base
+		if retainHeight <= base {
+			fmt.Printf("retain height %d: nothing would be pruned\n", retainHeight)
+			return nil
+		}
+
+		pruned, size, err := bs.PrunePreview(retainHeight)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("retain height %d: heights %d to %d would be pruned (%d blocks)\n",
+			retainHeight, base, retainHeight-1, pruned)
+		fmt.Printf("about %d bytes would be freed from the block store\n", size)
+		return nil
+	},
+}
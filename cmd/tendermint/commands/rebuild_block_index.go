@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	tmos "github.com/tendermint/tendermint/libs/os"
+)
+
+var rebuildBlockIndexDryRun bool
+
+func init() {
+	RebuildBlockIndexCmd.Flags().BoolVar(&rebuildBlockIndexDryRun, "dry-run", false,
+		"only report the damaged heights, without modifying the block store")
+}
+
+// RebuildBlockIndexCmd rebuilds the block metas and the block hash index of the
+// block store from the stored block parts.
+var RebuildBlockIndexCmd = &cobra.Command{
+	Use:   "rebuild-block-index",
+	Short: "Rebuild the block index of the block store from the stored block parts",
+	Long: `
+rebuild-block-index is an offline recovery tool which checks, for every height
+retained by the block store, that the block meta is readable and that the
+block hash maps to the height. Blocks whose index is damaged are unreachable
+even though their parts are stored: their meta and hash index are rebuilt from
+the parts. The heights whose block can't be reassembled from the stored parts
+are reported as unrecoverable.
+
+Before modifying anything, the block store database is copied next to it, as
+blockstore.db.bak-<timestamp>. With --dry-run, the damaged heights are only
+reported.
+
+The node must be stopped before running this command.
+`,
+	Example: `
+	tendermint rebuild-block-index --dry-run
+	tendermint rebuild-block-index
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !rebuildBlockIndexDryRun {
+			src := filepath.Join(config.DBDir(), "blockstore.db")
+			dst := fmt.Sprintf("%s.bak-%d", src, time.Now().Unix())
+			if err := copyPath(src, dst); err != nil {
+				return fmt.Errorf("failed to back up the block store: %w", err)
+			}
+			logger.Info("Backed up the block store", "path", dst)
+		}
+
+		bs, ss, err := loadStateAndBlockStore(config)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = bs.Close()
+			_ = ss.Close()
+		}()
+
+		result, err := bs.RebuildBlockIndex(rebuildBlockIndexDryRun)
+		if err != nil {
+			return err
+		}
+
+		verb := "rebuilt"
+		if rebuildBlockIndexDryRun {
+			verb = "to rebuild"
+		}
+		fmt.Printf("checked heights %d to %d: %d %s, %d unrecoverable\n",
+			bs.Base(), bs.Height(), len(result.Rebuilt), verb, len(result.Unrecoverable))
+		if len(result.Rebuilt) > 0 {
+			fmt.Printf("%s: %v\n", verb, result.Rebuilt)
+		}
+		if len(result.Unrecoverable) > 0 {
+			fmt.Printf("unrecoverable: %v\n", result.Unrecoverable)
+		}
+		return nil
+	},
+}
+
+// copyPath copies the file or directory at src to dst, which must not exist.
+func copyPath(src, dst string) error {
+	if tmos.FileExists(dst) {
+		return fmt.Errorf("%v already exists", dst)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o700)
+		}
+		return tmos.CopyFile(path, target)
+	})
+}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyPath(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "blockstore.db")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "000001.log"), []byte("log"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "file"), []byte("file"), 0o600))
+
+	dst := filepath.Join(dir, "blockstore.db.bak")
+	require.NoError(t, copyPath(src, dst))
+	bz, err := os.ReadFile(filepath.Join(dst, "000001.log"))
+	require.NoError(t, err)
+	require.Equal(t, "log", string(bz))
+	bz, err = os.ReadFile(filepath.Join(dst, "sub", "file"))
+	require.NoError(t, err)
+	require.Equal(t, "file", string(bz))
+
+	// an existing backup is never overwritten
+	require.Error(t, copyPath(src, dst))
+}
@@ -62,11 +62,12 @@ want to use this command.
 			return
 		}
 
-		bi, ti, err := loadEventSinks(config)
+		bi, ti, closeSinks, err := loadEventSinks(config)
 		if err != nil {
 			fmt.Println(reindexFailed, err)
 			return
 		}
+		defer func() { _ = closeSinks() }()
 
 		riArgs := eventReIndexArgs{
 			startHeight:  startHeight,
@@ -94,31 +95,33 @@ func init() {
 	ReIndexEventCmd.Flags().Int64Var(&endHeight, "end-height", 0, "the block height would like to finish for re-index")
 }
 
-func loadEventSinks(cfg *tmcfg.Config) (indexer.BlockIndexer, txindex.TxIndexer, error) {
+// loadEventSinks opens the event sinks of the node, which are to be closed
+// with the returned function once done.
+func loadEventSinks(cfg *tmcfg.Config) (indexer.BlockIndexer, txindex.TxIndexer, func() error, error) {
 	switch strings.ToLower(cfg.TxIndex.Indexer) {
 	case "null":
-		return nil, nil, errors.New("found null event sink, please check the tx-index section in the config.toml")
+		return nil, nil, nil, errors.New("found null event sink, please check the tx-index section in the config.toml")
 	case "psql":
 		conn := cfg.TxIndex.PsqlConn
 		if conn == "" {
-			return nil, nil, errors.New("the psql connection settings cannot be empty")
+			return nil, nil, nil, errors.New("the psql connection settings cannot be empty")
 		}
 		es, err := psql.NewEventSink(conn, cfg.ChainID())
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
-		return es.BlockIndexer(), es.TxIndexer(), nil
+		return es.BlockIndexer(), es.TxIndexer(), es.Stop, nil
 	case "kv":
 		store, err := dbm.NewDB("tx_index", dbm.BackendType(cfg.DBBackend), cfg.DBDir())
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		txIndexer := kv.NewTxIndex(store)
 		blockIndexer := blockidxkv.New(dbm.NewPrefixDB(store, []byte("block_events")))
-		return blockIndexer, txIndexer, nil
+		return blockIndexer, txIndexer, store.Close, nil
 	default:
-		return nil, nil, fmt.Errorf("unsupported event sink type: %s", cfg.TxIndex.Indexer)
+		return nil, nil, nil, fmt.Errorf("unsupported event sink type: %s", cfg.TxIndex.Indexer)
 	}
 }
 
@@ -98,11 +98,12 @@ func TestLoadEventSink(t *testing.T) {
 		cfg := tmcfg.TestConfig()
 		cfg.TxIndex.Indexer = tc.sinks
 		cfg.TxIndex.PsqlConn = tc.connURL
-		_, _, err := loadEventSinks(cfg)
+		_, _, closeSinks, err := loadEventSinks(cfg)
 		if tc.loadErr {
 			require.Error(t, err, idx)
 		} else {
 			require.NoError(t, err, idx)
+			require.NoError(t, closeSinks(), idx)
 		}
 	}
 }
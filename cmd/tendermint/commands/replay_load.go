@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	replayLoadNode        string
+	replayLoadRate        float64
+	replayLoadConcurrency int
+)
+
+func init() {
+	ReplayLoadCmd.Flags().StringVar(&replayLoadNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	ReplayLoadCmd.Flags().Float64Var(&replayLoadRate, "rate", 100,
+		"the number of transactions submitted per second (0 submits them as fast as possible)")
+	ReplayLoadCmd.Flags().IntVar(&replayLoadConcurrency, "concurrency", 4,
+		"the number of concurrent submissions")
+}
+
+// ReplayLoadCmd submits the transactions of a mempool dump to a node.
+var ReplayLoadCmd = &cobra.Command{
+	Use:   "replay-load [dump-file]",
+	Short: "Submit the transactions of a mempool dump to a node",
+	Long: `
+replay-load reads the transactions of a mempool dump, as returned by the
+unconfirmed_txs RPC endpoint, and submits them with broadcast_tx_sync to the
+RPC server of a running node, at the given rate. This turns a captured mempool
+into a repeatable CheckTx load test.
+
+Once all the transactions have been submitted, the submission throughput and
+the number of transactions rejected, by reason, are reported.
+`,
+	Example: `
+	curl -s 'localhost:26657/unconfirmed_txs?limit=100' > mempool.json
+	tendermint replay-load mempool.json --node tcp://localhost:36657 --rate 500 --concurrency 8
+	`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if replayLoadRate < 0 {
+			return fmt.Errorf("%w: rate can't be negative", ErrInvalidRequest)
+		}
+		if replayLoadConcurrency <= 0 {
+			return fmt.Errorf("%w: concurrency must be positive", ErrInvalidRequest)
+		}
+
+		bz, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		txs, err := parseMempoolDump(bz)
+		if err != nil {
+			return fmt.Errorf("invalid mempool dump %v: %w", args[0], err)
+		}
+
+		client, err := rpchttp.New(replayLoadNode, "/websocket")
+		if err != nil {
+			return fmt.Errorf("failed to create new http client: %w", err)
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		logger.Info("Submitting transactions", "count", len(txs), "rate", replayLoadRate,
+			"concurrency", replayLoadConcurrency)
+		report := replayLoad(ctx, client, txs, replayLoadRate, replayLoadConcurrency)
+
+		fmt.Printf("submitted %d txs in %v (%.2f txs/s): %d accepted, %d rejected\n",
+			report.Submitted, report.Elapsed.Round(time.Millisecond), report.Throughput(),
+			report.Accepted, report.Submitted-report.Accepted)
+		reasons := make([]string, 0, len(report.Rejections))
+		for reason := range report.Rejections {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Printf("  %d rejected: %s\n", report.Rejections[reason], reason)
+		}
+		return nil
+	},
+}
+
+// parseMempoolDump returns the transactions of an unconfirmed_txs result,
+// whether or not it's wrapped in its JSON-RPC response.
+func parseMempoolDump(bz []byte) (types.Txs, error) {
+	var resp rpctypes.RPCResponse
+	if err := json.Unmarshal(bz, &resp); err == nil && resp.Result != nil {
+		bz = resp.Result
+	}
+	result := new(ctypes.ResultUnconfirmedTxs)
+	if err := tmjson.Unmarshal(bz, result); err != nil {
+		return nil, err
+	}
+	return result.Txs, nil
+}
+
+// txBroadcaster submits a transaction to the CheckTx of a node.
+type txBroadcaster interface {
+	BroadcastTxSync(context.Context, types.Tx) (*ctypes.ResultBroadcastTx, error)
+}
+
+// replayLoadReport is the outcome of a replay-load run.
+type replayLoadReport struct {
+	Submitted int
+	Accepted  int
+	// Rejections counts the rejected transactions by reason: the CheckTx code
+	// and log, or the error of the request.
+	Rejections map[string]int
+	Elapsed    time.Duration
+}
+
+// Throughput returns the number of transactions submitted per second.
+func (r replayLoadReport) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Submitted) / r.Elapsed.Seconds()
+}
+
+// replayLoad submits txs from concurrency goroutines, at most rate txs per
+// second if rate is positive. It stops early if ctx is done.
+func replayLoad(
+	ctx context.Context,
+	client txBroadcaster,
+	txs types.Txs,
+	rate float64,
+	concurrency int,
+) replayLoadReport {
+	report := replayLoadReport{Rejections: make(map[string]int)}
+	var (
+		mtx   sync.Mutex
+		wg    sync.WaitGroup
+		queue = make(chan types.Tx)
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tx := range queue {
+				res, err := client.BroadcastTxSync(ctx, tx)
+				mtx.Lock()
+				report.Submitted++
+				switch {
+				case err != nil:
+					report.Rejections[err.Error()]++
+				case res.Code != 0:
+					report.Rejections[fmt.Sprintf("code %d: %s", res.Code, res.Log)]++
+				default:
+					report.Accepted++
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+
+	start := time.Now()
+	var tick <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+dispatch:
+	for i, tx := range txs {
+		// the first tx is submitted right away
+		if tick != nil && i > 0 {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		select {
+		case queue <- tx:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	return report
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// checkTxClient rejects the txs which start with "bad", and fails to submit the
+// txs which start with "err".
+type checkTxClient struct {
+	submitted int64
+}
+
+func (c *checkTxClient) BroadcastTxSync(_ context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	atomic.AddInt64(&c.submitted, 1)
+	switch {
+	case len(tx) >= 3 && string(tx[:3]) == "bad":
+		return &ctypes.ResultBroadcastTx{Code: 2, Log: "bad tx", Hash: tx.Hash()}, nil
+	case len(tx) >= 3 && string(tx[:3]) == "err":
+		return nil, errors.New("connection refused")
+	}
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+func TestParseMempoolDump(t *testing.T) {
+	txs := types.Txs{types.Tx("a"), types.Tx("b")}
+	result, err := tmjson.Marshal(&ctypes.ResultUnconfirmedTxs{Count: 2, Total: 2, Txs: txs})
+	require.NoError(t, err)
+
+	parsed, err := parseMempoolDump(result)
+	require.NoError(t, err)
+	assert.Equal(t, txs, parsed)
+
+	// the JSON-RPC response of unconfirmed_txs
+	resp := `{"jsonrpc":"2.0","id":-1,"result":` + string(result) + `}`
+	parsed, err = parseMempoolDump([]byte(resp))
+	require.NoError(t, err)
+	assert.Equal(t, txs, parsed)
+
+	_, err = parseMempoolDump([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestReplayLoad(t *testing.T) {
+	txs := types.Txs{
+		types.Tx("good1"), types.Tx("bad1"), types.Tx("good2"),
+		types.Tx("err1"), types.Tx("bad2"), types.Tx("good3"),
+	}
+	client := &checkTxClient{}
+	report := replayLoad(context.Background(), client, txs, 0, 3)
+	assert.EqualValues(t, len(txs), client.submitted)
+	assert.Equal(t, len(txs), report.Submitted)
+	assert.Equal(t, 3, report.Accepted)
+	assert.Equal(t, map[string]int{
+		"code 2: bad tx":     2,
+		"connection refused": 1,
+	}, report.Rejections)
+
+	// at 50 txs/s, the 6 txs take at least 100ms to be submitted
+	report = replayLoad(context.Background(), &checkTxClient{}, txs, 50, 1)
+	assert.Equal(t, len(txs), report.Submitted)
+	assert.GreaterOrEqual(t, report.Elapsed, 100*time.Millisecond)
+
+	// nothing is submitted once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client = &checkTxClient{}
+	report = replayLoad(ctx, client, txs, 1, 1)
+	assert.LessOrEqual(t, report.Submitted, 1)
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -21,7 +22,10 @@ var ResetAllCmd = &cobra.Command{
 	PreRun:  deprecateSnakeCase,
 }
 
-var keepAddrBook bool
+var (
+	keepAddrBook bool
+	resetDryRun  bool
+)
 
 // ResetStateCmd removes the database of the specified Tendermint core instance.
 var ResetStateCmd = &cobra.Command{
@@ -40,6 +44,8 @@ var ResetStateCmd = &cobra.Command{
 
 func init() {
 	ResetAllCmd.Flags().BoolVar(&keepAddrBook, "keep-addr-book", false, "keep the address book intact")
+	ResetAllCmd.Flags().BoolVar(&resetDryRun, "dry-run", false,
+		"print the paths which would be removed or reset, and their size, without touching them")
 }
 
 // ResetPrivValidatorCmd resets the private validator files.
@@ -81,6 +87,7 @@ func resetPrivValidator(cmd *cobra.Command, args []string) (err error) {
 }
 
 // resetAll removes address book files plus all data, and resets the privValdiator data.
+// With --dry-run, it only prints the paths it would remove or reset.
 func resetAll(dbDir, addrBookFile, privValKeyFile, privValStateFile string, logger log.Logger) error {
 	if keepAddrBook {
 		logger.Info("The address book remains intact")
@@ -88,14 +95,18 @@ func resetAll(dbDir, addrBookFile, privValKeyFile, privValStateFile string, logg
 		removeAddrBook(addrBookFile, logger)
 	}
 
-	if err := os.RemoveAll(dbDir); err == nil {
+	if resetDryRun {
+		printDryRun("remove", dbDir)
+	} else if err := os.RemoveAll(dbDir); err == nil {
 		logger.Info("Removed all blockchain history", "dir", dbDir)
 	} else {
 		logger.Error("Error removing all blockchain history", "dir", dbDir, "err", err)
 	}
 
-	if err := tmos.EnsureDir(dbDir, 0700); err != nil {
-		logger.Error("unable to recreate dbDir", "err", err)
+	if !resetDryRun {
+		if err := tmos.EnsureDir(dbDir, 0700); err != nil {
+			logger.Error("unable to recreate dbDir", "err", err)
+		}
 	}
 
 	// recreate the dbDir since the privVal state needs to live there
@@ -158,6 +169,15 @@ func resetState(dbDir string, logger log.Logger) error {
 }
 
 func resetFilePV(privValKeyFile, privValStateFile string, logger log.Logger) {
+	if resetDryRun {
+		if _, err := os.Stat(privValKeyFile); err == nil {
+			printDryRun("reset", privValStateFile)
+		} else {
+			printDryRun("generate", privValKeyFile)
+			printDryRun("generate", privValStateFile)
+		}
+		return
+	}
 	if _, err := os.Stat(privValKeyFile); err == nil {
 		pv := privval.LoadFilePVEmptyState(privValKeyFile, privValStateFile)
 		pv.Reset()
@@ -178,9 +198,42 @@ func resetFilePV(privValKeyFile, privValStateFile string, logger log.Logger) {
 }
 
 func removeAddrBook(addrBookFile string, logger log.Logger) {
+	if resetDryRun {
+		printDryRun("remove", addrBookFile)
+		return
+	}
 	if err := os.Remove(addrBookFile); err == nil {
 		logger.Info("Removed existing address book", "file", addrBookFile)
 	} else if !os.IsNotExist(err) {
 		logger.Info("Error removing address book", "file", addrBookFile, "err", err)
 	}
 }
+
+// printDryRun prints a path which a reset would act upon, and its size.
+func printDryRun(action, path string) {
+	size, err := pathSize(path)
+	switch {
+	case os.IsNotExist(err):
+		fmt.Printf("would %s %s (does not exist)\n", action, path)
+	case err != nil:
+		fmt.Printf("would %s %s (unknown size: %v)\n", action, path, err)
+	default:
+		fmt.Printf("would %s %s (%d bytes)\n", action, path, size)
+	}
+}
+
+// pathSize returns the size of a file, or the total size of the files in a
+// directory.
+func pathSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -51,3 +52,33 @@ func Test_ResetState(t *testing.T) {
 	// private validator state should still be in tact.
 	require.Equal(t, int64(10), pv.LastSignState.Height)
 }
+
+func Test_ResetAllDryRun(t *testing.T) {
+	config := cfg.TestConfig()
+	dir := t.TempDir()
+	config.SetRoot(dir)
+	cfg.EnsureRoot(dir)
+	require.NoError(t, initFilesWithConfig(config))
+	pv := privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	pv.LastSignState.Height = 10
+	pv.Save()
+	blockdb := filepath.Join(config.DBDir(), "blockstore.db")
+	require.NoError(t, os.MkdirAll(blockdb, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(blockdb, "000001.log"), []byte("block"), 0600))
+	require.NoError(t, os.WriteFile(config.P2P.AddrBookFile(), []byte("{}"), 0600))
+
+	resetDryRun = true
+	defer func() { resetDryRun = false }()
+	require.NoError(t, resetAll(config.DBDir(), config.P2P.AddrBookFile(), config.PrivValidatorKeyFile(),
+		config.PrivValidatorStateFile(), logger))
+
+	// nothing is removed, nor reset
+	require.FileExists(t, filepath.Join(blockdb, "000001.log"))
+	require.FileExists(t, config.P2P.AddrBookFile())
+	pv = privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	require.Equal(t, int64(10), pv.LastSignState.Height)
+
+	size, err := pathSize(blockdb)
+	require.NoError(t, err)
+	require.Equal(t, int64(len("block")), size)
+}
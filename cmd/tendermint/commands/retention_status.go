@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/state/indexer"
+	"github.com/tendermint/tendermint/store"
+)
+
+// RetentionStatusCmd reports which heights are still retained by the block
+// store, which heights have been indexed and up to which height proofs can be
+// served.
+var RetentionStatusCmd = &cobra.Command{
+	Use:   "retention-status",
+	Short: "Show the retained, indexed and provable height ranges of the node",
+	Long: `
+retention-status is an offline tool which reports, in a single view, the range
+of blocks retained by the block store (after pruning), the range of heights
+indexed by the event sink and the maximum height for which proofs can be
+generated (i.e. the latest height for which both the block and its commit are
+stored).
+
+The node should be stopped before running this command.
+`,
+	Example: `
+	tendermint retention-status
+	tendermint retention-status --json
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bs, ss, err := loadStateAndBlockStore(config)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = bs.Close()
+			_ = ss.Close()
+		}()
+
+		bi, _, closeSinks, err := loadEventSinks(config)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = closeSinks() }()
+
+		status, err := retentionStatus(bs, bi)
+		if err != nil {
+			return err
+		}
+
+		if retentionStatusJSON {
+			bz, err := json.MarshalIndent(status, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		}
+
+		fmt.Printf("earliest block height:   %d\n", status.EarliestBlockHeight)
+		fmt.Printf("latest block height:     %d\n", status.LatestBlockHeight)
+		fmt.Printf("earliest indexed height: %d\n", status.EarliestIndexedHeight)
+		fmt.Printf("latest indexed height:   %d\n", status.LatestIndexedHeight)
+		fmt.Printf("max provable height:     %d\n", status.MaxProvableHeight)
+		return nil
+	},
+}
+
+var retentionStatusJSON bool
+
+func init() {
+	RetentionStatusCmd.Flags().BoolVar(&retentionStatusJSON, "json", false, "output the status as JSON")
+}
+
+// RetentionStatus describes the height ranges available on a node. A height
+// of 0 means that the corresponding range is empty.
+type RetentionStatus struct {
+	EarliestBlockHeight   int64 `json:"earliest_block_height"`
+	LatestBlockHeight     int64 `json:"latest_block_height"`
+	EarliestIndexedHeight int64 `json:"earliest_indexed_height"`
+	LatestIndexedHeight   int64 `json:"latest_indexed_height"`
+	MaxProvableHeight     int64 `json:"max_provable_height"`
+}
+
+func retentionStatus(bs *store.BlockStore, bi indexer.BlockIndexer) (RetentionStatus, error) {
+	status := RetentionStatus{
+		EarliestBlockHeight: bs.Base(),
+		LatestBlockHeight:   bs.Height(),
+	}
+
+	if status.LatestBlockHeight == 0 {
+		return status, nil
+	}
+
+	// the seen commit is only stored for the latest block, so the latest height
+	// can be proven only if it has not been lost (e.g. on an unclean shutdown)
+	status.MaxProvableHeight = status.LatestBlockHeight
+	if bs.LoadSeenCommit(status.LatestBlockHeight) == nil {
+		status.MaxProvableHeight = status.LatestBlockHeight - 1
+	}
+	if status.MaxProvableHeight < status.EarliestBlockHeight {
+		status.MaxProvableHeight = 0
+	}
+
+	minIndexed, err := bi.MinIndexedHeight()
+	if err != nil {
+		return status, fmt.Errorf("getting the lowest indexed height: %w", err)
+	}
+	if minIndexed == 0 {
+		return status, nil
+	}
+	status.EarliestIndexedHeight = minIndexed
+
+	// indexing might have been disabled for a while, so look for the last
+	// indexed height starting from the tip
+	for h := status.LatestBlockHeight; h >= status.EarliestIndexedHeight; h-- {
+		ok, err := bi.Has(h)
+		if err != nil {
+			return status, fmt.Errorf("checking whether height %d is indexed: %w", h, err)
+		}
+		if ok {
+			status.LatestIndexedHeight = h
+			break
+		}
+	}
+
+	return status, nil
+}
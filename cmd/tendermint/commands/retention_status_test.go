@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/crypto"
+	blockidxkv "github.com/tendermint/tendermint/state/indexer/block/kv"
+	"github.com/tendermint/tendermint/store"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestRetentionStatus(t *testing.T) {
+	db := dbm.NewMemDB()
+	bs := store.NewBlockStore(db)
+	for h := int64(1); h <= 6; h++ {
+		block := types.MakeBlock(h, nil, retentionTestCommit(h-1), nil)
+		block.ProposerAddress = make([]byte, crypto.AddressSize)
+		partSet := block.MakePartSet(types.BlockPartSizeBytes)
+		bs.SaveBlock(block, partSet, retentionTestCommit(h))
+	}
+	_, err := bs.PruneBlocks(3)
+	require.NoError(t, err)
+
+	// heights 4 and 5 only are indexed
+	bi := blockidxkv.New(dbm.NewMemDB())
+	for h := int64(4); h <= 5; h++ {
+		require.NoError(t, bi.Index(types.EventDataNewBlockHeader{Header: types.Header{Height: h}}))
+	}
+
+	status, err := retentionStatus(bs, bi)
+	require.NoError(t, err)
+	assert.Equal(t, RetentionStatus{
+		EarliestBlockHeight:   3,
+		LatestBlockHeight:     6,
+		EarliestIndexedHeight: 4,
+		LatestIndexedHeight:   5,
+		MaxProvableHeight:     6,
+	}, status)
+
+	// without the seen commit of the latest block, it can't be proven
+	require.NoError(t, db.Delete([]byte(fmt.Sprintf("SC:%d", 6))))
+	status, err = retentionStatus(bs, bi)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, status.MaxProvableHeight)
+
+	// nothing is indexed
+	status, err = retentionStatus(bs, blockidxkv.New(dbm.NewMemDB()))
+	require.NoError(t, err)
+	assert.Zero(t, status.EarliestIndexedHeight)
+	assert.Zero(t, status.LatestIndexedHeight)
+
+	// an empty block store
+	status, err = retentionStatus(store.NewBlockStore(dbm.NewMemDB()), bi)
+	require.NoError(t, err)
+	assert.Equal(t, RetentionStatus{}, status)
+}
+
+func retentionTestCommit(height int64) *types.Commit {
+	return types.NewCommit(height, 0, types.BlockID{
+		Hash:          make([]byte, 32),
+		PartSetHeader: types.PartSetHeader{Hash: make([]byte, 32), Total: 1},
+	}, []types.CommitSig{{
+		BlockIDFlag:      types.BlockIDFlagCommit,
+		ValidatorAddress: make([]byte, crypto.AddressSize),
+		Timestamp:        time.Now(),
+		Signature:        []byte("signature"),
+	}})
+}
@@ -14,9 +14,16 @@ import (
 	"github.com/tendermint/tendermint/store"
 )
 
+var rollbackBlocks int64
+
+func init() {
+	RollbackStateCmd.Flags().Int64Var(&rollbackBlocks, "blocks", 1,
+		"number of heights to roll back the state by")
+}
+
 var RollbackStateCmd = &cobra.Command{
 	Use:   "rollback",
-	Short: "rollback tendermint state by one height",
+	Short: "rollback tendermint state by one height (or --blocks heights)",
 	Long: `
 A state rollback is performed to recover from an incorrect application state transition,
 when Tendermint has persisted an incorrect app hash and is thus unable to make
@@ -24,9 +31,14 @@ progress. Rollback overwrites a state at height n with the state at height n - 1
 The application should also roll back to height n - 1. No blocks are removed, so upon
 restarting Tendermint the transactions in block n will be re-executed against the
 application.
+
+With --blocks N, the state at height n is overwritten with the state at height n - N,
+one height at a time, and the application should roll back to height n - N. The
+rollback fails without modifying the state if height n - N is below the base height
+of the block store.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		height, hash, err := RollbackState(config)
+		height, hash, err := RollbackStateBlocks(config, rollbackBlocks)
 		if err != nil {
 			return fmt.Errorf("failed to rollback state: %w", err)
 		}
@@ -54,6 +66,21 @@ func RollbackState(config *cfg.Config) (int64, []byte, error) {
 	return state.Rollback(blockStore, stateStore)
 }
 
+// RollbackStateBlocks is like RollbackState, but overwrites the state at the
+// current height n with the state at height n - blocks.
+func RollbackStateBlocks(config *cfg.Config, blocks int64) (int64, []byte, error) {
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	if err != nil {
+		return -1, nil, err
+	}
+	defer func() {
+		_ = blockStore.Close()
+		_ = stateStore.Close()
+	}()
+
+	return state.RollbackBlocks(blockStore, stateStore, blocks)
+}
+
 func loadStateAndBlockStore(config *cfg.Config) (*store.BlockStore, state.Store, error) {
 	dbType := dbm.BackendType(config.DBBackend)
 
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/rpc/core"
+	rpcserver "github.com/tendermint/tendermint/rpc/jsonrpc/server"
+)
+
+var (
+	rpcRoutesJSON   bool
+	rpcRoutesUnsafe bool
+)
+
+func init() {
+	RPCRoutesCmd.Flags().BoolVar(&rpcRoutesJSON, "json", false, "output the routes as JSON")
+	RPCRoutesCmd.Flags().BoolVar(&rpcRoutesUnsafe, "unsafe", false, "include the unsafe routes")
+}
+
+// RPCRoutesCmd lists the routes of the RPC server along with their parameters.
+var RPCRoutesCmd = &cobra.Command{
+	Use:   "rpc-routes",
+	Short: "List the RPC routes and their parameters",
+	Long: `
+rpc-routes lists every route registered by the RPC server of this version of
+Tendermint, along with the names and Go types of its parameters. The unsafe
+routes, which are only served when rpc.unsafe is enabled, are listed with
+--unsafe.
+`,
+	Example: `
+	tendermint rpc-routes
+	tendermint rpc-routes --unsafe --json
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rpcRoutesUnsafe {
+			core.AddUnsafeRoutes()
+		}
+		routes := rpcRoutes(core.Routes)
+
+		if rpcRoutesJSON {
+			bz, err := json.MarshalIndent(routes, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		}
+
+		for _, route := range routes {
+			params := make([]string, len(route.Params))
+			for i, p := range route.Params {
+				params[i] = p.Name + " " + p.Type
+			}
+			fmt.Printf("%s(%s)\n", route.Name, strings.Join(params, ", "))
+		}
+		return nil
+	},
+}
+
+// RPCRoute describes a route of the RPC server.
+type RPCRoute struct {
+	Name   string     `json:"name"`
+	Params []RPCParam `json:"params"`
+}
+
+// RPCParam describes a parameter of an RPC route.
+type RPCParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// rpcRoutes describes the given routes, sorted by name.
+func rpcRoutes(funcMap map[string]*rpcserver.RPCFunc) []RPCRoute {
+	routes := make([]RPCRoute, 0, len(funcMap))
+	for name, f := range funcMap {
+		route := RPCRoute{Name: name, Params: []RPCParam{}}
+		types := f.ArgTypes()
+		for i, argName := range f.ArgNames() {
+			param := RPCParam{Name: argName}
+			if i < len(types) {
+				param.Type = types[i].String()
+			}
+			route.Params = append(route.Params, param)
+		}
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Name < routes[j].Name })
+	return routes
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/rpc/core"
+)
+
+func TestRPCRoutes(t *testing.T) {
+	routes := rpcRoutes(core.Routes)
+	require.Len(t, routes, len(core.Routes))
+
+	byName := make(map[string]RPCRoute, len(routes))
+	for i, route := range routes {
+		if i > 0 {
+			require.Less(t, routes[i-1].Name, route.Name, "routes must be sorted by name")
+		}
+		byName[route.Name] = route
+	}
+
+	require.Equal(t, []RPCParam{}, byName["health"].Params)
+	require.Equal(t, []RPCParam{
+		{Name: "hash", Type: "[]uint8"},
+		{Name: "prove", Type: "bool"},
+	}, byName["tx"].Params)
+	require.Equal(t, []RPCParam{
+		{Name: "height", Type: "*int64"},
+		{Name: "page", Type: "*int"},
+		{Name: "per_page", Type: "*int"},
+	}, byName["validators"].Params)
+}
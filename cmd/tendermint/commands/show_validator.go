@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/tendermint/tendermint/crypto"
 	tmjson "github.com/tendermint/tendermint/libs/json"
 	tmos "github.com/tendermint/tendermint/libs/os"
 	"github.com/tendermint/tendermint/privval"
@@ -19,6 +23,20 @@ var ShowValidatorCmd = &cobra.Command{
 	PreRun:  deprecateSnakeCase,
 }
 
+var showValidatorOutput string
+
+func init() {
+	ShowValidatorCmd.Flags().StringVar(&showValidatorOutput, "output", "text",
+		`output format: "text" (the amino JSON of the pubkey) or "json" (its key type and bytes)`)
+}
+
+// validatorPubKey is the JSON output of show-validator: the type of the key,
+// as given by crypto.PubKey.Type() (e.g. "ed25519"), and its bytes.
+type validatorPubKey struct {
+	Type  string `json:"type"`
+	Value []byte `json:"value"`
+}
+
 func showValidator(cmd *cobra.Command, args []string) error {
 	keyFilePath := config.PrivValidatorKeyFile()
 	if !tmos.FileExists(keyFilePath) {
@@ -32,11 +50,26 @@ func showValidator(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("can't get pubkey: %w", err)
 	}
 
-	bz, err := tmjson.Marshal(pubKey)
+	return writeValidatorPubKey(os.Stdout, pubKey, showValidatorOutput)
+}
+
+func writeValidatorPubKey(w io.Writer, pubKey crypto.PubKey, output string) error {
+	var (
+		bz  []byte
+		err error
+	)
+	switch output {
+	case "text":
+		bz, err = tmjson.Marshal(pubKey)
+	case "json":
+		bz, err = json.Marshal(validatorPubKey{Type: pubKey.Type(), Value: pubKey.Bytes()})
+	default:
+		return fmt.Errorf(`unknown output format %q, expected "text" or "json"`, output)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to marshal private validator pubkey: %w", err)
 	}
 
-	fmt.Println(string(bz))
-	return nil
+	_, err = fmt.Fprintln(w, string(bz))
+	return err
 }
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/privval"
+)
+
+func TestWriteValidatorPubKey(t *testing.T) {
+	for _, privKey := range []crypto.PrivKey{ed25519.GenPrivKey(), secp256k1.GenPrivKey()} {
+		t.Run(privKey.Type(), func(t *testing.T) {
+			// load the key from a key file, as show-validator does
+			dir := t.TempDir()
+			keyFile, stateFile := filepath.Join(dir, "key.json"), filepath.Join(dir, "state.json")
+			privval.NewFilePV(privKey, keyFile, stateFile).Save()
+			pubKey, err := privval.LoadFilePV(keyFile, stateFile).GetPubKey()
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, writeValidatorPubKey(&buf, pubKey, "json"))
+			var out validatorPubKey
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+			require.Equal(t, privKey.Type(), out.Type)
+			require.Equal(t, privKey.PubKey().Bytes(), out.Value)
+
+			// the default output is unchanged
+			buf.Reset()
+			require.NoError(t, writeValidatorPubKey(&buf, pubKey, "text"))
+			var decoded crypto.PubKey
+			require.NoError(t, tmjson.Unmarshal(buf.Bytes(), &decoded))
+			require.Equal(t, privKey.PubKey(), decoded)
+		})
+	}
+
+	require.Error(t, writeValidatorPubKey(&bytes.Buffer{}, ed25519.GenPrivKey().PubKey(), "yaml"))
+}
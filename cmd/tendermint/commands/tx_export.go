@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+)
+
+var (
+	txExportNode             string
+	txExportProve            bool
+	txExportOrderBy          string
+	txExportSkipPrunedProofs bool
+	txExportPerPage          int
+)
+
+const txExportProgressInterval = 10 * time.Second
+
+func init() {
+	TxExportCmd.Flags().StringVar(&txExportNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	TxExportCmd.Flags().BoolVar(&txExportProve, "prove", false, "include the proof of each transaction")
+	TxExportCmd.Flags().StringVar(&txExportOrderBy, "order-by", "asc", "the order of the transactions (asc or desc)")
+	TxExportCmd.Flags().BoolVar(&txExportSkipPrunedProofs, "skip-pruned-proofs", false,
+		"leave the proof of transactions whose block has been pruned empty, instead of failing")
+	TxExportCmd.Flags().IntVar(&txExportPerPage, "per-page", 100, "the number of transactions fetched per request")
+}
+
+// TxExportCmd writes every transaction matching a query to a file.
+var TxExportCmd = &cobra.Command{
+	Use:   "tx-export [query] [output-file]",
+	Short: "Export the transactions matching a query as NDJSON",
+	Long: `
+tx-export runs a tx_search query against the RPC server of a running node, and
+writes every matching transaction, across all the pages of results, to the
+output file. Each line of the file holds one transaction, encoded as in the
+tx_search response.
+
+The progress of the export is logged periodically.
+`,
+	Example: `
+	tendermint tx-export "tx.height >= 100" txs.ndjson
+	tendermint tx-export "transfer.sender = 'addr'" txs.ndjson --prove --skip-pruned-proofs
+	`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if txExportPerPage <= 0 {
+			return fmt.Errorf("%w: per-page must be positive", ErrInvalidRequest)
+		}
+
+		client, err := jsonrpcclient.New(txExportNode)
+		if err != nil {
+			return fmt.Errorf("failed to create new http client: %w", err)
+		}
+
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w := bufio.NewWriter(f)
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		lastLog := time.Now()
+		opts := txExportOptions{
+			Prove:            txExportProve,
+			OrderBy:          txExportOrderBy,
+			SkipPrunedProofs: txExportSkipPrunedProofs,
+			PerPage:          txExportPerPage,
+		}
+		exported, err := exportTxs(ctx, client, args[0], opts, w, func(exported, total int) {
+			if time.Since(lastLog) >= txExportProgressInterval {
+				logger.Info("Exporting transactions", "exported", exported, "total", total)
+				lastLog = time.Now()
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		logger.Info("Exported transactions", "count", exported, "file", args[1])
+		return nil
+	},
+}
+
+// txExportOptions are the tx_search parameters of an export.
+type txExportOptions struct {
+	Prove            bool
+	OrderBy          string
+	SkipPrunedProofs bool
+	PerPage          int
+}
+
+// exportTxs writes the transactions matching the query to w, one JSON object
+// per line, going through every page of results. It calls progress after each
+// page, and returns the number of exported transactions.
+func exportTxs(
+	ctx context.Context,
+	caller jsonrpcclient.Caller,
+	query string,
+	opts txExportOptions,
+	w io.Writer,
+	progress func(exported, total int),
+) (int, error) {
+	exported := 0
+	for page := 1; ; page++ {
+		result := new(ctypes.ResultTxSearch)
+		params := map[string]interface{}{
+			"query":              query,
+			"prove":              opts.Prove,
+			"page":               page,
+			"per_page":           opts.PerPage,
+			"order_by":           opts.OrderBy,
+			"skip_pruned_proofs": opts.SkipPrunedProofs,
+		}
+		if _, err := caller.Call(ctx, "tx_search", params, result); err != nil {
+			return exported, fmt.Errorf("fetching page %d: %w", page, err)
+		}
+
+		for _, tx := range result.Txs {
+			bz, err := tmjson.Marshal(tx)
+			if err != nil {
+				return exported, err
+			}
+			if _, err := w.Write(append(bz, '\n')); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+		progress(exported, result.TotalCount)
+
+		if len(result.Txs) == 0 || exported >= result.TotalCount {
+			return exported, nil
+		}
+	}
+}
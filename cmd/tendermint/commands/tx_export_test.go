@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// pagedTxSearchCaller serves tx_search requests from a fixed list of txs.
+type pagedTxSearchCaller struct {
+	txs   []*ctypes.ResultTx
+	calls []map[string]interface{}
+}
+
+func (c *pagedTxSearchCaller) Call(
+	ctx context.Context,
+	method string,
+	params map[string]interface{},
+	result interface{},
+) (interface{}, error) {
+	c.calls = append(c.calls, params)
+	page, perPage := params["page"].(int), params["per_page"].(int)
+	start, end := (page-1)*perPage, page*perPage
+	if start > len(c.txs) {
+		start = len(c.txs)
+	}
+	if end > len(c.txs) {
+		end = len(c.txs)
+	}
+	res := result.(*ctypes.ResultTxSearch)
+	res.Txs = c.txs[start:end]
+	res.TotalCount = len(c.txs)
+	return res, nil
+}
+
+func TestExportTxs(t *testing.T) {
+	caller := &pagedTxSearchCaller{}
+	for i := 0; i < 7; i++ {
+		tx := types.Tx{byte(i)}
+		caller.txs = append(caller.txs, &ctypes.ResultTx{Hash: tx.Hash(), Height: int64(i + 1), Tx: tx})
+	}
+
+	var buf bytes.Buffer
+	var progress []int
+	opts := txExportOptions{OrderBy: "asc", PerPage: 3, SkipPrunedProofs: true}
+	exported, err := exportTxs(context.Background(), caller, "tx.height > 0", opts, &buf, func(exported, total int) {
+		require.Equal(t, 7, total)
+		progress = append(progress, exported)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 7, exported)
+	require.Equal(t, []int{3, 6, 7}, progress)
+	require.Len(t, caller.calls, 3)
+	require.Equal(t, true, caller.calls[0]["skip_pruned_proofs"])
+	require.Equal(t, "tx.height > 0", caller.calls[0]["query"])
+
+	scanner := bufio.NewScanner(&buf)
+	var lines int
+	for scanner.Scan() {
+		var tx ctypes.ResultTx
+		require.NoError(t, tmjson.Unmarshal(scanner.Bytes(), &tx))
+		require.Equal(t, caller.txs[lines].Hash, tx.Hash)
+		require.Equal(t, caller.txs[lines].Height, tx.Height)
+		lines++
+	}
+	require.Equal(t, 7, lines)
+}
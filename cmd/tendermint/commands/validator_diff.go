@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/bytes"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	validatorDiffNode     string
+	validatorDiffJSON     bool
+	validatorDiffExitCode bool
+)
+
+func init() {
+	ValidatorDiffCmd.Flags().StringVar(&validatorDiffNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	ValidatorDiffCmd.Flags().BoolVar(&validatorDiffJSON, "json", false, "output the changes as JSON")
+	ValidatorDiffCmd.Flags().BoolVar(&validatorDiffExitCode, "exit-code", false,
+		"exit with a non-zero status if the validator sets differ")
+}
+
+// ValidatorDiffCmd reports the changes of the validator set between two
+// heights.
+var ValidatorDiffCmd = &cobra.Command{
+	Use:   "validator-diff [from-height] [to-height]",
+	Short: "Show the validator set changes between two heights",
+	Long: `
+validator-diff fetches the validator sets at the two given heights from the
+RPC server of a running node, and reports the validators which were added,
+removed, or whose voting power changed in between.
+
+With --exit-code, the command exits with a non-zero status if any change was
+found, which is convenient for scripting.
+`,
+	Example: `
+	tendermint validator-diff 100 200
+	tendermint validator-diff 100 200 --node tcp://localhost:26657 --json
+	`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || from <= 0 {
+			return fmt.Errorf("invalid from-height %q: must be a positive integer", args[0])
+		}
+		to, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || to <= 0 {
+			return fmt.Errorf("invalid to-height %q: must be a positive integer", args[1])
+		}
+
+		client, err := rpchttp.New(validatorDiffNode, "/websocket")
+		if err != nil {
+			return fmt.Errorf("failed to create new http client: %w", err)
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		fromVals, err := fetchValidators(ctx, client, from)
+		if err != nil {
+			return err
+		}
+		toVals, err := fetchValidators(ctx, client, to)
+		if err != nil {
+			return err
+		}
+
+		diff := diffValidators(fromVals, toVals)
+		diff.FromHeight, diff.ToHeight = from, to
+
+		if validatorDiffJSON {
+			bz, err := tmjson.MarshalIndent(diff, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+		} else {
+			printValidatorDiff(diff)
+		}
+
+		if validatorDiffExitCode && !diff.Empty() {
+			return errors.New("the validator sets differ")
+		}
+		return nil
+	},
+}
+
+// ValidatorDiff lists the validator set changes between two heights.
+type ValidatorDiff struct {
+	FromHeight   int64             `json:"from_height"`
+	ToHeight     int64             `json:"to_height"`
+	Added        []ValidatorChange `json:"added"`
+	Removed      []ValidatorChange `json:"removed"`
+	PowerChanged []ValidatorChange `json:"power_changed"`
+}
+
+// Empty returns true if the two validator sets are identical.
+func (diff ValidatorDiff) Empty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.PowerChanged) == 0
+}
+
+// ValidatorChange describes the change of a single validator. The power of a
+// validator absent from a set is 0.
+type ValidatorChange struct {
+	Address  bytes.HexBytes `json:"address"`
+	PubKey   crypto.PubKey  `json:"pub_key"`
+	OldPower int64          `json:"old_power"`
+	NewPower int64          `json:"new_power"`
+}
+
+// fetchValidators returns the whole validator set at the given height, going
+// through every page of the validators endpoint.
+func fetchValidators(ctx context.Context, client rpcclient.SignClient, height int64) ([]*types.Validator, error) {
+	var (
+		vals    []*types.Validator
+		perPage = 100
+	)
+	for page := 1; ; page++ {
+		res, err := client.Validators(ctx, &height, &page, &perPage)
+		if err != nil {
+			return nil, fmt.Errorf("fetching the validators at height %d: %w", height, err)
+		}
+		vals = append(vals, res.Validators...)
+		if len(res.Validators) == 0 || len(vals) >= res.Total {
+			return vals, nil
+		}
+	}
+}
+
+// diffValidators computes the changes from one validator set to another. Each
+// list of changes is sorted by address.
+func diffValidators(from, to []*types.Validator) ValidatorDiff {
+	var diff ValidatorDiff
+
+	old := make(map[string]*types.Validator, len(from))
+	for _, val := range from {
+		old[string(val.Address)] = val
+	}
+
+	for _, val := range to {
+		prev, ok := old[string(val.Address)]
+		delete(old, string(val.Address))
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, ValidatorChange{
+				Address:  val.Address,
+				PubKey:   val.PubKey,
+				NewPower: val.VotingPower,
+			})
+		case prev.VotingPower != val.VotingPower:
+			diff.PowerChanged = append(diff.PowerChanged, ValidatorChange{
+				Address:  val.Address,
+				PubKey:   val.PubKey,
+				OldPower: prev.VotingPower,
+				NewPower: val.VotingPower,
+			})
+		}
+	}
+
+	for _, val := range old {
+		diff.Removed = append(diff.Removed, ValidatorChange{
+			Address:  val.Address,
+			PubKey:   val.PubKey,
+			OldPower: val.VotingPower,
+		})
+	}
+
+	for _, changes := range [][]ValidatorChange{diff.Added, diff.Removed, diff.PowerChanged} {
+		changes := changes
+		sort.Slice(changes, func(i, j int) bool {
+			return changes[i].Address.String() < changes[j].Address.String()
+		})
+	}
+	return diff
+}
+
+func printValidatorDiff(diff ValidatorDiff) {
+	fmt.Printf("validator set changes from height %d to height %d\n", diff.FromHeight, diff.ToHeight)
+	if diff.Empty() {
+		fmt.Println("no changes")
+		return
+	}
+
+	fmt.Printf("added (%d):\n", len(diff.Added))
+	for _, c := range diff.Added {
+		fmt.Printf("  + %v power %d\n", c.Address, c.NewPower)
+	}
+	fmt.Printf("removed (%d):\n", len(diff.Removed))
+	for _, c := range diff.Removed {
+		fmt.Printf("  - %v power %d\n", c.Address, c.OldPower)
+	}
+	fmt.Printf("power changed (%d):\n", len(diff.PowerChanged))
+	for _, c := range diff.PowerChanged {
+		fmt.Printf("  ~ %v power %d -> %d\n", c.Address, c.OldPower, c.NewPower)
+	}
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestDiffValidators(t *testing.T) {
+	vals := make([]*types.Validator, 4)
+	for i := range vals {
+		vals[i] = types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	}
+	withPower := func(val *types.Validator, power int64) *types.Validator {
+		val = val.Copy()
+		val.VotingPower = power
+		return val
+	}
+
+	diff := diffValidators(vals[:3], vals[:3])
+	require.True(t, diff.Empty())
+
+	// vals[0] is unchanged, vals[1] is removed, vals[2] changes its power and
+	// vals[3] is added
+	diff = diffValidators(vals[:3], []*types.Validator{vals[0], withPower(vals[2], 25), vals[3]})
+	require.False(t, diff.Empty())
+
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, vals[3].Address, diff.Added[0].Address)
+	require.EqualValues(t, 0, diff.Added[0].OldPower)
+	require.EqualValues(t, 10, diff.Added[0].NewPower)
+
+	require.Len(t, diff.Removed, 1)
+	require.Equal(t, vals[1].Address, diff.Removed[0].Address)
+	require.EqualValues(t, 10, diff.Removed[0].OldPower)
+	require.EqualValues(t, 0, diff.Removed[0].NewPower)
+
+	require.Len(t, diff.PowerChanged, 1)
+	require.Equal(t, vals[2].Address, diff.PowerChanged[0].Address)
+	require.EqualValues(t, 10, diff.PowerChanged[0].OldPower)
+	require.EqualValues(t, 25, diff.PowerChanged[0].NewPower)
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/tempfile"
+	"github.com/tendermint/tendermint/privval"
+)
+
+// ExportValidatorStateCmd writes the last sign state of the private validator
+// to a file, so that it can be moved to another host.
+var ExportValidatorStateCmd = &cobra.Command{
+	Use:   "export-validator-state [file]",
+	Short: "Export this node's validator last sign state",
+	Long: `
+export-validator-state writes the last signed height, round and step of the
+file based private validator (priv_validator_state.json) to the given file, or
+to stdout if no file is given. The output can be imported on another host with
+import-validator-state.
+
+The node should be stopped before running this command, so that the exported
+state is not outdated by the time it is imported.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lss, err := privval.LoadFilePVLastSignState(config.PrivValidatorStateFile())
+		if err != nil {
+			return fmt.Errorf("failed to load validator state: %w", err)
+		}
+
+		bz, err := tmjson.MarshalIndent(lss, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal validator state: %w", err)
+		}
+
+		if len(args) == 0 {
+			fmt.Println(string(bz))
+			return nil
+		}
+		if err := tempfile.WriteFileAtomic(args[0], bz, 0o600); err != nil {
+			return err
+		}
+
+		logger.Info("Exported validator state", "file", args[0],
+			"height", lss.Height, "round", lss.Round, "step", lss.Step)
+		return nil
+	},
+}
+
+// ImportValidatorStateCmd replaces the last sign state of the private validator
+// with one exported by ExportValidatorStateCmd.
+var ImportValidatorStateCmd = &cobra.Command{
+	Use:   "import-validator-state [file]",
+	Short: "Import a validator last sign state exported from another host",
+	Long: `
+import-validator-state atomically replaces this node's priv_validator_state.json
+with the state stored in the given file.
+
+The import fails if the current state is ahead of the imported one (i.e. it has
+a higher height, round or step), as lowering the last signed height could lead
+the validator to double sign.
+
+The node should be stopped before running this command.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bz, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		var lss privval.FilePVLastSignState
+		if err := tmjson.Unmarshal(bz, &lss); err != nil {
+			return fmt.Errorf("error reading validator state from %v: %w", args[0], err)
+		}
+
+		if err := privval.ImportFilePVLastSignState(config.PrivValidatorStateFile(), lss); err != nil {
+			return err
+		}
+
+		logger.Info("Imported validator state", "file", config.PrivValidatorStateFile(),
+			"height", lss.Height, "round", lss.Round, "step", lss.Step)
+		return nil
+	},
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/libs/tempfile"
+	"github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	verifyAppHashChainFrom       int64
+	verifyAppHashChainTo         int64
+	verifyAppHashChainCheckpoint string
+)
+
+// appHashChainCheckpointInterval is the number of heights verified between two
+// writes of the checkpoint file.
+const appHashChainCheckpointInterval = 1000
+
+func init() {
+	VerifyAppHashChainCmd.Flags().Int64Var(&verifyAppHashChainFrom, "from", 0,
+		"the first height to verify (defaults to the block store base)")
+	VerifyAppHashChainCmd.Flags().Int64Var(&verifyAppHashChainTo, "to", 0,
+		"the last height to verify (defaults to the block store height)")
+	VerifyAppHashChainCmd.Flags().StringVar(&verifyAppHashChainCheckpoint, "checkpoint", "",
+		"a file recording the last verified height, to resume an interrupted verification")
+}
+
+// VerifyAppHashChainCmd checks that the stored blocks are chained to each
+// other, along with the app hash and results hash they commit to.
+var VerifyAppHashChainCmd = &cobra.Command{
+	Use:   "verify-apphash-chain",
+	Short: "Verify the chaining of the stored blocks and of their app and results hashes",
+	Long: `
+verify-apphash-chain is an offline tool which checks, for every pair of
+consecutive heights between --from and --to, that the stored block metas are
+chained: the header of each block hashes to its block ID, and the next header
+refers to that block ID. Since the header hash covers the app hash, a modified
+app hash breaks the chain. When the ABCI responses of a height are stored, the
+results hash of the next header is also checked against them.
+
+The first break of the chain is reported, and the command fails. Nothing is
+replayed.
+
+With --checkpoint, the last verified height is recorded in the given file as
+the verification progresses, and a verification started with an existing file
+resumes after that height.
+
+The node should be stopped before running this command.
+`,
+	Example: `
+	tendermint verify-apphash-chain
+	tendermint verify-apphash-chain --from 1000 --to 2000
+	tendermint verify-apphash-chain --checkpoint verify.checkpoint
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bs, ss, err := loadStateAndBlockStore(config)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = bs.Close()
+			_ = ss.Close()
+		}()
+
+		from, to := verifyAppHashChainFrom, verifyAppHashChainTo
+		if from == 0 || from < bs.Base() {
+			from = bs.Base()
+		}
+		if to == 0 || to > bs.Height() {
+			to = bs.Height()
+		}
+		if verifyAppHashChainCheckpoint != "" {
+			last, err := readAppHashChainCheckpoint(verifyAppHashChainCheckpoint)
+			if err != nil {
+				return err
+			}
+			if last >= from {
+				logger.Info("Resuming from checkpoint", "height", last)
+				from = last
+			}
+		}
+		if from >= to {
+			return fmt.Errorf("%w: no pair of retained heights between %d and %d", ErrHeightNotAvailable, from, to)
+		}
+
+		report, err := verifyAppHashChain(bs, ss, from, to, func(height int64) error {
+			if verifyAppHashChainCheckpoint == "" {
+				return nil
+			}
+			return writeAppHashChainCheckpoint(verifyAppHashChainCheckpoint, height)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("verified %d links between heights %d and %d (%d results hashes verified)\n",
+			report.Links, from, report.LastVerified, report.ResultsHashes)
+		if report.Break != nil {
+			fmt.Printf("chain broken at height %d: %s\n", report.Break.Height, report.Break.Reason)
+			return errors.New("the stored blocks are not consistently chained")
+		}
+		return nil
+	},
+}
+
+// appHashChainBreak is a height whose block isn't consistently chained to the
+// previous one.
+type appHashChainBreak struct {
+	Height int64
+	Reason string
+}
+
+type appHashChainReport struct {
+	// Links is the number of pairs of consecutive heights verified.
+	Links int
+	// ResultsHashes is the number of results hashes verified against the
+	// stored ABCI responses.
+	ResultsHashes int
+	// LastVerified is the last height chained to the previous ones.
+	LastVerified int64
+	// Break is the first break of the chain, if any.
+	Break *appHashChainBreak
+}
+
+// blockMetaStore is the subset of the block store used to verify the chain.
+type blockMetaStore interface {
+	LoadBlockMeta(height int64) *types.BlockMeta
+}
+
+// verifyAppHashChain checks the chaining of the blocks from `from` to `to`
+// (inclusive), and stops at the first break. checkpoint is called with the
+// last verified height every appHashChainCheckpointInterval heights, and once
+// the verification stops.
+func verifyAppHashChain(
+	bs blockMetaStore,
+	ss state.Store,
+	from, to int64,
+	checkpoint func(height int64) error,
+) (appHashChainReport, error) {
+	report := appHashChainReport{LastVerified: from}
+
+	meta := bs.LoadBlockMeta(from)
+	if meta == nil {
+		report.Break = &appHashChainBreak{Height: from, Reason: "block meta not found"}
+		return report, nil
+	}
+	if h := meta.Header.Hash(); !bytes.Equal(meta.BlockID.Hash, h) {
+		report.Break = &appHashChainBreak{Height: from,
+			Reason: fmt.Sprintf("block ID %X doesn't match the header hash %X", meta.BlockID.Hash, h)}
+		return report, nil
+	}
+
+	for height := from + 1; height <= to; height++ {
+		next := bs.LoadBlockMeta(height)
+		brk, resultsVerified, err := verifyAppHashChainLink(ss, meta, next)
+		if err != nil {
+			return report, err
+		}
+		if brk != nil {
+			report.Break = brk
+			break
+		}
+		if resultsVerified {
+			report.ResultsHashes++
+		}
+		report.Links++
+		report.LastVerified = height
+		meta = next
+
+		if report.Links%appHashChainCheckpointInterval == 0 {
+			if err := checkpoint(report.LastVerified); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, checkpoint(report.LastVerified)
+}
+
+// verifyAppHashChainLink checks that next is chained to meta, which has been
+// verified already. It also reports whether the results hash of next was
+// verified, which requires the ABCI responses of meta to be stored.
+func verifyAppHashChainLink(ss state.Store, meta, next *types.BlockMeta) (*appHashChainBreak, bool, error) {
+	height := meta.Header.Height + 1
+	if next == nil {
+		return &appHashChainBreak{Height: height, Reason: "block meta not found"}, false, nil
+	}
+	if h := next.Header.Hash(); !bytes.Equal(next.BlockID.Hash, h) {
+		return &appHashChainBreak{Height: height,
+			Reason: fmt.Sprintf("block ID %X doesn't match the header hash %X", next.BlockID.Hash, h)}, false, nil
+	}
+	if !next.Header.LastBlockID.Equals(meta.BlockID) {
+		return &appHashChainBreak{Height: height,
+			Reason: fmt.Sprintf("last block ID %v doesn't match the block ID %v of height %d",
+				next.Header.LastBlockID, meta.BlockID, meta.Header.Height)}, false, nil
+	}
+
+	var notFound state.ErrNoABCIResponsesForHeight
+	responses, err := ss.LoadABCIResponses(meta.Header.Height)
+	switch {
+	case errors.As(err, &notFound) || errors.Is(err, state.ErrABCIResponsesNotPersisted):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, fmt.Errorf("loading the ABCI responses of height %d: %w", meta.Header.Height, err)
+	}
+	if h := state.ABCIResponsesResultsHash(responses); !bytes.Equal(next.Header.LastResultsHash, h) {
+		return &appHashChainBreak{Height: height,
+			Reason: fmt.Sprintf("last results hash %X doesn't match the hash %X of the ABCI responses of height %d",
+				next.Header.LastResultsHash, h, meta.Header.Height)}, false, nil
+	}
+	return nil, true, nil
+}
+
+// readAppHashChainCheckpoint returns the height recorded in the checkpoint
+// file, or 0 if it doesn't exist.
+func readAppHashChainCheckpoint(path string) (int64, error) {
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	height, err := strconv.ParseInt(strings.TrimSpace(string(bz)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint file %v: %w", path, err)
+	}
+	return height, nil
+}
+
+func writeAppHashChainCheckpoint(path string, height int64) error {
+	return tempfile.WriteFileAtomic(path, []byte(strconv.FormatInt(height, 10)+"\n"), 0o600)
+}
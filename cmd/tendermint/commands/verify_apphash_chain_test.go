@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tm-db"
+
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	"github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/mocks"
+	"github.com/tendermint/tendermint/types"
+)
+
+// makeAppHashChain returns the chained metas of the blocks from height 1 to n,
+// and stores the ABCI responses of every height.
+func makeAppHashChain(t *testing.T, ss state.Store, n int64) map[int64]*types.BlockMeta {
+	metas := make(map[int64]*types.BlockMeta)
+	var lastBlockID types.BlockID
+	var lastResultsHash []byte
+	for h := int64(1); h <= n; h++ {
+		header := types.Header{
+			ChainID:         "test",
+			Height:          h,
+			LastBlockID:     lastBlockID,
+			LastResultsHash: lastResultsHash,
+			AppHash:         tmhash.Sum([]byte{byte(h)}),
+			ValidatorsHash:  tmhash.Sum([]byte("vals")),
+		}
+		meta := &types.BlockMeta{BlockID: types.BlockID{Hash: header.Hash()}, Header: header}
+		metas[h] = meta
+
+		responses := &tmstate.ABCIResponses{
+			DeliverTxs: []*abcitypes.ResponseDeliverTx{{Code: 0, Data: []byte{byte(h)}}},
+			EndBlock:   &abcitypes.ResponseEndBlock{},
+			BeginBlock: &abcitypes.ResponseBeginBlock{},
+		}
+		require.NoError(t, ss.SaveABCIResponses(h, responses))
+		lastBlockID, lastResultsHash = meta.BlockID, state.ABCIResponsesResultsHash(responses)
+	}
+	return metas
+}
+
+func appHashChainStore(metas map[int64]*types.BlockMeta) *mocks.BlockStore {
+	bs := &mocks.BlockStore{}
+	for h, meta := range metas {
+		bs.On("LoadBlockMeta", h).Return(meta)
+	}
+	return bs
+}
+
+func TestVerifyAppHashChain(t *testing.T) {
+	ss := state.NewStore(dbm.NewMemDB(), state.StoreOptions{})
+	metas := makeAppHashChain(t, ss, 5)
+
+	var checkpoints []int64
+	checkpoint := func(height int64) error {
+		checkpoints = append(checkpoints, height)
+		return nil
+	}
+
+	report, err := verifyAppHashChain(appHashChainStore(metas), ss, 1, 5, checkpoint)
+	require.NoError(t, err)
+	assert.Nil(t, report.Break)
+	assert.Equal(t, 4, report.Links)
+	assert.Equal(t, 4, report.ResultsHashes)
+	assert.Equal(t, int64(5), report.LastVerified)
+	assert.Equal(t, []int64{5}, checkpoints)
+
+	// the app hash of height 3 was modified: the header doesn't match its block ID
+	tampered := *metas[3]
+	tampered.Header.AppHash = tmhash.Sum([]byte("tampered"))
+	metas[3] = &tampered
+	report, err = verifyAppHashChain(appHashChainStore(metas), ss, 1, 5, checkpoint)
+	require.NoError(t, err)
+	require.NotNil(t, report.Break)
+	assert.Equal(t, int64(3), report.Break.Height)
+	assert.Equal(t, int64(2), report.LastVerified)
+
+	// the block ID was recomputed too: the next header doesn't refer to it
+	tampered.BlockID = types.BlockID{Hash: tampered.Header.Hash()}
+	report, err = verifyAppHashChain(appHashChainStore(metas), ss, 1, 5, checkpoint)
+	require.NoError(t, err)
+	require.NotNil(t, report.Break)
+	assert.Equal(t, int64(4), report.Break.Height)
+	assert.Contains(t, report.Break.Reason, "last block ID")
+
+	// the ABCI responses of height 1 don't match the results hash of height 2
+	ss = state.NewStore(dbm.NewMemDB(), state.StoreOptions{})
+	metas = makeAppHashChain(t, ss, 3)
+	require.NoError(t, ss.SaveABCIResponses(1, &tmstate.ABCIResponses{
+		DeliverTxs: []*abcitypes.ResponseDeliverTx{{Code: 1}},
+	}))
+	report, err = verifyAppHashChain(appHashChainStore(metas), ss, 1, 3, checkpoint)
+	require.NoError(t, err)
+	require.NotNil(t, report.Break)
+	assert.Equal(t, int64(2), report.Break.Height)
+	assert.Contains(t, report.Break.Reason, "results hash")
+
+	// without ABCI responses, only the block IDs are verified
+	ss = state.NewStore(dbm.NewMemDB(), state.StoreOptions{DiscardABCIResponses: true})
+	report, err = verifyAppHashChain(appHashChainStore(makeAppHashChain(t, ss, 3)), ss, 1, 3, checkpoint)
+	require.NoError(t, err)
+	assert.Nil(t, report.Break)
+	assert.Equal(t, 2, report.Links)
+	assert.Equal(t, 0, report.ResultsHashes)
+}
+
+func TestAppHashChainCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	height, err := readAppHashChainCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), height)
+
+	require.NoError(t, writeAppHashChainCheckpoint(path, 1234))
+	height, err = readAppHashChainCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), height)
+}
@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+	"github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	verifyIndexSampleRate  float64
+	verifyIndexStartHeight int64
+	verifyIndexEndHeight   int64
+)
+
+func init() {
+	VerifyIndexStoreConsistencyCmd.Flags().Float64Var(&verifyIndexSampleRate, "sample-rate", 1,
+		"the fraction of the indexed txs to check, in (0, 1]")
+	VerifyIndexStoreConsistencyCmd.Flags().Int64Var(&verifyIndexStartHeight, "start-height", 0,
+		"the block height to start checking from (defaults to the block store base)")
+	VerifyIndexStoreConsistencyCmd.Flags().Int64Var(&verifyIndexEndHeight, "end-height", 0,
+		"the last block height to check (defaults to the block store height)")
+}
+
+// VerifyIndexStoreConsistencyCmd checks that the txs of the tx index match the
+// txs of the block store.
+var VerifyIndexStoreConsistencyCmd = &cobra.Command{
+	Use:   "verify-index-store-consistency",
+	Short: "Verify that the indexed txs resolve in the block store",
+	Long: `
+verify-index-store-consistency is an offline tool which checks, for every height
+retained by the block store, that each tx indexed at that height is found at the
+indexed position of the stored block. Any mismatch indicates that the tx index
+or the block store is corrupted.
+
+Large indexes can be checked partially with --sample-rate, which checks each
+indexed tx with the given probability.
+
+The node should be stopped before running this command.
+`,
+	Example: `
+	tendermint verify-index-store-consistency
+	tendermint verify-index-store-consistency --sample-rate 0.01
+	tendermint verify-index-store-consistency --start-height 1000 --end-height 2000
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifyIndexSampleRate <= 0 || verifyIndexSampleRate > 1 {
+			return fmt.Errorf("%w: sample-rate must be in (0, 1]", ErrInvalidRequest)
+		}
+
+		bs, ss, err := loadStateAndBlockStore(config)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = bs.Close()
+			_ = ss.Close()
+		}()
+
+		_, ti, closeSinks, err := loadEventSinks(config)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = closeSinks() }()
+
+		start, end := verifyIndexStartHeight, verifyIndexEndHeight
+		if start == 0 || start < bs.Base() {
+			start = bs.Base()
+		}
+		if end == 0 || end > bs.Height() {
+			end = bs.Height()
+		}
+		if start > end {
+			return fmt.Errorf("%w: no retained height between %d and %d", ErrHeightNotAvailable, start, end)
+		}
+
+		report, err := verifyIndexStoreConsistency(cmd.Context(), ti, bs, start, end, verifyIndexSampleRate)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range report.Mismatches {
+			fmt.Printf("mismatch at height %d, index %d (tx %X): %s\n", m.Height, m.Index, m.Hash, m.Reason)
+		}
+		fmt.Printf("checked %d of %d indexed txs between heights %d and %d: %d mismatches\n",
+			report.Checked, report.Indexed, start, end, len(report.Mismatches))
+		if len(report.Mismatches) > 0 {
+			return errors.New("the tx index is inconsistent with the block store")
+		}
+		return nil
+	},
+}
+
+// indexMismatch describes an indexed tx which does not match the block store.
+type indexMismatch struct {
+	Height int64
+	Index  uint32
+	Hash   []byte
+	Reason string
+}
+
+type indexConsistencyReport struct {
+	Indexed    int
+	Checked    int
+	Mismatches []indexMismatch
+}
+
+// verifyIndexStoreConsistency checks the txs indexed at each height from start
+// to end (inclusive) against the blocks of the block store. Each indexed tx is
+// checked with the probability given by sampleRate.
+func verifyIndexStoreConsistency(
+	ctx context.Context,
+	ti txindex.TxIndexer,
+	bs state.BlockStore,
+	start, end int64,
+	sampleRate float64,
+) (indexConsistencyReport, error) {
+	var report indexConsistencyReport
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for height := start; height <= end; height++ {
+		q, err := tmquery.New(fmt.Sprintf("tx.height = %d", height))
+		if err != nil {
+			return report, err
+		}
+		results, err := ti.Search(ctx, q)
+		if err != nil {
+			return report, fmt.Errorf("searching the txs indexed at height %d: %w", height, err)
+		}
+		block := bs.LoadBlock(height)
+		for _, r := range results {
+			// a tx included again at a later height is indexed at the latest
+			// one, where it is checked
+			if r.Height != height {
+				continue
+			}
+			report.Indexed++
+			if sampleRate < 1 && tmrand.Float64() >= sampleRate {
+				continue
+			}
+			report.Checked++
+
+			mismatch := indexMismatch{Height: r.Height, Index: r.Index, Hash: types.Tx(r.Tx).Hash()}
+			switch {
+			case block == nil:
+				mismatch.Reason = "block not found in the block store"
+			case int(r.Index) >= len(block.Data.Txs):
+				mismatch.Reason = fmt.Sprintf("the block only contains %d txs", len(block.Data.Txs))
+			case !bytes.Equal(block.Data.Txs[r.Index], r.Tx):
+				mismatch.Reason = fmt.Sprintf("the block contains tx %X at this index", block.Data.Txs[r.Index].Hash())
+			default:
+				continue
+			}
+			report.Mismatches = append(report.Mismatches, mismatch)
+		}
+	}
+	return report, nil
+}
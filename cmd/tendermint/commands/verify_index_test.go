@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tm-db"
+
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/state/mocks"
+	"github.com/tendermint/tendermint/state/txindex/kv"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestVerifyIndexStoreConsistency(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	index := func(height int64, i uint32, tx types.Tx) {
+		require.NoError(t, txIndexer.Index(&abcitypes.TxResult{Height: height, Index: i, Tx: tx}))
+	}
+
+	mockBlockStore := &mocks.BlockStore{}
+	for h := int64(1); h <= 3; h++ {
+		mockBlockStore.On("LoadBlock", h).Return(&types.Block{Data: types.Data{Txs: types.Txs{
+			types.Tx("a"), types.Tx("b"),
+		}}})
+	}
+	mockBlockStore.On("LoadBlock", int64(4)).Return(nil)
+
+	// height 1 is consistent
+	index(1, 0, types.Tx("a"))
+	index(1, 1, types.Tx("b"))
+	// height 2 is indexed with the wrong position, and an extra tx
+	index(2, 0, types.Tx("c"))
+	index(2, 2, types.Tx("e"))
+	// height 4 is missing from the block store
+	index(4, 0, types.Tx("d"))
+
+	report, err := verifyIndexStoreConsistency(context.Background(), txIndexer, mockBlockStore, 1, 4, 1)
+	require.NoError(t, err)
+	require.Equal(t, 5, report.Indexed)
+	require.Equal(t, 5, report.Checked)
+	require.Len(t, report.Mismatches, 3)
+	for _, m := range report.Mismatches {
+		require.NotEqual(t, int64(1), m.Height)
+		require.NotEmpty(t, m.Reason)
+	}
+
+	report, err = verifyIndexStoreConsistency(context.Background(), txIndexer, mockBlockStore, 1, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Checked)
+	require.Empty(t, report.Mismatches)
+
+	// a tx included again later is checked at the latest height only
+	index(3, 1, types.Tx("a"))
+	report, err = verifyIndexStoreConsistency(context.Background(), txIndexer, mockBlockStore, 1, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Checked)
+	require.Empty(t, report.Mismatches)
+
+	// sampling checks a subset of the indexed txs
+	report, err = verifyIndexStoreConsistency(context.Background(), txIndexer, mockBlockStore, 1, 4, 0.5)
+	require.NoError(t, err)
+	require.Equal(t, 5, report.Indexed)
+	require.LessOrEqual(t, report.Checked, report.Indexed)
+}
@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/consensus"
+	tmos "github.com/tendermint/tendermint/libs/os"
+)
+
+var walCheckRepair bool
+
+func init() {
+	WALCheckCmd.Flags().BoolVar(&walCheckRepair, "repair", false,
+		"truncate the WAL to the last valid message if corruption is found")
+}
+
+// WALCheckCmd decodes every message of the consensus WAL and reports the first
+// corrupted one.
+var WALCheckCmd = &cobra.Command{
+	Use:   "wal-check",
+	Short: "Check the consensus WAL for corruption",
+	Long: `
+wal-check reads the consensus WAL, including the files it has been rotated
+into, and decodes every message it contains. It reports the file and offset of
+the first message which cannot be decoded.
+
+By default the WAL is left untouched. With --repair, the corrupted file is
+truncated right after the last valid message and any WAL file following it is
+removed, so only the valid prefix of the WAL remains.
+
+The node should be stopped before running this command.
+`,
+	Example: `
+	tendermint wal-check
+	tendermint wal-check --repair
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		walFile := config.Consensus.WalFile()
+		reports, err := checkWAL(walFile)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range reports {
+			fmt.Printf("%s: %d valid messages\n", r.Path, r.Messages)
+		}
+
+		last := reports[len(reports)-1]
+		if last.Err == nil {
+			fmt.Println("no corruption found")
+			return nil
+		}
+
+		fmt.Printf("corruption found in %s at offset %d: %v\n", last.Path, last.ValidBytes, last.Err)
+		if !walCheckRepair {
+			return errors.New("the WAL is corrupted, run again with --repair to truncate it")
+		}
+
+		removed, err := repairWAL(walFile, last)
+		if err != nil {
+			return fmt.Errorf("failed to repair the WAL: %w", err)
+		}
+		fmt.Printf("truncated %s to %d bytes\n", last.Path, last.ValidBytes)
+		for _, path := range removed {
+			fmt.Printf("removed %s\n", path)
+		}
+		return nil
+	},
+}
+
+// walFileReport is the result of decoding a single WAL file.
+type walFileReport struct {
+	Path     string
+	Messages int
+	// ValidBytes is the offset right after the last valid message.
+	ValidBytes int64
+	// Err is the error returned when decoding the message at ValidBytes, if
+	// the file is corrupted.
+	Err error
+}
+
+// checkWAL decodes the files of the WAL with the given head path, oldest
+// first, and returns a report for each of them. It stops at the first
+// corrupted file, which is the last one reported.
+func checkWAL(headPath string) ([]walFileReport, error) {
+	paths, err := walFilePaths(headPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no WAL found at %v", headPath)
+	}
+
+	reports := make([]walFileReport, 0, len(paths))
+	for _, path := range paths {
+		r, err := checkWALFile(path)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+		if r.Err != nil {
+			break
+		}
+	}
+	return reports, nil
+}
+
+func checkWALFile(path string) (walFileReport, error) {
+	r := walFileReport{Path: path}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return r, err
+	}
+	defer f.Close()
+
+	cr := &countingReader{rd: f}
+	dec := consensus.NewWALDecoder(cr)
+	for {
+		_, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return r, nil
+		}
+		if err != nil {
+			r.Err = err
+			return r, nil
+		}
+		r.Messages++
+		r.ValidBytes = cr.n
+	}
+}
+
+// repairWAL truncates the corrupted file to its valid prefix and removes every
+// WAL file following it. It returns the paths of the removed files.
+func repairWAL(headPath string, corrupted walFileReport) ([]string, error) {
+	paths, err := walFilePaths(headPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Truncate(corrupted.Path, corrupted.ValidBytes); err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for i, path := range paths {
+		if path != corrupted.Path {
+			continue
+		}
+		for _, later := range paths[i+1:] {
+			if err := os.Remove(later); err != nil {
+				return removed, err
+			}
+			removed = append(removed, later)
+		}
+		break
+	}
+	return removed, nil
+}
+
+// walFilePaths returns the paths of the existing files making up the WAL with
+// the given head path, oldest first. Rotated files are named after the head,
+// with a numeric index suffix (e.g. wal.000).
+func walFilePaths(headPath string) ([]string, error) {
+	dir, head := filepath.Split(headPath)
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[int]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, head+".") {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(name, head+"."))
+		if err != nil {
+			continue
+		}
+		indexes[index] = filepath.Join(dir, name)
+	}
+
+	keys := make([]int, 0, len(indexes))
+	for index := range indexes {
+		keys = append(keys, index)
+	}
+	sort.Ints(keys)
+
+	paths := make([]string, 0, len(keys)+1)
+	for _, index := range keys {
+		paths = append(paths, indexes[index])
+	}
+	if tmos.FileExists(headPath) {
+		paths = append(paths, headPath)
+	}
+	return paths, nil
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	rd io.Reader
+	n  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.rd.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/consensus"
+)
+
+func writeWALFile(t *testing.T, path string, heights []int64, garbage []byte) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	enc := consensus.NewWALEncoder(f)
+	for _, h := range heights {
+		require.NoError(t, enc.Encode(&consensus.TimedWALMessage{
+			Time: time.Now(),
+			Msg:  consensus.EndHeightMessage{Height: h},
+		}))
+	}
+	_, err = f.Write(garbage)
+	require.NoError(t, err)
+}
+
+func TestWALCheck(t *testing.T) {
+	garbage := []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0x00, 0x00, 0x02, 0x01, 0x02}
+
+	t.Run("valid", func(t *testing.T) {
+		head := filepath.Join(t.TempDir(), "wal")
+		writeWALFile(t, head+".000", []int64{1, 2}, nil)
+		writeWALFile(t, head, []int64{3}, nil)
+
+		reports, err := checkWAL(head)
+		require.NoError(t, err)
+		require.Len(t, reports, 2)
+		require.Equal(t, head+".000", reports[0].Path)
+		require.Equal(t, 2, reports[0].Messages)
+		require.Equal(t, head, reports[1].Path)
+		require.Equal(t, 1, reports[1].Messages)
+		require.NoError(t, reports[1].Err)
+	})
+
+	t.Run("corrupted head", func(t *testing.T) {
+		head := filepath.Join(t.TempDir(), "wal")
+		writeWALFile(t, head+".000", []int64{1, 2}, nil)
+		writeWALFile(t, head, []int64{3, 4}, garbage)
+		info, err := os.Stat(head)
+		require.NoError(t, err)
+
+		reports, err := checkWAL(head)
+		require.NoError(t, err)
+		require.Len(t, reports, 2)
+		corrupted := reports[1]
+		require.Equal(t, 2, corrupted.Messages)
+		require.Error(t, corrupted.Err)
+		require.Equal(t, info.Size()-int64(len(garbage)), corrupted.ValidBytes)
+
+		// checking must not modify the WAL
+		after, err := os.Stat(head)
+		require.NoError(t, err)
+		require.Equal(t, info.Size(), after.Size())
+
+		removed, err := repairWAL(head, corrupted)
+		require.NoError(t, err)
+		require.Empty(t, removed)
+
+		reports, err = checkWAL(head)
+		require.NoError(t, err)
+		require.Len(t, reports, 2)
+		require.NoError(t, reports[1].Err)
+		require.Equal(t, 2, reports[1].Messages)
+	})
+
+	t.Run("corrupted rotated file", func(t *testing.T) {
+		head := filepath.Join(t.TempDir(), "wal")
+		writeWALFile(t, head+".000", []int64{1}, nil)
+		writeWALFile(t, head+".001", []int64{2}, garbage)
+		writeWALFile(t, head, []int64{3}, nil)
+
+		reports, err := checkWAL(head)
+		require.NoError(t, err)
+		require.Len(t, reports, 2, "checking must stop at the first corrupted file")
+		require.Equal(t, head+".001", reports[1].Path)
+		require.Error(t, reports[1].Err)
+
+		removed, err := repairWAL(head, reports[1])
+		require.NoError(t, err)
+		require.Equal(t, []string{head}, removed)
+		require.NoFileExists(t, head)
+	})
+}
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/crypto"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+)
+
+var (
+	watchSigningNode      string
+	watchSigningWindow    int
+	watchSigningThreshold float64
+	watchSigningInterval  time.Duration
+)
+
+func init() {
+	WatchSigningCmd.Flags().StringVar(&watchSigningNode, "node", "tcp://localhost:26657",
+		"the Tendermint node's RPC address (<host>:<port>)")
+	WatchSigningCmd.Flags().IntVar(&watchSigningWindow, "window", 100,
+		"the number of most recent blocks over which the participation is computed")
+	WatchSigningCmd.Flags().Float64Var(&watchSigningThreshold, "threshold", 90,
+		"the participation percentage below which an alert is reported")
+	WatchSigningCmd.Flags().DurationVar(&watchSigningInterval, "interval", time.Second,
+		"the interval at which the node is polled for new blocks")
+}
+
+// WatchSigningCmd reports the signing participation of a validator over the
+// most recent blocks.
+var WatchSigningCmd = &cobra.Command{
+	Use:   "watch-signing [validator-address]",
+	Short: "Follow the signing participation of a validator",
+	Long: `
+watch-signing follows the commits of new blocks from the RPC server of a
+running node, and reports, for each block, whether the validator with the given
+address (in hex) signed it, along with the percentage of the most recent blocks
+it signed. Blocks for which the validator was not in the validator set are not
+counted.
+
+An alert is logged when the participation drops below the threshold, and when
+it recovers.
+`,
+	Example: `
+	tendermint watch-signing 7B3D4B4E2B38C3A1D8C54C2E2C9B5F83CA9D5C80
+	tendermint watch-signing 7B3D4B4E2B38C3A1D8C54C2E2C9B5F83CA9D5C80 --window 1000 --threshold 95
+	`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		address, err := hex.DecodeString(args[0])
+		if err != nil || len(address) != crypto.AddressSize {
+			return fmt.Errorf("%w: invalid validator address %q", ErrInvalidRequest, args[0])
+		}
+		if watchSigningWindow <= 0 {
+			return fmt.Errorf("%w: window must be positive", ErrInvalidRequest)
+		}
+		if watchSigningThreshold < 0 || watchSigningThreshold > 100 {
+			return fmt.Errorf("%w: threshold must be within [0, 100]", ErrInvalidRequest)
+		}
+		if watchSigningInterval <= 0 {
+			return fmt.Errorf("%w: interval must be positive", ErrInvalidRequest)
+		}
+
+		client, err := rpchttp.New(watchSigningNode, "/websocket")
+		if err != nil {
+			return fmt.Errorf("failed to create new http client: %w", err)
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		status, err := client.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("fetching the node status: %w", err)
+		}
+		// start with the blocks of the window which have already been committed
+		height := status.SyncInfo.LatestBlockHeight - int64(watchSigningWindow) + 1
+		if height < status.SyncInfo.EarliestBlockHeight {
+			height = status.SyncInfo.EarliestBlockHeight
+		}
+		latest := status.SyncInfo.LatestBlockHeight
+
+		w := newSigningWatcher(client, address, watchSigningWindow)
+		alerting := false
+		for {
+			for ; height <= latest; height++ {
+				signed, inSet, err := w.signed(ctx, height)
+				if err != nil {
+					return err
+				}
+				if !inSet {
+					fmt.Printf("height %d: not in the validator set\n", height)
+					continue
+				}
+				w.record(signed)
+				signedCount, total := w.participation()
+				rate := 100 * float64(signedCount) / float64(total)
+				outcome := "missed"
+				if signed {
+					outcome = "signed"
+				}
+				fmt.Printf("height %d: %s, participation %.2f%% (%d/%d)\n", height, outcome, rate, signedCount, total)
+
+				switch {
+				case !alerting && rate < watchSigningThreshold:
+					alerting = true
+					logger.Error("Signing participation below threshold",
+						"height", height, "participation", rate, "threshold", watchSigningThreshold)
+				case alerting && rate >= watchSigningThreshold:
+					alerting = false
+					logger.Info("Signing participation recovered",
+						"height", height, "participation", rate, "threshold", watchSigningThreshold)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(watchSigningInterval):
+			}
+			res, err := client.Status(ctx)
+			if err != nil {
+				logger.Error("Failed to fetch the node status", "err", err)
+				continue
+			}
+			latest = res.SyncInfo.LatestBlockHeight
+		}
+	},
+}
+
+// signingWatcher tracks whether a validator signed the most recent blocks.
+type signingWatcher struct {
+	client  rpcclient.SignClient
+	address []byte
+
+	// position of the validator in the last validator set fetched, or -1 if
+	// it's not part of it
+	valsHash []byte
+	index    int
+
+	// ring of the outcomes of the last blocks, true if signed
+	samples []bool
+	next    int
+	full    bool
+}
+
+func newSigningWatcher(client rpcclient.SignClient, address []byte, window int) *signingWatcher {
+	return &signingWatcher{
+		client:  client,
+		address: address,
+		index:   -1,
+		samples: make([]bool, window),
+	}
+}
+
+// signed returns whether the validator signed the block at the given height,
+// as recorded by its commit, and whether it was part of the validator set.
+func (w *signingWatcher) signed(ctx context.Context, height int64) (signed, inSet bool, err error) {
+	res, err := w.client.Commit(ctx, &height)
+	if err != nil {
+		return false, false, fmt.Errorf("fetching the commit of height %d: %w", height, err)
+	}
+
+	// the validator set only needs to be fetched again when it changes
+	if valsHash := res.Header.ValidatorsHash; w.valsHash == nil || !bytes.Equal(valsHash, w.valsHash) {
+		vals, err := fetchValidators(ctx, w.client, height)
+		if err != nil {
+			return false, false, err
+		}
+		w.valsHash, w.index = valsHash, -1
+		for i, val := range vals {
+			if bytes.Equal(val.Address, w.address) {
+				w.index = i
+				break
+			}
+		}
+	}
+	if w.index < 0 {
+		return false, false, nil
+	}
+
+	sigs := res.Commit.Signatures
+	if w.index >= len(sigs) {
+		return false, false, fmt.Errorf("the commit of height %d has %d signatures, expected at least %d",
+			height, len(sigs), w.index+1)
+	}
+	sig := sigs[w.index]
+	return !sig.Absent() && bytes.Equal(sig.ValidatorAddress, w.address), true, nil
+}
+
+// record adds the outcome of a block to the window, replacing the oldest one
+// if the window is full.
+func (w *signingWatcher) record(signed bool) {
+	w.samples[w.next] = signed
+	w.next = (w.next + 1) % len(w.samples)
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+// participation returns the number of blocks signed in the window, and the
+// number of blocks it holds.
+func (w *signingWatcher) participation() (signed, total int) {
+	total = w.next
+	if w.full {
+		total = len(w.samples)
+	}
+	for _, s := range w.samples[:total] {
+		if s {
+			signed++
+		}
+	}
+	return signed, total
+}
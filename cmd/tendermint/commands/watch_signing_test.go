@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// commitsClient serves commits signed by the given validator set, in which
+// absent[height] validators did not sign.
+type commitsClient struct {
+	rpcclient.SignClient
+	vals            *types.ValidatorSet
+	absent          map[int64]int
+	validatorsCalls int
+}
+
+func (c *commitsClient) Commit(_ context.Context, height *int64) (*ctypes.ResultCommit, error) {
+	sigs := make([]types.CommitSig, c.vals.Size())
+	for i, val := range c.vals.Validators {
+		sigs[i] = types.CommitSig{BlockIDFlag: types.BlockIDFlagCommit, ValidatorAddress: val.Address}
+		if idx, ok := c.absent[*height]; ok && idx == i {
+			sigs[i] = types.NewCommitSigAbsent()
+		}
+	}
+	header := &types.Header{Height: *height, ValidatorsHash: c.vals.Hash()}
+	return ctypes.NewResultCommit(header, &types.Commit{Height: *height, Signatures: sigs}, true), nil
+}
+
+func (c *commitsClient) Validators(_ context.Context, height *int64, page, perPage *int) (*ctypes.ResultValidators, error) {
+	c.validatorsCalls++
+	return &ctypes.ResultValidators{BlockHeight: *height, Validators: c.vals.Validators, Count: c.vals.Size(),
+		Total: c.vals.Size()}, nil
+}
+
+func TestSigningWatcher(t *testing.T) {
+	vals := make([]*types.Validator, 3)
+	for i := range vals {
+		vals[i] = types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	}
+	valSet := types.NewValidatorSet(vals)
+	target := valSet.Validators[1]
+	client := &commitsClient{vals: valSet, absent: map[int64]int{2: 1, 3: 0, 4: 1}}
+
+	w := newSigningWatcher(client, target.Address, 3)
+	expected := []bool{true, false, true, false, true}
+	for h := int64(1); h <= 5; h++ {
+		signed, inSet, err := w.signed(context.Background(), h)
+		require.NoError(t, err)
+		require.True(t, inSet)
+		require.Equal(t, expected[h-1], signed, "height %d", h)
+		w.record(signed)
+	}
+	// the validator set is only fetched once
+	require.Equal(t, 1, client.validatorsCalls)
+
+	// the window holds the outcomes of heights 3 to 5
+	signed, total := w.participation()
+	require.Equal(t, 2, signed)
+	require.Equal(t, 3, total)
+
+	// a validator outside of the set
+	w = newSigningWatcher(client, ed25519.GenPrivKey().PubKey().Address(), 3)
+	_, inSet, err := w.signed(context.Background(), 1)
+	require.NoError(t, err)
+	require.False(t, inSet)
+	signed, total = w.participation()
+	require.Zero(t, signed)
+	require.Zero(t, total)
+}
@@ -31,6 +31,26 @@ func main() {
 		cmd.VersionCmd,
 		cmd.RollbackStateCmd,
 		cmd.CompactGoLevelDBCmd,
+		cmd.RetentionStatusCmd,
+		cmd.ExportValidatorStateCmd,
+		cmd.ImportValidatorStateCmd,
+		cmd.WALCheckCmd,
+		cmd.ValidatorDiffCmd,
+		cmd.RPCRoutesCmd,
+		cmd.VerifyIndexStoreConsistencyCmd,
+		cmd.ConsensusParamsHistoryCmd,
+		cmd.TxExportCmd,
+		cmd.PrunePreviewCmd,
+		cmd.ProofHealthcheckCmd,
+		cmd.RebuildBlockIndexCmd,
+		cmd.WatchSigningCmd,
+		cmd.ReplayLoadCmd,
+		cmd.ExportAddrBookCmd,
+		cmd.CheckRPCExposureCmd,
+		cmd.NewFeeReportCmd(nil),
+		cmd.PendingTxsCmd,
+		cmd.VerifyAppHashChainCmd,
+		cmd.MempoolMonitorCmd,
 		debug.DebugCmd,
 		cli.NewCompletionCmd(rootCmd, true),
 	)
@@ -28,6 +28,12 @@ const (
 	// Default is v0.
 	MempoolV0 = "v0"
 	MempoolV1 = "v1"
+
+	// Reaping modes of the v1 mempool. Priority reaps the transactions by
+	// decreasing priority, round-robin interleaves them across senders.
+	// Default is priority.
+	ReapModePriority   = "priority"
+	ReapModeRoundRobin = "round-robin"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -388,6 +394,18 @@ type RPCConfig struct {
 	// Maximum size of request header, in bytes
 	MaxHeaderBytes int `mapstructure:"max_header_bytes"`
 
+	// Event types stripped from the transaction results returned by the tx,
+	// tx_search and related endpoints. Indexing and matching are unaffected.
+	ExcludedEventTypes []string `mapstructure:"excluded_event_types"`
+
+	// Maximum number of tx_search results cached, so that repeated identical
+	// queries are served without hitting the indexer. 0 disables the cache.
+	TxSearchCacheSize int `mapstructure:"tx_search_cache_size"`
+
+	// How long a cached tx_search result is served for. Cached results are
+	// also dropped as soon as a new block is committed.
+	TxSearchCacheTTL time.Duration `mapstructure:"tx_search_cache_ttl"`
+
 	// The path to a file containing certificate that is used to create the HTTPS server.
 	// Might be either absolute path or path related to Tendermint's config directory.
 	//
@@ -432,6 +450,11 @@ func DefaultRPCConfig() *RPCConfig {
 		MaxBodyBytes:   int64(1000000), // 1MB
 		MaxHeaderBytes: 1 << 20,        // same as the net/http default
 
+		ExcludedEventTypes: []string{},
+
+		TxSearchCacheSize: 0,
+		TxSearchCacheTTL:  5 * time.Second,
+
 		TLSCertFile: "",
 		TLSKeyFile:  "",
 	}
@@ -482,6 +505,12 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxHeaderBytes < 0 {
 		return errors.New("max_header_bytes can't be negative")
 	}
+	if cfg.TxSearchCacheSize < 0 {
+		return errors.New("tx_search_cache_size can't be negative")
+	}
+	if cfg.TxSearchCacheTTL < 0 {
+		return errors.New("tx_search_cache_ttl can't be negative")
+	}
 	return nil
 }
 
@@ -720,7 +749,9 @@ type MempoolConfig struct {
 	//
 	// Note, if TTLNumBlocks is also defined, a transaction will be removed if it
 	// has existed in the mempool at least TTLNumBlocks number of blocks or if it's
-	// insertion time into the mempool is beyond TTLDuration.
+	// insertion time into the mempool is beyond TTLDuration. With the priority
+	// mempool (v1), expired transactions are also evicted by CheckTx, without
+	// waiting for the next block.
 	TTLDuration time.Duration `mapstructure:"ttl-duration"`
 
 	// TTLNumBlocks, if non-zero, defines the maximum number of blocks a transaction
@@ -730,6 +761,42 @@ type MempoolConfig struct {
 	// has existed in the mempool at least TTLNumBlocks number of blocks or if
 	// it's insertion time into the mempool is beyond TTLDuration.
 	TTLNumBlocks int64 `mapstructure:"ttl-num-blocks"`
+
+	// CheckTxConcurrency is the maximum number of CheckTx calls that may run
+	// concurrently against an in-process (local) application. Values of 0 and 1
+	// keep the default behaviour of serializing every ABCI call. The v1 mempool
+	// also checks up to this many transactions of a batch at a time, and raises
+	// the number of rechecks in flight after a block (2 per CPU by default) to it
+	// if it is higher; transactions are still inserted in order.
+	//
+	// NOTE: only enable this if the application's CheckTx is thread-safe. It
+	// requires the v1 mempool, and has no effect on out-of-process
+	// applications.
+	CheckTxConcurrency int `mapstructure:"check_tx_concurrency"`
+
+	// MaxInFlightCheckTx is the maximum number of CheckTx requests of new
+	// transactions, from peers and RPC, in flight to the application at any
+	// time. 0 disables the limit.
+	MaxInFlightCheckTx int `mapstructure:"max_in_flight_check_tx"`
+
+	// InFlightCheckTxTimeout is how long a new transaction waits for a CheckTx
+	// request to complete when MaxInFlightCheckTx is reached, before being
+	// rejected. 0 rejects it immediately.
+	InFlightCheckTxTimeout time.Duration `mapstructure:"in_flight_check_tx_timeout"`
+
+	// ReapMode is the order in which the v1 mempool reaps transactions for a
+	// block: "priority" (by decreasing priority) or "round-robin" (one
+	// transaction of each sender in turn, each sender's transactions being
+	// taken by decreasing priority).
+	ReapMode string `mapstructure:"reap_mode"`
+
+	// ReapMaxTxsPerSender is the maximum number of transactions of a single
+	// sender reaped for a block in round-robin mode. 0 disables the limit.
+	ReapMaxTxsPerSender int `mapstructure:"reap_max_txs_per_sender"`
+
+	// MaxTxBytesPerSender is the maximum total size of the transactions of a
+	// single sender in the v1 mempool, in bytes. 0 disables the quota.
+	MaxTxBytesPerSender int64 `mapstructure:"max_tx_bytes_per_sender"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
@@ -747,6 +814,15 @@ func DefaultMempoolConfig() *MempoolConfig {
 		MaxTxBytes:   1024 * 1024, // 1MB
 		TTLDuration:  0 * time.Second,
 		TTLNumBlocks: 0,
+
+		CheckTxConcurrency: 1,
+
+		MaxInFlightCheckTx:     0,
+		InFlightCheckTxTimeout: 100 * time.Millisecond,
+
+		ReapMode:            ReapModePriority,
+		ReapMaxTxsPerSender: 0,
+		MaxTxBytesPerSender: 0,
 	}
 }
 
@@ -782,6 +858,30 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.MaxTxBytes < 0 {
 		return errors.New("max_tx_bytes can't be negative")
 	}
+	if cfg.CheckTxConcurrency < 0 {
+		return errors.New("check_tx_concurrency can't be negative")
+	}
+	if cfg.CheckTxConcurrency > 1 && cfg.Version == MempoolV0 {
+		// the v0 mempool isn't safe for concurrent CheckTx responses
+		return errors.New("check_tx_concurrency above 1 requires the v1 mempool")
+	}
+	if cfg.MaxInFlightCheckTx < 0 {
+		return errors.New("max_in_flight_check_tx can't be negative")
+	}
+	if cfg.InFlightCheckTxTimeout < 0 {
+		return errors.New("in_flight_check_tx_timeout can't be negative")
+	}
+	switch cfg.ReapMode {
+	case "", ReapModePriority, ReapModeRoundRobin:
+	default:
+		return fmt.Errorf("unknown reap_mode %q (must be %q or %q)", cfg.ReapMode, ReapModePriority, ReapModeRoundRobin)
+	}
+	if cfg.ReapMaxTxsPerSender < 0 {
+		return errors.New("reap_max_txs_per_sender can't be negative")
+	}
+	if cfg.MaxTxBytesPerSender < 0 {
+		return errors.New("max_tx_bytes_per_sender can't be negative")
+	}
 	return nil
 }
 
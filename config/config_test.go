@@ -75,6 +75,8 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		"TimeoutBroadcastTxCommit",
 		"MaxBodyBytes",
 		"MaxHeaderBytes",
+		"TxSearchCacheSize",
+		"TxSearchCacheTTL",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -113,6 +115,11 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		"MaxTxsBytes",
 		"CacheSize",
 		"MaxTxBytes",
+		"CheckTxConcurrency",
+		"MaxInFlightCheckTx",
+		"InFlightCheckTxTimeout",
+		"ReapMaxTxsPerSender",
+		"MaxTxBytesPerSender",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -122,6 +129,25 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 	}
 }
 
+func TestMempoolConfigCheckTxConcurrency(t *testing.T) {
+	cfg := TestMempoolConfig()
+	cfg.CheckTxConcurrency = 4
+	cfg.Version = MempoolV1
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.Version = MempoolV0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CheckTxConcurrency = 1
+	assert.NoError(t, cfg.ValidateBasic())
+}
+
+func TestMempoolConfigReapMode(t *testing.T) {
+	cfg := TestMempoolConfig()
+	cfg.ReapMode = ReapModeRoundRobin
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.ReapMode = "fair"
+	assert.Error(t, cfg.ValidateBasic())
+}
+
 func TestStateSyncConfigValidateBasic(t *testing.T) {
 	cfg := TestStateSyncConfig()
 	require.NoError(t, cfg.ValidateBasic())
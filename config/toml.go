@@ -245,6 +245,20 @@ max_body_bytes = {{ .RPC.MaxBodyBytes }}
 # Maximum size of request header, in bytes
 max_header_bytes = {{ .RPC.MaxHeaderBytes }}
 
+# Event types stripped from the transaction results returned by the tx,
+# tx_search and related endpoints, to reduce the size of the responses.
+# Transactions are still indexed, and can still be queried, by these events.
+excluded_event_types = [{{ range .RPC.ExcludedEventTypes }}{{ printf "%q, " . }}{{end}}]
+
+# Maximum number of tx_search results cached, so that repeated identical
+# queries (same query, order, prove flag and page) are served without
+# searching the tx indexer. 0 disables the cache.
+tx_search_cache_size = {{ .RPC.TxSearchCacheSize }}
+
+# How long a cached tx_search result is served for. Cached results are also
+# dropped as soon as a new block is committed.
+tx_search_cache_ttl = "{{ .RPC.TxSearchCacheTTL }}"
+
 # The path to a file containing certificate that is used to create the HTTPS server.
 # Might be either absolute path or path related to Tendermint's config directory.
 # If the certificate is signed by a certificate authority,
@@ -381,7 +395,9 @@ max_batch_bytes = {{ .Mempool.MaxBatchBytes }}
 #
 # Note, if ttl-num-blocks is also defined, a transaction will be removed if it
 # has existed in the mempool at least ttl-num-blocks number of blocks or if it's
-# insertion time into the mempool is beyond ttl-duration.
+# insertion time into the mempool is beyond ttl-duration. With the priority
+# mempool (v1), expired transactions are also evicted by CheckTx, without
+# waiting for the next block.
 ttl-duration = "{{ .Mempool.TTLDuration }}"
 
 # ttl-num-blocks, if non-zero, defines the maximum number of blocks a transaction
@@ -392,6 +408,54 @@ ttl-duration = "{{ .Mempool.TTLDuration }}"
 # it's insertion time into the mempool is beyond ttl-duration.
 ttl-num-blocks = {{ .Mempool.TTLNumBlocks }}
 
+# check_tx_concurrency is the maximum number of CheckTx calls that may run
+# concurrently against an in-process application. 0 or 1 serializes CheckTx
+# with every other ABCI call (the default). The v1 mempool also checks up to
+# this many transactions of a batch at a time, and raises the number of
+# rechecks in flight after a block (2 per CPU by default) to it if it is
+# higher; transactions are still inserted in the mempool in order.
+#
+# Only set this above 1 if the application's CheckTx is thread-safe. CheckTx
+# calls never overlap with other ABCI methods (e.g. DeliverTx or Commit), but
+# their responses may complete out of submission order. It requires the v1
+# mempool (version = "v1"), and has no effect on out-of-process applications.
+check_tx_concurrency = {{ .Mempool.CheckTxConcurrency }}
+
+# max_in_flight_check_tx is the maximum number of CheckTx requests of new
+# transactions, received from peers or over RPC, in flight to the application
+# at any time. 0 disables the limit (the default).
+#
+# Limiting the in-flight requests protects the application from bursts of
+# gossiped transactions, at the cost of latency: when the limit is reached, a
+# new transaction waits up to in_flight_check_tx_timeout for a request to
+# complete, and is rejected otherwise. Rechecks are not limited.
+max_in_flight_check_tx = {{ .Mempool.MaxInFlightCheckTx }}
+in_flight_check_tx_timeout = "{{ .Mempool.InFlightCheckTxTimeout }}"
+
+# reap_mode is the order in which the v1 (prioritized) mempool reaps
+# transactions for a block:
+#  - "priority": by decreasing priority (the default);
+#  - "round-robin": one transaction of each sender in turn, each sender's
+#    transactions being taken by decreasing priority. This prevents a few
+#    high-volume senders from filling the blocks.
+# The sender of a transaction is the one returned by the sender function of
+# the node (see node.MempoolSenderFunc), or else the one assigned by the
+# application in CheckTx. As the mempool keeps a single transaction per sender
+# assigned by the application, round-robin reaping and the per-sender limits
+# below only have an effect with a sender function.
+reap_mode = "{{ .Mempool.ReapMode }}"
+
+# reap_max_txs_per_sender is the maximum number of transactions of a single
+# sender reaped for a block in round-robin mode. 0 disables the limit.
+reap_max_txs_per_sender = {{ .Mempool.ReapMaxTxsPerSender }}
+
+# max_tx_bytes_per_sender is the maximum total size of the transactions of a
+# single sender in the v1 (prioritized) mempool, in bytes, so that a single
+# sender can't fill the mempool with many small transactions. Transactions
+# which would exceed it are rejected, even if the mempool isn't full. The
+# sender is determined as for reap_mode. 0 disables the quota.
+max_tx_bytes_per_sender = {{ .Mempool.MaxTxBytesPerSender }}
+
 #######################################################
 ###         State Sync Configuration Options        ###
 #######################################################
@@ -46,6 +46,27 @@ func TestParser(t *testing.T) {
 
 		{"abci.account.name CONTAINS 'Igor'", true},
 
+		{"abci.account.name MATCHES 'Ig.*'", true},
+		{"abci.account.name MATCHES'I[a-z]+'", true},
+		{"abci.account.name MATCHES ''", true},
+		{"abci.account.name MATCHES 100", false},
+		{"abci.account.name MATCH 'Igor'", false},
+		// invalid regular expressions
+		{"abci.account.name MATCHES 'Ig(or'", false},
+		{"abci.account.name MATCHES '*'", false},
+
+		{"abci.account.name != 'Igor'", true},
+		{"abci.account.name!='Igor'", true},
+		{"account.balance != 100", true},
+		{"tx.date != DATE 2013-05-03", true},
+		{"abci.account.name ! = 'Igor'", false},
+		{"abci.account.name !== 'Igor'", false},
+		{"abci.account.name != ", false},
+		// "!" is only reserved in a key when followed by "="
+		{"abci.account!name = 'Igor'", true},
+		{"abci.account.name! = 'Igor'", true},
+		{"abci.account!name!='Igor'", true},
+
 		{"tx.date > DATE 2013-05-03", true},
 		{"tx.date < DATE 2013-05-03", true},
 		{"tx.date <= DATE 2013-05-03", true},
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +26,9 @@ var (
 type Query struct {
 	str    string
 	parser *QueryParser
+
+	// the compiled operands of the MATCHES conditions, by pattern
+	regexps map[string]*regexp.Regexp
 }
 
 // Condition represents a single condition within a query and consists of composite key
@@ -43,7 +47,49 @@ func New(s string) (*Query, error) {
 	if err := p.Parse(); err != nil {
 		return nil, err
 	}
-	return &Query{str: s, parser: p}, nil
+	q := &Query{str: s, parser: p}
+	if err := q.compileRegexps(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// compileRegexps compiles the operands of the MATCHES conditions, so that an
+// invalid regular expression is reported when the query is parsed.
+func (q *Query) compileRegexps() error {
+	conditions, err := q.Conditions()
+	if err != nil {
+		return err
+	}
+	for _, c := range conditions {
+		if c.Op != OpMatches {
+			continue
+		}
+		pattern := c.Operand.(string)
+		if _, ok := q.regexps[pattern]; ok {
+			continue
+		}
+		re, err := CompileMatches(pattern)
+		if err != nil {
+			return err
+		}
+		if q.regexps == nil {
+			q.regexps = make(map[string]*regexp.Regexp)
+		}
+		q.regexps[pattern] = re
+	}
+	return nil
+}
+
+// CompileMatches compiles the operand of a MATCHES condition. The regular
+// expression is anchored: it must match the whole value, not a substring of
+// it (use CONTAINS for that).
+func CompileMatches(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+	}
+	return re, nil
 }
 
 // MustParse turns the given string into a query or panics; for tests or others
@@ -80,6 +126,12 @@ const (
 	OpContains
 	// "EXISTS"; used to check if a certain event attribute is present.
 	OpExists
+	// "!="; used to check that a certain event attribute is present, but none
+	// of its values is equal to the operand.
+	OpNotEqual
+	// "MATCHES"; used to check if a string matches a regular expression, which
+	// is anchored at both ends.
+	OpMatches
 )
 
 const (
@@ -124,9 +176,15 @@ func (q *Query) Conditions() ([]Condition, error) {
 		case ruleequal:
 			op = OpEqual
 
+		case rulenotEqual:
+			op = OpNotEqual
+
 		case rulecontains:
 			op = OpContains
 
+		case rulematches:
+			op = OpMatches
+
 		case ruleexists:
 			op = OpExists
 			conditions = append(conditions, Condition{eventAttr, op, nil})
@@ -236,8 +294,15 @@ func (q *Query) Matches(events map[string][]string) (bool, error) {
 		case ruleequal:
 			op = OpEqual
 
+		case rulenotEqual:
+			op = OpNotEqual
+
 		case rulecontains:
 			op = OpContains
+
+		case rulematches:
+			op = OpMatches
+
 		case ruleexists:
 			op = OpExists
 			if strings.Contains(eventAttr, ".") {
@@ -265,6 +330,13 @@ func (q *Query) Matches(events map[string][]string) (bool, error) {
 			// strip single quotes from value (i.e. "'NewBlock'" -> "NewBlock")
 			valueWithoutSingleQuotes := buffer[begin+1 : end-1]
 
+			if op == OpMatches {
+				if !matchRegexp(eventAttr, q.regexps[valueWithoutSingleQuotes], events) {
+					return false, nil
+				}
+				continue
+			}
+
 			// see if the triplet (event attribute, operator, operand) matches any event
 			// "tx.gas", "=", "7", { "tx.gas": 7, "tx.ID": "4AE393495334" }
 			match, err := match(eventAttr, op, reflect.ValueOf(valueWithoutSingleQuotes), events)
@@ -359,6 +431,88 @@ func (q *Query) Matches(events map[string][]string) (bool, error) {
 	return true, nil
 }
 
+// MatchedAttribute is an event attribute value which satisfies a condition of
+// a query.
+type MatchedAttribute struct {
+	CompositeKey string
+	Value        string
+}
+
+// MatchedAttributes returns the attribute values of the given events which
+// satisfy a condition of the query, in the order of the conditions, without
+// duplicates. Unlike Matches, it doesn't require all the conditions to be
+// satisfied: it tells which attributes a match is due to. A value which
+// can't be compared to the operand of a condition (e.g. "abc" to a number)
+// doesn't satisfy it.
+//
+// For example, query "tx.gas > 5 AND tx.name EXISTS" against events
+// {"tx.gas": ["3", "7"], "tx.name": ["John"]} returns tx.gas=7 and
+// tx.name=John.
+func (q *Query) MatchedAttributes(events map[string][]string) ([]MatchedAttribute, error) {
+	conditions, err := q.Conditions()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matched []MatchedAttribute
+		seen    = make(map[MatchedAttribute]struct{})
+	)
+	add := func(key, value string) {
+		m := MatchedAttribute{CompositeKey: key, Value: value}
+		if _, ok := seen[m]; !ok {
+			seen[m] = struct{}{}
+			matched = append(matched, m)
+		}
+	}
+
+	for _, c := range conditions {
+		switch c.Op {
+		case OpExists:
+			// as in Matches, a key without a "." matches any attribute of
+			// that event type
+			keys := make([]string, 0, 1)
+			for compositeKey := range events {
+				if compositeKey == c.CompositeKey ||
+					(!strings.Contains(c.CompositeKey, ".") && strings.Index(compositeKey, c.CompositeKey) == 0) {
+					keys = append(keys, compositeKey)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				for _, value := range events[key] {
+					add(key, value)
+				}
+			}
+
+		case OpNotEqual:
+			ok, err := match(c.CompositeKey, c.Op, reflect.ValueOf(c.Operand), events)
+			if err != nil || !ok {
+				continue
+			}
+			for _, value := range events[c.CompositeKey] {
+				add(c.CompositeKey, value)
+			}
+
+		case OpMatches:
+			re := q.regexps[c.Operand.(string)]
+			for _, value := range events[c.CompositeKey] {
+				if re.MatchString(value) {
+					add(c.CompositeKey, value)
+				}
+			}
+
+		default:
+			for _, value := range events[c.CompositeKey] {
+				if ok, err := matchValue(value, c.Op, reflect.ValueOf(c.Operand)); err == nil && ok {
+					add(c.CompositeKey, value)
+				}
+			}
+		}
+	}
+	return matched, nil
+}
+
 // match returns true if the given triplet (attribute, operator, operand) matches
 // any value in an event for that attribute. If any match fails with an error,
 // that error is returned.
@@ -374,6 +528,22 @@ func match(attr string, op Operator, operand reflect.Value, events map[string][]
 		return false, nil
 	}
 
+	// a negation matches only if none of the values is equal to the operand
+	if op == OpNotEqual {
+		for _, value := range values {
+			equal, err := matchValue(value, OpEqual, operand)
+			if err != nil {
+				return false, err
+			}
+
+			if equal {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+
 	for _, value := range values {
 		// return true if any value in the set of the event's values matches
 		match, err := matchValue(value, op, operand)
@@ -389,6 +559,17 @@ func match(attr string, op Operator, operand reflect.Value, events map[string][]
 	return false, nil
 }
 
+// matchRegexp returns true if any value in an event for the given attribute
+// matches the regular expression.
+func matchRegexp(attr string, re *regexp.Regexp, events map[string][]string) bool {
+	for _, value := range events[attr] {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchValue will attempt to match a string value against an operator an
 // operand. A boolean is returned representing the match result. It will return
 // an error if the value cannot be parsed and matched against the operand type.
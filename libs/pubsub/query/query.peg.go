@@ -27,7 +27,9 @@ const (
 	ruleday
 	ruleand
 	ruleequal
+	rulenotEqual
 	rulecontains
+	rulematches
 	ruleexists
 	rulele
 	rulege
@@ -55,7 +57,9 @@ var rul3s = [...]string{
 	"day",
 	"and",
 	"equal",
+	"notEqual",
 	"contains",
+	"matches",
 	"exists",
 	"le",
 	"ge",
@@ -355,7 +359,7 @@ func (t *tokens32) Expand(index int) {
 type QueryParser struct {
 	Buffer string
 	buffer []rune
-	rules  [21]func() bool
+	rules  [23]func() bool
 	Parse  func(rule ...int) error
 	Reset  func()
 	Pretty bool
@@ -618,7 +622,7 @@ func (p *QueryParser) Init() {
 			position, tokenIndex, depth = position0, tokenIndex0, depth0
 			return false
 		},
-		/* 1 condition <- <(tag ' '* ((le ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number))) / (ge ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number))) / ((&('E' | 'e') exists) | (&('=') (equal ' '* ((&('\'') value) | (&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('>') (g ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('<') (l ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('C' | 'c') (contains ' '* value)))))> */
+		/* 1 condition <- <(tag ' '* ((le ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number))) / (ge ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number))) / ((&('E' | 'e') exists) | (&('M' | 'm') (matches ' '* value)) | (&('!') (notEqual ' '* ((&('\'') value) | (&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('=') (equal ' '* ((&('\'') value) | (&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('>') (g ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('<') (l ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('C' | 'c') (contains ' '* value)))))> */
 		func() bool {
 			position16, tokenIndex16, depth16 := position, tokenIndex, depth
 			{
@@ -713,6 +717,20 @@ func (p *QueryParser) Init() {
 						l22:
 							position, tokenIndex, depth = position22, tokenIndex22, depth22
 						}
+						{
+							position24, tokenIndex24, depth24 := position, tokenIndex, depth
+							if buffer[position] != rune('!') {
+								goto l24
+							}
+							position++
+							if buffer[position] != rune('=') {
+								goto l24
+							}
+							position++
+							goto l16
+						l24:
+							position, tokenIndex, depth = position24, tokenIndex24, depth24
+						}
 						if !matchDot() {
 							goto l16
 						}
@@ -720,78 +738,78 @@ func (p *QueryParser) Init() {
 						{
 							position21, tokenIndex21, depth21 := position, tokenIndex, depth
 							{
-								position24, tokenIndex24, depth24 := position, tokenIndex, depth
+								position25, tokenIndex25, depth25 := position, tokenIndex, depth
 								{
 									switch buffer[position] {
 									case '<':
 										if buffer[position] != rune('<') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '>':
 										if buffer[position] != rune('>') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '=':
 										if buffer[position] != rune('=') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '\'':
 										if buffer[position] != rune('\'') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '"':
 										if buffer[position] != rune('"') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case ')':
 										if buffer[position] != rune(')') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '(':
 										if buffer[position] != rune('(') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '\\':
 										if buffer[position] != rune('\\') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '\r':
 										if buffer[position] != rune('\r') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '\n':
 										if buffer[position] != rune('\n') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									case '\t':
 										if buffer[position] != rune('\t') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
 									default:
 										if buffer[position] != rune(' ') {
-											goto l24
+											goto l25
 										}
 										position++
 										break
@@ -799,8 +817,22 @@ func (p *QueryParser) Init() {
 								}
 
 								goto l21
-							l24:
-								position, tokenIndex, depth = position24, tokenIndex24, depth24
+							l25:
+								position, tokenIndex, depth = position25, tokenIndex25, depth25
+							}
+							{
+								position27, tokenIndex27, depth27 := position, tokenIndex, depth
+								if buffer[position] != rune('!') {
+									goto l27
+								}
+								position++
+								if buffer[position] != rune('=') {
+									goto l27
+								}
+								position++
+								goto l21
+							l27:
+								position, tokenIndex, depth = position27, tokenIndex27, depth27
 							}
 							if !matchDot() {
 								goto l21
@@ -815,146 +847,131 @@ func (p *QueryParser) Init() {
 					depth--
 					add(ruletag, position18)
 				}
-			l26:
+			l28:
 				{
-					position27, tokenIndex27, depth27 := position, tokenIndex, depth
+					position29, tokenIndex29, depth29 := position, tokenIndex, depth
 					if buffer[position] != rune(' ') {
-						goto l27
+						goto l29
 					}
 					position++
-					goto l26
-				l27:
-					position, tokenIndex, depth = position27, tokenIndex27, depth27
+					goto l28
+				l29:
+					position, tokenIndex, depth = position29, tokenIndex29, depth29
 				}
 				{
-					position28, tokenIndex28, depth28 := position, tokenIndex, depth
+					position30, tokenIndex30, depth30 := position, tokenIndex, depth
 					{
-						position30 := position
+						position32 := position
 						depth++
 						if buffer[position] != rune('<') {
-							goto l29
+							goto l31
 						}
 						position++
 						if buffer[position] != rune('=') {
-							goto l29
+							goto l31
 						}
 						position++
 						depth--
-						add(rulele, position30)
+						add(rulele, position32)
 					}
-				l31:
+				l33:
 					{
-						position32, tokenIndex32, depth32 := position, tokenIndex, depth
+						position34, tokenIndex34, depth34 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l32
+							goto l34
 						}
 						position++
-						goto l31
-					l32:
-						position, tokenIndex, depth = position32, tokenIndex32, depth32
+						goto l33
+					l34:
+						position, tokenIndex, depth = position34, tokenIndex34, depth34
 					}
 					{
 						switch buffer[position] {
 						case 'D', 'd':
 							if !_rules[ruledate]() {
-								goto l29
+								goto l31
 							}
 							break
 						case 'T', 't':
 							if !_rules[ruletime]() {
-								goto l29
+								goto l31
 							}
 							break
 						default:
 							if !_rules[rulenumber]() {
-								goto l29
+								goto l31
 							}
 							break
 						}
 					}
 
-					goto l28
-				l29:
-					position, tokenIndex, depth = position28, tokenIndex28, depth28
+					goto l30
+				l31:
+					position, tokenIndex, depth = position30, tokenIndex30, depth30
 					{
-						position35 := position
+						position37 := position
 						depth++
 						if buffer[position] != rune('>') {
-							goto l34
+							goto l36
 						}
 						position++
 						if buffer[position] != rune('=') {
-							goto l34
+							goto l36
 						}
 						position++
 						depth--
-						add(rulege, position35)
+						add(rulege, position37)
 					}
-				l36:
+				l38:
 					{
-						position37, tokenIndex37, depth37 := position, tokenIndex, depth
+						position39, tokenIndex39, depth39 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l37
+							goto l39
 						}
 						position++
-						goto l36
-					l37:
-						position, tokenIndex, depth = position37, tokenIndex37, depth37
+						goto l38
+					l39:
+						position, tokenIndex, depth = position39, tokenIndex39, depth39
 					}
 					{
 						switch buffer[position] {
 						case 'D', 'd':
 							if !_rules[ruledate]() {
-								goto l34
+								goto l36
 							}
 							break
 						case 'T', 't':
 							if !_rules[ruletime]() {
-								goto l34
+								goto l36
 							}
 							break
 						default:
 							if !_rules[rulenumber]() {
-								goto l34
+								goto l36
 							}
 							break
 						}
 					}
 
-					goto l28
-				l34:
-					position, tokenIndex, depth = position28, tokenIndex28, depth28
+					goto l30
+				l36:
+					position, tokenIndex, depth = position30, tokenIndex30, depth30
 					{
 						switch buffer[position] {
 						case 'E', 'e':
 							{
-								position40 := position
+								position42 := position
 								depth++
-								{
-									position41, tokenIndex41, depth41 := position, tokenIndex, depth
-									if buffer[position] != rune('e') {
-										goto l42
-									}
-									position++
-									goto l41
-								l42:
-									position, tokenIndex, depth = position41, tokenIndex41, depth41
-									if buffer[position] != rune('E') {
-										goto l16
-									}
-									position++
-								}
-							l41:
 								{
 									position43, tokenIndex43, depth43 := position, tokenIndex, depth
-									if buffer[position] != rune('x') {
+									if buffer[position] != rune('e') {
 										goto l44
 									}
 									position++
 									goto l43
 								l44:
 									position, tokenIndex, depth = position43, tokenIndex43, depth43
-									if buffer[position] != rune('X') {
+									if buffer[position] != rune('E') {
 										goto l16
 									}
 									position++
@@ -962,14 +979,14 @@ func (p *QueryParser) Init() {
 							l43:
 								{
 									position45, tokenIndex45, depth45 := position, tokenIndex, depth
-									if buffer[position] != rune('i') {
+									if buffer[position] != rune('x') {
 										goto l46
 									}
 									position++
 									goto l45
 								l46:
 									position, tokenIndex, depth = position45, tokenIndex45, depth45
-									if buffer[position] != rune('I') {
+									if buffer[position] != rune('X') {
 										goto l16
 									}
 									position++
@@ -977,14 +994,14 @@ func (p *QueryParser) Init() {
 							l45:
 								{
 									position47, tokenIndex47, depth47 := position, tokenIndex, depth
-									if buffer[position] != rune('s') {
+									if buffer[position] != rune('i') {
 										goto l48
 									}
 									position++
 									goto l47
 								l48:
 									position, tokenIndex, depth = position47, tokenIndex47, depth47
-									if buffer[position] != rune('S') {
+									if buffer[position] != rune('I') {
 										goto l16
 									}
 									position++
@@ -992,14 +1009,14 @@ func (p *QueryParser) Init() {
 							l47:
 								{
 									position49, tokenIndex49, depth49 := position, tokenIndex, depth
-									if buffer[position] != rune('t') {
+									if buffer[position] != rune('s') {
 										goto l50
 									}
 									position++
 									goto l49
 								l50:
 									position, tokenIndex, depth = position49, tokenIndex49, depth49
-									if buffer[position] != rune('T') {
+									if buffer[position] != rune('S') {
 										goto l16
 									}
 									position++
@@ -1007,44 +1024,238 @@ func (p *QueryParser) Init() {
 							l49:
 								{
 									position51, tokenIndex51, depth51 := position, tokenIndex, depth
-									if buffer[position] != rune('s') {
+									if buffer[position] != rune('t') {
 										goto l52
 									}
 									position++
 									goto l51
 								l52:
 									position, tokenIndex, depth = position51, tokenIndex51, depth51
-									if buffer[position] != rune('S') {
+									if buffer[position] != rune('T') {
 										goto l16
 									}
 									position++
 								}
 							l51:
+								{
+									position53, tokenIndex53, depth53 := position, tokenIndex, depth
+									if buffer[position] != rune('s') {
+										goto l54
+									}
+									position++
+									goto l53
+								l54:
+									position, tokenIndex, depth = position53, tokenIndex53, depth53
+									if buffer[position] != rune('S') {
+										goto l16
+									}
+									position++
+								}
+							l53:
 								depth--
-								add(ruleexists, position40)
+								add(ruleexists, position42)
 							}
+							break
+						case 'M', 'm':
+							{
+								position55 := position
+								depth++
+								{
+									position56, tokenIndex56, depth56 := position, tokenIndex, depth
+									if buffer[position] != rune('m') {
+										goto l57
+									}
+									position++
+									goto l56
+								l57:
+									position, tokenIndex, depth = position56, tokenIndex56, depth56
+									if buffer[position] != rune('M') {
+										goto l16
+									}
+									position++
+								}
+							l56:
+								{
+									position58, tokenIndex58, depth58 := position, tokenIndex, depth
+									if buffer[position] != rune('a') {
+										goto l59
+									}
+									position++
+									goto l58
+								l59:
+									position, tokenIndex, depth = position58, tokenIndex58, depth58
+									if buffer[position] != rune('A') {
+										goto l16
+									}
+									position++
+								}
+							l58:
+								{
+									position60, tokenIndex60, depth60 := position, tokenIndex, depth
+									if buffer[position] != rune('t') {
+										goto l61
+									}
+									position++
+									goto l60
+								l61:
+									position, tokenIndex, depth = position60, tokenIndex60, depth60
+									if buffer[position] != rune('T') {
+										goto l16
+									}
+									position++
+								}
+							l60:
+								{
+									position62, tokenIndex62, depth62 := position, tokenIndex, depth
+									if buffer[position] != rune('c') {
+										goto l63
+									}
+									position++
+									goto l62
+								l63:
+									position, tokenIndex, depth = position62, tokenIndex62, depth62
+									if buffer[position] != rune('C') {
+										goto l16
+									}
+									position++
+								}
+							l62:
+								{
+									position64, tokenIndex64, depth64 := position, tokenIndex, depth
+									if buffer[position] != rune('h') {
+										goto l65
+									}
+									position++
+									goto l64
+								l65:
+									position, tokenIndex, depth = position64, tokenIndex64, depth64
+									if buffer[position] != rune('H') {
+										goto l16
+									}
+									position++
+								}
+							l64:
+								{
+									position66, tokenIndex66, depth66 := position, tokenIndex, depth
+									if buffer[position] != rune('e') {
+										goto l67
+									}
+									position++
+									goto l66
+								l67:
+									position, tokenIndex, depth = position66, tokenIndex66, depth66
+									if buffer[position] != rune('E') {
+										goto l16
+									}
+									position++
+								}
+							l66:
+								{
+									position68, tokenIndex68, depth68 := position, tokenIndex, depth
+									if buffer[position] != rune('s') {
+										goto l69
+									}
+									position++
+									goto l68
+								l69:
+									position, tokenIndex, depth = position68, tokenIndex68, depth68
+									if buffer[position] != rune('S') {
+										goto l16
+									}
+									position++
+								}
+							l68:
+								depth--
+								add(rulematches, position55)
+							}
+						l70:
+							{
+								position71, tokenIndex71, depth71 := position, tokenIndex, depth
+								if buffer[position] != rune(' ') {
+									goto l71
+								}
+								position++
+								goto l70
+							l71:
+								position, tokenIndex, depth = position71, tokenIndex71, depth71
+							}
+							if !_rules[rulevalue]() {
+								goto l16
+							}
+							break
+						case '!':
+							{
+								position72 := position
+								depth++
+								if buffer[position] != rune('!') {
+									goto l16
+								}
+								position++
+								if buffer[position] != rune('=') {
+									goto l16
+								}
+								position++
+								depth--
+								add(rulenotEqual, position72)
+							}
+						l73:
+							{
+								position74, tokenIndex74, depth74 := position, tokenIndex, depth
+								if buffer[position] != rune(' ') {
+									goto l74
+								}
+								position++
+								goto l73
+							l74:
+								position, tokenIndex, depth = position74, tokenIndex74, depth74
+							}
+							{
+								switch buffer[position] {
+								case '\'':
+									if !_rules[rulevalue]() {
+										goto l16
+									}
+									break
+								case 'D', 'd':
+									if !_rules[ruledate]() {
+										goto l16
+									}
+									break
+								case 'T', 't':
+									if !_rules[ruletime]() {
+										goto l16
+									}
+									break
+								default:
+									if !_rules[rulenumber]() {
+										goto l16
+									}
+									break
+								}
+							}
+
 							break
 						case '=':
 							{
-								position53 := position
+								position76 := position
 								depth++
 								if buffer[position] != rune('=') {
 									goto l16
 								}
 								position++
 								depth--
-								add(ruleequal, position53)
+								add(ruleequal, position76)
 							}
-						l54:
+						l77:
 							{
-								position55, tokenIndex55, depth55 := position, tokenIndex, depth
+								position78, tokenIndex78, depth78 := position, tokenIndex, depth
 								if buffer[position] != rune(' ') {
-									goto l55
+									goto l78
 								}
 								position++
-								goto l54
-							l55:
-								position, tokenIndex, depth = position55, tokenIndex55, depth55
+								goto l77
+							l78:
+								position, tokenIndex, depth = position78, tokenIndex78, depth78
 							}
 							{
 								switch buffer[position] {
@@ -1074,25 +1285,25 @@ func (p *QueryParser) Init() {
 							break
 						case '>':
 							{
-								position57 := position
+								position80 := position
 								depth++
 								if buffer[position] != rune('>') {
 									goto l16
 								}
 								position++
 								depth--
-								add(ruleg, position57)
+								add(ruleg, position80)
 							}
-						l58:
+						l81:
 							{
-								position59, tokenIndex59, depth59 := position, tokenIndex, depth
+								position82, tokenIndex82, depth82 := position, tokenIndex, depth
 								if buffer[position] != rune(' ') {
-									goto l59
+									goto l82
 								}
 								position++
-								goto l58
-							l59:
-								position, tokenIndex, depth = position59, tokenIndex59, depth59
+								goto l81
+							l82:
+								position, tokenIndex, depth = position82, tokenIndex82, depth82
 							}
 							{
 								switch buffer[position] {
@@ -1117,25 +1328,25 @@ func (p *QueryParser) Init() {
 							break
 						case '<':
 							{
-								position61 := position
+								position84 := position
 								depth++
 								if buffer[position] != rune('<') {
 									goto l16
 								}
 								position++
 								depth--
-								add(rulel, position61)
+								add(rulel, position84)
 							}
-						l62:
+						l85:
 							{
-								position63, tokenIndex63, depth63 := position, tokenIndex, depth
+								position86, tokenIndex86, depth86 := position, tokenIndex, depth
 								if buffer[position] != rune(' ') {
-									goto l63
+									goto l86
 								}
 								position++
-								goto l62
-							l63:
-								position, tokenIndex, depth = position63, tokenIndex63, depth63
+								goto l85
+							l86:
+								position, tokenIndex, depth = position86, tokenIndex86, depth86
 							}
 							{
 								switch buffer[position] {
@@ -1160,141 +1371,141 @@ func (p *QueryParser) Init() {
 							break
 						default:
 							{
-								position65 := position
+								position88 := position
 								depth++
 								{
-									position66, tokenIndex66, depth66 := position, tokenIndex, depth
+									position89, tokenIndex89, depth89 := position, tokenIndex, depth
 									if buffer[position] != rune('c') {
-										goto l67
+										goto l90
 									}
 									position++
-									goto l66
-								l67:
-									position, tokenIndex, depth = position66, tokenIndex66, depth66
+									goto l89
+								l90:
+									position, tokenIndex, depth = position89, tokenIndex89, depth89
 									if buffer[position] != rune('C') {
 										goto l16
 									}
 									position++
 								}
-							l66:
+							l89:
 								{
-									position68, tokenIndex68, depth68 := position, tokenIndex, depth
+									position91, tokenIndex91, depth91 := position, tokenIndex, depth
 									if buffer[position] != rune('o') {
-										goto l69
+										goto l92
 									}
 									position++
-									goto l68
-								l69:
-									position, tokenIndex, depth = position68, tokenIndex68, depth68
+									goto l91
+								l92:
+									position, tokenIndex, depth = position91, tokenIndex91, depth91
 									if buffer[position] != rune('O') {
 										goto l16
 									}
 									position++
 								}
-							l68:
+							l91:
 								{
-									position70, tokenIndex70, depth70 := position, tokenIndex, depth
+									position93, tokenIndex93, depth93 := position, tokenIndex, depth
 									if buffer[position] != rune('n') {
-										goto l71
+										goto l94
 									}
 									position++
-									goto l70
-								l71:
-									position, tokenIndex, depth = position70, tokenIndex70, depth70
+									goto l93
+								l94:
+									position, tokenIndex, depth = position93, tokenIndex93, depth93
 									if buffer[position] != rune('N') {
 										goto l16
 									}
 									position++
 								}
-							l70:
+							l93:
 								{
-									position72, tokenIndex72, depth72 := position, tokenIndex, depth
+									position95, tokenIndex95, depth95 := position, tokenIndex, depth
 									if buffer[position] != rune('t') {
-										goto l73
+										goto l96
 									}
 									position++
-									goto l72
-								l73:
-									position, tokenIndex, depth = position72, tokenIndex72, depth72
+									goto l95
+								l96:
+									position, tokenIndex, depth = position95, tokenIndex95, depth95
 									if buffer[position] != rune('T') {
 										goto l16
 									}
 									position++
 								}
-							l72:
+							l95:
 								{
-									position74, tokenIndex74, depth74 := position, tokenIndex, depth
+									position97, tokenIndex97, depth97 := position, tokenIndex, depth
 									if buffer[position] != rune('a') {
-										goto l75
+										goto l98
 									}
 									position++
-									goto l74
-								l75:
-									position, tokenIndex, depth = position74, tokenIndex74, depth74
+									goto l97
+								l98:
+									position, tokenIndex, depth = position97, tokenIndex97, depth97
 									if buffer[position] != rune('A') {
 										goto l16
 									}
 									position++
 								}
-							l74:
+							l97:
 								{
-									position76, tokenIndex76, depth76 := position, tokenIndex, depth
+									position99, tokenIndex99, depth99 := position, tokenIndex, depth
 									if buffer[position] != rune('i') {
-										goto l77
+										goto l100
 									}
 									position++
-									goto l76
-								l77:
-									position, tokenIndex, depth = position76, tokenIndex76, depth76
+									goto l99
+								l100:
+									position, tokenIndex, depth = position99, tokenIndex99, depth99
 									if buffer[position] != rune('I') {
 										goto l16
 									}
 									position++
 								}
-							l76:
+							l99:
 								{
-									position78, tokenIndex78, depth78 := position, tokenIndex, depth
+									position101, tokenIndex101, depth101 := position, tokenIndex, depth
 									if buffer[position] != rune('n') {
-										goto l79
+										goto l102
 									}
 									position++
-									goto l78
-								l79:
-									position, tokenIndex, depth = position78, tokenIndex78, depth78
+									goto l101
+								l102:
+									position, tokenIndex, depth = position101, tokenIndex101, depth101
 									if buffer[position] != rune('N') {
 										goto l16
 									}
 									position++
 								}
-							l78:
+							l101:
 								{
-									position80, tokenIndex80, depth80 := position, tokenIndex, depth
+									position103, tokenIndex103, depth103 := position, tokenIndex, depth
 									if buffer[position] != rune('s') {
-										goto l81
+										goto l104
 									}
 									position++
-									goto l80
-								l81:
-									position, tokenIndex, depth = position80, tokenIndex80, depth80
+									goto l103
+								l104:
+									position, tokenIndex, depth = position103, tokenIndex103, depth103
 									if buffer[position] != rune('S') {
 										goto l16
 									}
 									position++
 								}
-							l80:
+							l103:
 								depth--
-								add(rulecontains, position65)
+								add(rulecontains, position88)
 							}
-						l82:
+						l105:
 							{
-								position83, tokenIndex83, depth83 := position, tokenIndex, depth
+								position106, tokenIndex106, depth106 := position, tokenIndex, depth
 								if buffer[position] != rune(' ') {
-									goto l83
+									goto l106
 								}
 								position++
-								goto l82
-							l83:
-								position, tokenIndex, depth = position83, tokenIndex83, depth83
+								goto l105
+							l106:
+								position, tokenIndex, depth = position106, tokenIndex106, depth106
 							}
 							if !_rules[rulevalue]() {
 								goto l16
@@ -1304,7 +1515,7 @@ func (p *QueryParser) Init() {
 					}
 
 				}
-			l28:
+			l30:
 				depth--
 				add(rulecondition, position17)
 			}
@@ -1313,524 +1524,524 @@ func (p *QueryParser) Init() {
 			position, tokenIndex, depth = position16, tokenIndex16, depth16
 			return false
 		},
-		/* 2 tag <- <<(!((&('<') '<') | (&('>') '>') | (&('=') '=') | (&('\'') '\'') | (&('"') '"') | (&(')') ')') | (&('(') '(') | (&('\\') '\\') | (&('\r') '\r') | (&('\n') '\n') | (&('\t') '\t') | (&(' ') ' ')) .)+>> */
+		/* 2 tag <- <<(!((&('<') '<') | (&('>') '>') | (&('=') '=') | (&('\'') '\'') | (&('"') '"') | (&(')') ')') | (&('(') '(') | (&('\\') '\\') | (&('\r') '\r') | (&('\n') '\n') | (&('\t') '\t') | (&(' ') ' ')) !('!' '=') .)+>> */
 		nil,
 		/* 3 value <- <<('\'' (!('"' / '\'') .)* '\'')>> */
 		func() bool {
-			position85, tokenIndex85, depth85 := position, tokenIndex, depth
+			position108, tokenIndex108, depth108 := position, tokenIndex, depth
 			{
-				position86 := position
+				position109 := position
 				depth++
 				{
-					position87 := position
+					position110 := position
 					depth++
 					if buffer[position] != rune('\'') {
-						goto l85
+						goto l108
 					}
 					position++
-				l88:
+				l111:
 					{
-						position89, tokenIndex89, depth89 := position, tokenIndex, depth
+						position112, tokenIndex112, depth112 := position, tokenIndex, depth
 						{
-							position90, tokenIndex90, depth90 := position, tokenIndex, depth
+							position113, tokenIndex113, depth113 := position, tokenIndex, depth
 							{
-								position91, tokenIndex91, depth91 := position, tokenIndex, depth
+								position114, tokenIndex114, depth114 := position, tokenIndex, depth
 								if buffer[position] != rune('"') {
-									goto l92
+									goto l115
 								}
 								position++
-								goto l91
-							l92:
-								position, tokenIndex, depth = position91, tokenIndex91, depth91
+								goto l114
+							l115:
+								position, tokenIndex, depth = position114, tokenIndex114, depth114
 								if buffer[position] != rune('\'') {
-									goto l90
+									goto l113
 								}
 								position++
 							}
-						l91:
-							goto l89
-						l90:
-							position, tokenIndex, depth = position90, tokenIndex90, depth90
+						l114:
+							goto l112
+						l113:
+							position, tokenIndex, depth = position113, tokenIndex113, depth113
 						}
 						if !matchDot() {
-							goto l89
+							goto l112
 						}
-						goto l88
-					l89:
-						position, tokenIndex, depth = position89, tokenIndex89, depth89
+						goto l111
+					l112:
+						position, tokenIndex, depth = position112, tokenIndex112, depth112
 					}
 					if buffer[position] != rune('\'') {
-						goto l85
+						goto l108
 					}
 					position++
 					depth--
-					add(rulePegText, position87)
+					add(rulePegText, position110)
 				}
 				depth--
-				add(rulevalue, position86)
+				add(rulevalue, position109)
 			}
 			return true
-		l85:
-			position, tokenIndex, depth = position85, tokenIndex85, depth85
+		l108:
+			position, tokenIndex, depth = position108, tokenIndex108, depth108
 			return false
 		},
 		/* 4 number <- <<('0' / ([1-9] digit* ('.' digit*)?))>> */
 		func() bool {
-			position93, tokenIndex93, depth93 := position, tokenIndex, depth
+			position116, tokenIndex116, depth116 := position, tokenIndex, depth
 			{
-				position94 := position
+				position117 := position
 				depth++
 				{
-					position95 := position
+					position118 := position
 					depth++
 					{
-						position96, tokenIndex96, depth96 := position, tokenIndex, depth
+						position119, tokenIndex119, depth119 := position, tokenIndex, depth
 						if buffer[position] != rune('0') {
-							goto l97
+							goto l120
 						}
 						position++
-						goto l96
-					l97:
-						position, tokenIndex, depth = position96, tokenIndex96, depth96
+						goto l119
+					l120:
+						position, tokenIndex, depth = position119, tokenIndex119, depth119
 						if c := buffer[position]; c < rune('1') || c > rune('9') {
-							goto l93
+							goto l116
 						}
 						position++
-					l98:
+					l121:
 						{
-							position99, tokenIndex99, depth99 := position, tokenIndex, depth
+							position122, tokenIndex122, depth122 := position, tokenIndex, depth
 							if !_rules[ruledigit]() {
-								goto l99
+								goto l122
 							}
-							goto l98
-						l99:
-							position, tokenIndex, depth = position99, tokenIndex99, depth99
+							goto l121
+						l122:
+							position, tokenIndex, depth = position122, tokenIndex122, depth122
 						}
 						{
-							position100, tokenIndex100, depth100 := position, tokenIndex, depth
+							position123, tokenIndex123, depth123 := position, tokenIndex, depth
 							if buffer[position] != rune('.') {
-								goto l100
+								goto l123
 							}
 							position++
-						l102:
+						l125:
 							{
-								position103, tokenIndex103, depth103 := position, tokenIndex, depth
+								position126, tokenIndex126, depth126 := position, tokenIndex, depth
 								if !_rules[ruledigit]() {
-									goto l103
+									goto l126
 								}
-								goto l102
-							l103:
-								position, tokenIndex, depth = position103, tokenIndex103, depth103
+								goto l125
+							l126:
+								position, tokenIndex, depth = position126, tokenIndex126, depth126
 							}
-							goto l101
-						l100:
-							position, tokenIndex, depth = position100, tokenIndex100, depth100
+							goto l124
+						l123:
+							position, tokenIndex, depth = position123, tokenIndex123, depth123
 						}
-					l101:
+					l124:
 					}
-				l96:
+				l119:
 					depth--
-					add(rulePegText, position95)
+					add(rulePegText, position118)
 				}
 				depth--
-				add(rulenumber, position94)
+				add(rulenumber, position117)
 			}
 			return true
-		l93:
-			position, tokenIndex, depth = position93, tokenIndex93, depth93
+		l116:
+			position, tokenIndex, depth = position116, tokenIndex116, depth116
 			return false
 		},
 		/* 5 digit <- <[0-9]> */
 		func() bool {
-			position104, tokenIndex104, depth104 := position, tokenIndex, depth
+			position127, tokenIndex127, depth127 := position, tokenIndex, depth
 			{
-				position105 := position
+				position128 := position
 				depth++
 				if c := buffer[position]; c < rune('0') || c > rune('9') {
-					goto l104
+					goto l127
 				}
 				position++
 				depth--
-				add(ruledigit, position105)
+				add(ruledigit, position128)
 			}
 			return true
-		l104:
-			position, tokenIndex, depth = position104, tokenIndex104, depth104
+		l127:
+			position, tokenIndex, depth = position127, tokenIndex127, depth127
 			return false
 		},
 		/* 6 time <- <(('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ' ' <(year '-' month '-' day 'T' digit digit ':' digit digit ':' digit digit ((('-' / '+') digit digit ':' digit digit) / 'Z'))>)> */
 		func() bool {
-			position106, tokenIndex106, depth106 := position, tokenIndex, depth
+			position129, tokenIndex129, depth129 := position, tokenIndex, depth
 			{
-				position107 := position
+				position130 := position
 				depth++
 				{
-					position108, tokenIndex108, depth108 := position, tokenIndex, depth
+					position131, tokenIndex131, depth131 := position, tokenIndex, depth
 					if buffer[position] != rune('t') {
-						goto l109
+						goto l132
 					}
 					position++
-					goto l108
-				l109:
-					position, tokenIndex, depth = position108, tokenIndex108, depth108
+					goto l131
+				l132:
+					position, tokenIndex, depth = position131, tokenIndex131, depth131
 					if buffer[position] != rune('T') {
-						goto l106
+						goto l129
 					}
 					position++
 				}
-			l108:
+			l131:
 				{
-					position110, tokenIndex110, depth110 := position, tokenIndex, depth
+					position133, tokenIndex133, depth133 := position, tokenIndex, depth
 					if buffer[position] != rune('i') {
-						goto l111
+						goto l134
 					}
 					position++
-					goto l110
-				l111:
-					position, tokenIndex, depth = position110, tokenIndex110, depth110
+					goto l133
+				l134:
+					position, tokenIndex, depth = position133, tokenIndex133, depth133
 					if buffer[position] != rune('I') {
-						goto l106
+						goto l129
 					}
 					position++
 				}
-			l110:
+			l133:
 				{
-					position112, tokenIndex112, depth112 := position, tokenIndex, depth
+					position135, tokenIndex135, depth135 := position, tokenIndex, depth
 					if buffer[position] != rune('m') {
-						goto l113
+						goto l136
 					}
 					position++
-					goto l112
-				l113:
-					position, tokenIndex, depth = position112, tokenIndex112, depth112
+					goto l135
+				l136:
+					position, tokenIndex, depth = position135, tokenIndex135, depth135
 					if buffer[position] != rune('M') {
-						goto l106
+						goto l129
 					}
 					position++
 				}
-			l112:
+			l135:
 				{
-					position114, tokenIndex114, depth114 := position, tokenIndex, depth
+					position137, tokenIndex137, depth137 := position, tokenIndex, depth
 					if buffer[position] != rune('e') {
-						goto l115
+						goto l138
 					}
 					position++
-					goto l114
-				l115:
-					position, tokenIndex, depth = position114, tokenIndex114, depth114
+					goto l137
+				l138:
+					position, tokenIndex, depth = position137, tokenIndex137, depth137
 					if buffer[position] != rune('E') {
-						goto l106
+						goto l129
 					}
 					position++
 				}
-			l114:
+			l137:
 				if buffer[position] != rune(' ') {
-					goto l106
+					goto l129
 				}
 				position++
 				{
-					position116 := position
+					position139 := position
 					depth++
 					if !_rules[ruleyear]() {
-						goto l106
+						goto l129
 					}
 					if buffer[position] != rune('-') {
-						goto l106
+						goto l129
 					}
 					position++
 					if !_rules[rulemonth]() {
-						goto l106
+						goto l129
 					}
 					if buffer[position] != rune('-') {
-						goto l106
+						goto l129
 					}
 					position++
 					if !_rules[ruleday]() {
-						goto l106
+						goto l129
 					}
 					if buffer[position] != rune('T') {
-						goto l106
+						goto l129
 					}
 					position++
 					if !_rules[ruledigit]() {
-						goto l106
+						goto l129
 					}
 					if !_rules[ruledigit]() {
-						goto l106
+						goto l129
 					}
 					if buffer[position] != rune(':') {
-						goto l106
+						goto l129
 					}
 					position++
 					if !_rules[ruledigit]() {
-						goto l106
+						goto l129
 					}
 					if !_rules[ruledigit]() {
-						goto l106
+						goto l129
 					}
 					if buffer[position] != rune(':') {
-						goto l106
+						goto l129
 					}
 					position++
 					if !_rules[ruledigit]() {
-						goto l106
+						goto l129
 					}
 					if !_rules[ruledigit]() {
-						goto l106
+						goto l129
 					}
 					{
-						position117, tokenIndex117, depth117 := position, tokenIndex, depth
+						position140, tokenIndex140, depth140 := position, tokenIndex, depth
 						{
-							position119, tokenIndex119, depth119 := position, tokenIndex, depth
+							position142, tokenIndex142, depth142 := position, tokenIndex, depth
 							if buffer[position] != rune('-') {
-								goto l120
+								goto l143
 							}
 							position++
-							goto l119
-						l120:
-							position, tokenIndex, depth = position119, tokenIndex119, depth119
+							goto l142
+						l143:
+							position, tokenIndex, depth = position142, tokenIndex142, depth142
 							if buffer[position] != rune('+') {
-								goto l118
+								goto l141
 							}
 							position++
 						}
-					l119:
+					l142:
 						if !_rules[ruledigit]() {
-							goto l118
+							goto l141
 						}
 						if !_rules[ruledigit]() {
-							goto l118
+							goto l141
 						}
 						if buffer[position] != rune(':') {
-							goto l118
+							goto l141
 						}
 						position++
 						if !_rules[ruledigit]() {
-							goto l118
+							goto l141
 						}
 						if !_rules[ruledigit]() {
-							goto l118
+							goto l141
 						}
-						goto l117
-					l118:
-						position, tokenIndex, depth = position117, tokenIndex117, depth117
+						goto l140
+					l141:
+						position, tokenIndex, depth = position140, tokenIndex140, depth140
 						if buffer[position] != rune('Z') {
-							goto l106
+							goto l129
 						}
 						position++
 					}
-				l117:
+				l140:
 					depth--
-					add(rulePegText, position116)
+					add(rulePegText, position139)
 				}
 				depth--
-				add(ruletime, position107)
+				add(ruletime, position130)
 			}
 			return true
-		l106:
-			position, tokenIndex, depth = position106, tokenIndex106, depth106
+		l129:
+			position, tokenIndex, depth = position129, tokenIndex129, depth129
 			return false
 		},
 		/* 7 date <- <(('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') ' ' <(year '-' month '-' day)>)> */
 		func() bool {
-			position121, tokenIndex121, depth121 := position, tokenIndex, depth
+			position144, tokenIndex144, depth144 := position, tokenIndex, depth
 			{
-				position122 := position
+				position145 := position
 				depth++
 				{
-					position123, tokenIndex123, depth123 := position, tokenIndex, depth
+					position146, tokenIndex146, depth146 := position, tokenIndex, depth
 					if buffer[position] != rune('d') {
-						goto l124
+						goto l147
 					}
 					position++
-					goto l123
-				l124:
-					position, tokenIndex, depth = position123, tokenIndex123, depth123
+					goto l146
+				l147:
+					position, tokenIndex, depth = position146, tokenIndex146, depth146
 					if buffer[position] != rune('D') {
-						goto l121
+						goto l144
 					}
 					position++
 				}
-			l123:
+			l146:
 				{
-					position125, tokenIndex125, depth125 := position, tokenIndex, depth
+					position148, tokenIndex148, depth148 := position, tokenIndex, depth
 					if buffer[position] != rune('a') {
-						goto l126
+						goto l149
 					}
 					position++
-					goto l125
-				l126:
-					position, tokenIndex, depth = position125, tokenIndex125, depth125
+					goto l148
+				l149:
+					position, tokenIndex, depth = position148, tokenIndex148, depth148
 					if buffer[position] != rune('A') {
-						goto l121
+						goto l144
 					}
 					position++
 				}
-			l125:
+			l148:
 				{
-					position127, tokenIndex127, depth127 := position, tokenIndex, depth
+					position150, tokenIndex150, depth150 := position, tokenIndex, depth
 					if buffer[position] != rune('t') {
-						goto l128
+						goto l151
 					}
 					position++
-					goto l127
-				l128:
-					position, tokenIndex, depth = position127, tokenIndex127, depth127
+					goto l150
+				l151:
+					position, tokenIndex, depth = position150, tokenIndex150, depth150
 					if buffer[position] != rune('T') {
-						goto l121
+						goto l144
 					}
 					position++
 				}
-			l127:
+			l150:
 				{
-					position129, tokenIndex129, depth129 := position, tokenIndex, depth
+					position152, tokenIndex152, depth152 := position, tokenIndex, depth
 					if buffer[position] != rune('e') {
-						goto l130
+						goto l153
 					}
 					position++
-					goto l129
-				l130:
-					position, tokenIndex, depth = position129, tokenIndex129, depth129
+					goto l152
+				l153:
+					position, tokenIndex, depth = position152, tokenIndex152, depth152
 					if buffer[position] != rune('E') {
-						goto l121
+						goto l144
 					}
 					position++
 				}
-			l129:
+			l152:
 				if buffer[position] != rune(' ') {
-					goto l121
+					goto l144
 				}
 				position++
 				{
-					position131 := position
+					position154 := position
 					depth++
 					if !_rules[ruleyear]() {
-						goto l121
+						goto l144
 					}
 					if buffer[position] != rune('-') {
-						goto l121
+						goto l144
 					}
 					position++
 					if !_rules[rulemonth]() {
-						goto l121
+						goto l144
 					}
 					if buffer[position] != rune('-') {
-						goto l121
+						goto l144
 					}
 					position++
 					if !_rules[ruleday]() {
-						goto l121
+						goto l144
 					}
 					depth--
-					add(rulePegText, position131)
+					add(rulePegText, position154)
 				}
 				depth--
-				add(ruledate, position122)
+				add(ruledate, position145)
 			}
 			return true
-		l121:
-			position, tokenIndex, depth = position121, tokenIndex121, depth121
+		l144:
+			position, tokenIndex, depth = position144, tokenIndex144, depth144
 			return false
 		},
 		/* 8 year <- <(('1' / '2') digit digit digit)> */
 		func() bool {
-			position132, tokenIndex132, depth132 := position, tokenIndex, depth
+			position155, tokenIndex155, depth155 := position, tokenIndex, depth
 			{
-				position133 := position
+				position156 := position
 				depth++
 				{
-					position134, tokenIndex134, depth134 := position, tokenIndex, depth
+					position157, tokenIndex157, depth157 := position, tokenIndex, depth
 					if buffer[position] != rune('1') {
-						goto l135
+						goto l158
 					}
 					position++
-					goto l134
-				l135:
-					position, tokenIndex, depth = position134, tokenIndex134, depth134
+					goto l157
+				l158:
+					position, tokenIndex, depth = position157, tokenIndex157, depth157
 					if buffer[position] != rune('2') {
-						goto l132
+						goto l155
 					}
 					position++
 				}
-			l134:
+			l157:
 				if !_rules[ruledigit]() {
-					goto l132
+					goto l155
 				}
 				if !_rules[ruledigit]() {
-					goto l132
+					goto l155
 				}
 				if !_rules[ruledigit]() {
-					goto l132
+					goto l155
 				}
 				depth--
-				add(ruleyear, position133)
+				add(ruleyear, position156)
 			}
 			return true
-		l132:
-			position, tokenIndex, depth = position132, tokenIndex132, depth132
+		l155:
+			position, tokenIndex, depth = position155, tokenIndex155, depth155
 			return false
 		},
 		/* 9 month <- <(('0' / '1') digit)> */
 		func() bool {
-			position136, tokenIndex136, depth136 := position, tokenIndex, depth
+			position159, tokenIndex159, depth159 := position, tokenIndex, depth
 			{
-				position137 := position
+				position160 := position
 				depth++
 				{
-					position138, tokenIndex138, depth138 := position, tokenIndex, depth
+					position161, tokenIndex161, depth161 := position, tokenIndex, depth
 					if buffer[position] != rune('0') {
-						goto l139
+						goto l162
 					}
 					position++
-					goto l138
-				l139:
-					position, tokenIndex, depth = position138, tokenIndex138, depth138
+					goto l161
+				l162:
+					position, tokenIndex, depth = position161, tokenIndex161, depth161
 					if buffer[position] != rune('1') {
-						goto l136
+						goto l159
 					}
 					position++
 				}
-			l138:
+			l161:
 				if !_rules[ruledigit]() {
-					goto l136
+					goto l159
 				}
 				depth--
-				add(rulemonth, position137)
+				add(rulemonth, position160)
 			}
 			return true
-		l136:
-			position, tokenIndex, depth = position136, tokenIndex136, depth136
+		l159:
+			position, tokenIndex, depth = position159, tokenIndex159, depth159
 			return false
 		},
 		/* 10 day <- <(((&('3') '3') | (&('2') '2') | (&('1') '1') | (&('0') '0')) digit)> */
 		func() bool {
-			position140, tokenIndex140, depth140 := position, tokenIndex, depth
+			position163, tokenIndex163, depth163 := position, tokenIndex, depth
 			{
-				position141 := position
+				position164 := position
 				depth++
 				{
 					switch buffer[position] {
 					case '3':
 						if buffer[position] != rune('3') {
-							goto l140
+							goto l163
 						}
 						position++
 						break
 					case '2':
 						if buffer[position] != rune('2') {
-							goto l140
+							goto l163
 						}
 						position++
 						break
 					case '1':
 						if buffer[position] != rune('1') {
-							goto l140
+							goto l163
 						}
 						position++
 						break
 					default:
 						if buffer[position] != rune('0') {
-							goto l140
+							goto l163
 						}
 						position++
 						break
@@ -1838,31 +2049,35 @@ func (p *QueryParser) Init() {
 				}
 
 				if !_rules[ruledigit]() {
-					goto l140
+					goto l163
 				}
 				depth--
-				add(ruleday, position141)
+				add(ruleday, position164)
 			}
 			return true
-		l140:
-			position, tokenIndex, depth = position140, tokenIndex140, depth140
+		l163:
+			position, tokenIndex, depth = position163, tokenIndex163, depth163
 			return false
 		},
 		/* 11 and <- <(('a' / 'A') ('n' / 'N') ('d' / 'D'))> */
 		nil,
 		/* 12 equal <- <'='> */
 		nil,
-		/* 13 contains <- <(('c' / 'C') ('o' / 'O') ('n' / 'N') ('t' / 'T') ('a' / 'A') ('i' / 'I') ('n' / 'N') ('s' / 'S'))> */
+		/* 13 notEqual <- <('!' '=')> */
+		nil,
+		/* 14 contains <- <(('c' / 'C') ('o' / 'O') ('n' / 'N') ('t' / 'T') ('a' / 'A') ('i' / 'I') ('n' / 'N') ('s' / 'S'))> */
+		nil,
+		/* 15 matches <- <(('m' / 'M') ('a' / 'A') ('t' / 'T') ('c' / 'C') ('h' / 'H') ('e' / 'E') ('s' / 'S'))> */
 		nil,
-		/* 14 exists <- <(('e' / 'E') ('x' / 'X') ('i' / 'I') ('s' / 'S') ('t' / 'T') ('s' / 'S'))> */
+		/* 16 exists <- <(('e' / 'E') ('x' / 'X') ('i' / 'I') ('s' / 'S') ('t' / 'T') ('s' / 'S'))> */
 		nil,
-		/* 15 le <- <('<' '=')> */
+		/* 17 le <- <('<' '=')> */
 		nil,
-		/* 16 ge <- <('>' '=')> */
+		/* 18 ge <- <('>' '=')> */
 		nil,
-		/* 17 l <- <'<'> */
+		/* 19 l <- <'<'> */
 		nil,
-		/* 18 g <- <'>'> */
+		/* 20 g <- <'>'> */
 		nil,
 		nil,
 	}
@@ -62,6 +62,22 @@ func TestMatches(t *testing.T) {
 		{"tx.time = TIME 2013-05-03T14:45:00Z", map[string][]string{"tx.time": {txTime}}, false, false, false},
 		{"abci.owner.name CONTAINS 'Igor'", map[string][]string{"abci.owner.name": {"Igor,Ivan"}}, false, true, false},
 		{"abci.owner.name CONTAINS 'Igor'", map[string][]string{"abci.owner.name": {"Pavel,Ivan"}}, false, false, false},
+		{"abci.owner.name MATCHES 'Ig.r'", map[string][]string{"abci.owner.name": {"Igor"}}, false, true, false},
+		{"abci.owner.name MATCHES 'Ig.r'", map[string][]string{"abci.owner.name": {"Pavel", "Igar"}}, false, true, false},
+		// the regular expression is anchored
+		{"abci.owner.name MATCHES 'Ig.r'", map[string][]string{"abci.owner.name": {"Igor,Ivan"}}, false, false, false},
+		{"abci.owner.name MATCHES 'Ig.r.*'", map[string][]string{"abci.owner.name": {"Igor,Ivan"}}, false, true, false},
+		{"abci.owner.name MATCHES 'a|Igor'", map[string][]string{"abci.owner.name": {"Igor"}}, false, true, false},
+		{"abci.owner.name MATCHES 'Ig.r'", map[string][]string{"abci.owner.id": {"Igor"}}, false, false, false},
+		{"abci.owner.name != 'Igor'", map[string][]string{"abci.owner.name": {"Pavel"}}, false, true, false},
+		{"abci.owner.name != 'Igor'", map[string][]string{"abci.owner.name": {"Igor"}}, false, false, false},
+		{"abci.owner.name != 'Igor'", map[string][]string{"abci.owner.name": {"Pavel", "Igor"}}, false, false, false},
+		{"abci.owner.name != 'Igor'", map[string][]string{"abci.owner.id": {"1"}}, false, false, false},
+		{"tx.gas != 7", map[string][]string{"tx.gas": {"8"}}, false, true, false},
+		{"tx.gas! = 7", map[string][]string{"tx.gas!": {"7"}}, false, true, false},
+		{"tx!gas != 7", map[string][]string{"tx!gas": {"8"}}, false, true, false},
+		{"tx.gas != 7", map[string][]string{"tx.gas": {"7"}}, false, false, false},
+		{"tx.gas > 7 AND tx.gas != 8", map[string][]string{"tx.gas": {"9"}}, false, true, false},
 		{"abci.owner.name = 'Igor'", map[string][]string{"abci.owner.name": {"Igor", "Ivan"}}, false, true, false},
 		{
 			"abci.owner.name = 'Ivan'",
@@ -203,6 +219,19 @@ func TestConditions(t *testing.T) {
 				{CompositeKey: "tx.time", Op: query.OpGreaterEqual, Operand: txTime},
 			},
 		},
+		{
+			s: "abci.owner.name MATCHES 'Ig[a-z]+'",
+			conditions: []query.Condition{
+				{CompositeKey: "abci.owner.name", Op: query.OpMatches, Operand: "Ig[a-z]+"},
+			},
+		},
+		{
+			s: "tx.gas != 7 AND abci.owner.name != 'Igor'",
+			conditions: []query.Condition{
+				{CompositeKey: "tx.gas", Op: query.OpNotEqual, Operand: int64(7)},
+				{CompositeKey: "abci.owner.name", Op: query.OpNotEqual, Operand: "Igor"},
+			},
+		},
 		{
 			s: "slashing EXISTS",
 			conditions: []query.Condition{
@@ -220,3 +249,74 @@ func TestConditions(t *testing.T) {
 		assert.Equal(t, tc.conditions, c)
 	}
 }
+
+func TestMatchedAttributes(t *testing.T) {
+	testCases := []struct {
+		s       string
+		events  map[string][]string
+		matched []query.MatchedAttribute
+	}{
+		{
+			"tx.gas > 5",
+			map[string][]string{"tx.gas": {"3", "7", "9"}},
+			[]query.MatchedAttribute{{"tx.gas", "7"}, {"tx.gas", "9"}},
+		},
+		{
+			"tx.gas > 5 AND account.owner = 'Ivan'",
+			map[string][]string{"tx.gas": {"7"}, "account.owner": {"Igor", "Ivan"}, "account.name": {"x"}},
+			[]query.MatchedAttribute{{"tx.gas", "7"}, {"account.owner", "Ivan"}},
+		},
+		{
+			// only the satisfied conditions are reported
+			"tx.gas > 5 AND account.owner = 'Ivan'",
+			map[string][]string{"tx.gas": {"3"}, "account.owner": {"Ivan"}},
+			[]query.MatchedAttribute{{"account.owner", "Ivan"}},
+		},
+		{
+			"account.owner CONTAINS 'Iv' AND account.owner = 'Ivan'",
+			map[string][]string{"account.owner": {"Ivan"}},
+			[]query.MatchedAttribute{{"account.owner", "Ivan"}},
+		},
+		{
+			"account.owner MATCHES 'I.*'",
+			map[string][]string{"account.owner": {"Ivan", "Pavel", "Igor"}},
+			[]query.MatchedAttribute{{"account.owner", "Ivan"}, {"account.owner", "Igor"}},
+		},
+		{
+			"account EXISTS",
+			map[string][]string{"account.owner": {"Ivan"}, "account.name": {"x"}, "tx.gas": {"1"}},
+			[]query.MatchedAttribute{{"account.name", "x"}, {"account.owner", "Ivan"}},
+		},
+		{
+			"account.owner != 'Ivan'",
+			map[string][]string{"account.owner": {"Igor"}},
+			[]query.MatchedAttribute{{"account.owner", "Igor"}},
+		},
+		{
+			"account.owner != 'Ivan'",
+			map[string][]string{"account.owner": {"Igor", "Ivan"}},
+			nil,
+		},
+		{
+			// values which can't be compared don't match
+			"tx.gas > 5",
+			map[string][]string{"tx.gas": {"abc", "8"}},
+			[]query.MatchedAttribute{{"tx.gas", "8"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		q, err := query.New(tc.s)
+		require.NoError(t, err)
+
+		matched, err := q.MatchedAttributes(tc.events)
+		require.NoError(t, err)
+		assert.Equal(t, tc.matched, matched, "query %s", tc.s)
+	}
+}
+
+func TestInvalidRegexp(t *testing.T) {
+	_, err := query.New("account.owner MATCHES 'Iv(an'")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid regular expression "Iv(an"`)
+}
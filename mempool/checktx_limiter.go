@@ -0,0 +1,82 @@
+package mempool
+
+import (
+	"fmt"
+	"time"
+)
+
+// CheckTxLimiter bounds the number of CheckTx requests in flight to the
+// application, across all the senders of transactions (peers and RPC).
+//
+// A nil *CheckTxLimiter does not limit anything.
+type CheckTxLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// NewCheckTxLimiter returns a limiter allowing up to limit CheckTx requests in
+// flight. A new request waits up to timeout for a request to complete when
+// the limit is reached, or is rejected immediately if timeout is 0. It returns
+// nil if limit is 0.
+func NewCheckTxLimiter(limit int, timeout time.Duration) *CheckTxLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &CheckTxLimiter{
+		slots:   make(chan struct{}, limit),
+		timeout: timeout,
+	}
+}
+
+// Acquire reserves a slot for a new CheckTx request. It returns
+// ErrCheckTxInFlightLimit if no slot has been released within the timeout.
+// Every successful call must be followed by a call to Release once the
+// request has completed.
+func (l *CheckTxLimiter) Acquire() error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if l.timeout <= 0 {
+		return ErrCheckTxInFlightLimit{Max: cap(l.slots)}
+	}
+
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrCheckTxInFlightLimit{Max: cap(l.slots)}
+	}
+}
+
+// Release frees the slot of a completed CheckTx request.
+func (l *CheckTxLimiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// InFlight returns the number of CheckTx requests currently in flight.
+func (l *CheckTxLimiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// ErrCheckTxInFlightLimit defines an error where a transaction is rejected
+// because too many CheckTx requests are in flight to the application.
+type ErrCheckTxInFlightLimit struct {
+	Max int
+}
+
+func (e ErrCheckTxInFlightLimit) Error() string {
+	return fmt.Sprintf("too many CheckTx requests in flight (max: %d)", e.Max)
+}
@@ -0,0 +1,89 @@
+package mempool
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTxLimiter(t *testing.T) {
+	// no limit
+	var l *CheckTxLimiter
+	require.Nil(t, NewCheckTxLimiter(0, time.Second))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, l.Acquire())
+	}
+	l.Release()
+	require.Zero(t, l.InFlight())
+
+	// reject immediately
+	l = NewCheckTxLimiter(2, 0)
+	require.NoError(t, l.Acquire())
+	require.NoError(t, l.Acquire())
+	require.Equal(t, ErrCheckTxInFlightLimit{Max: 2}, l.Acquire())
+	require.Equal(t, 2, l.InFlight())
+	l.Release()
+	require.NoError(t, l.Acquire())
+
+	// wait for a slot
+	l = NewCheckTxLimiter(1, time.Minute)
+	require.NoError(t, l.Acquire())
+	time.AfterFunc(10*time.Millisecond, l.Release)
+	require.NoError(t, l.Acquire())
+
+	// until the timeout
+	l = NewCheckTxLimiter(1, 10*time.Millisecond)
+	require.NoError(t, l.Acquire())
+	start := time.Now()
+	require.Equal(t, ErrCheckTxInFlightLimit{Max: 1}, l.Acquire())
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+// BenchmarkCheckTxLimiter runs simulated CheckTx requests from many goroutines
+// and reports the maximum number of requests in flight, which never exceeds
+// the limit, along with the share of throttled requests.
+func BenchmarkCheckTxLimiter(b *testing.B) {
+	for _, limit := range []int{0, 4, 16} {
+		limit := limit
+		b.Run(benchmarkLimitName(limit), func(b *testing.B) {
+			l := NewCheckTxLimiter(limit, time.Millisecond)
+			var inFlight, maxInFlight, throttled int64
+			b.SetParallelism(64)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := l.Acquire(); err != nil {
+						atomic.AddInt64(&throttled, 1)
+						continue
+					}
+					n := atomic.AddInt64(&inFlight, 1)
+					for {
+						max := atomic.LoadInt64(&maxInFlight)
+						if n <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, n) {
+							break
+						}
+					}
+					// the application checks the tx
+					time.Sleep(10 * time.Microsecond)
+					atomic.AddInt64(&inFlight, -1)
+					l.Release()
+				}
+			})
+			b.ReportMetric(float64(maxInFlight), "max-in-flight")
+			b.ReportMetric(float64(throttled)/float64(b.N), "throttled/op")
+			if limit > 0 && maxInFlight > int64(limit) {
+				b.Fatalf("%d requests in flight, limit is %d", maxInFlight, limit)
+			}
+		})
+	}
+}
+
+func benchmarkLimitName(limit int) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return "limit-" + strconv.Itoa(limit)
+}
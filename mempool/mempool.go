@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/types"
@@ -36,6 +37,15 @@ type Mempool interface {
 	// from the mempool.
 	RemoveTxByKey(txKey types.TxKey) error
 
+	// RemoveTxByHash removes a transaction, identified by its hash as reported
+	// by RPC and the indexer (see types.TxHash), from the mempool and the
+	// cache, e.g. when the application knows it will never be valid. It
+	// returns ErrTxNotFound if there is no such transaction.
+	//
+	// NOTE:
+	// 1. Locks the mempool: it must not be called with the lock held.
+	RemoveTxByHash(hash []byte) error
+
 	// ReapMaxBytesMaxGas reaps transactions from the mempool up to maxBytes
 	// bytes total with the condition that the total gasWanted must be less than
 	// maxGas.
@@ -96,6 +106,22 @@ type Mempool interface {
 
 	// SizeBytes returns the total size of all txs in the mempool.
 	SizeBytes() int64
+
+	// PendingTxs returns all the transactions in the mempool along with when
+	// they were added, sorted by age (oldest first).
+	PendingTxs() []PendingTx
+}
+
+// PendingTx is a transaction waiting in the mempool.
+type PendingTx struct {
+	Tx types.Tx
+	// Height is the height at which the transaction was added to the mempool.
+	Height int64
+	// Time is the time at which the transaction was added to the mempool.
+	Time time.Time
+	// Priority is the priority assigned by the application, if the mempool
+	// supports priorities.
+	Priority int64
 }
 
 // PreCheckFunc is an optional filter executed before CheckTx and rejects
@@ -103,6 +129,19 @@ type Mempool interface {
 // transaction doesn't exceeded the block size.
 type PreCheckFunc func(types.Tx) error
 
+// TxFilter is an optional filter executed before the ABCI CheckTx, meant for
+// cheap local checks (e.g. size, format or denylist). If the returned code is
+// not abci.CodeTypeOK, the transaction is rejected without calling the
+// application, and the code and log are reported to the caller as if they had
+// been returned by CheckTx. Unlike PreCheckFunc, it is never replaced by
+// Update.
+type TxFilter func(types.Tx) (code uint32, log string)
+
+// SenderFunc returns the sender of a transaction, which groups the
+// transactions when reaping them fairly across senders. Transactions with an
+// empty sender are not grouped.
+type SenderFunc func(types.Tx) string
+
 // PostCheckFunc is an optional filter executed after CheckTx and rejects
 // transaction if false is returned. An example would be to ensure a
 // transaction doesn't require more gas than available for the block.
@@ -145,6 +184,21 @@ func PostCheckMaxGas(maxGas int64) PostCheckFunc {
 // ErrTxInCache is returned to the client if we saw tx earlier
 var ErrTxInCache = errors.New("tx already exists in cache")
 
+// ErrTxNotFound is returned when removing a transaction which is not in the
+// mempool.
+var ErrTxNotFound = errors.New("transaction not found")
+
+// TxKeyFromHash returns the key of the transaction with the given Tx.Hash,
+// which differs from types.TxHash if a custom TxHasher is registered.
+func TxKeyFromHash(hash []byte) (types.TxKey, error) {
+	var key types.TxKey
+	if len(hash) != len(key) {
+		return key, fmt.Errorf("invalid tx hash length %d, expected %d", len(hash), len(key))
+	}
+	copy(key[:], hash)
+	return key, nil
+}
+
 // TxKey is the fixed length array key used as an index.
 type TxKey [sha256.Size]byte
 
@@ -178,6 +232,40 @@ func (e ErrMempoolIsFull) Error() string {
 	)
 }
 
+// ErrSenderQuotaExceeded defines an error where a transaction would exceed the
+// quota of bytes of its sender in the mempool.
+type ErrSenderQuotaExceeded struct {
+	Sender      string
+	SenderBytes int64
+	TxBytes     int64
+	MaxBytes    int64
+}
+
+func (e ErrSenderQuotaExceeded) Error() string {
+	return fmt.Sprintf(
+		"sender %q quota exceeded: sender txs bytes %d + tx bytes %d (max: %d)",
+		e.Sender,
+		e.SenderBytes,
+		e.TxBytes,
+		e.MaxBytes,
+	)
+}
+
+// ErrTxInBatch defines an error where a transaction of a batch fails the
+// validation, which fails the whole batch.
+type ErrTxInBatch struct {
+	Index int
+	Err   error
+}
+
+func (e ErrTxInBatch) Error() string {
+	return fmt.Sprintf("tx %d of the batch: %v", e.Index, e.Err)
+}
+
+func (e ErrTxInBatch) Unwrap() error {
+	return e.Err
+}
+
 // ErrPreCheck defines an error where a transaction fails a pre-check.
 type ErrPreCheck struct {
 	Reason error
@@ -13,6 +13,26 @@ const (
 	MetricsSubsystem = "mempool"
 )
 
+// The reasons for which transactions are removed from the mempool, as given by
+// the "reason" label of Metrics.RemovedTxs.
+const (
+	// RemovalReasonCommitted is the reason of the removal of a transaction
+	// included in a block.
+	RemovalReasonCommitted = "committed"
+	// RemovalReasonEvicted is the reason of the removal of a valid transaction
+	// to make room for a higher priority one.
+	RemovalReasonEvicted = "evicted"
+	// RemovalReasonExpired is the reason of the removal of a transaction which
+	// exceeded its TTL.
+	RemovalReasonExpired = "expired"
+	// RemovalReasonRecheck is the reason of the removal of a transaction which
+	// was no longer valid when rechecked after a block.
+	RemovalReasonRecheck = "recheck"
+	// RemovalReasonRemoved is the reason of the removal of a transaction
+	// requested through RemoveTxByKey or RemoveTxByHash.
+	RemovalReasonRemoved = "removed"
+)
+
 // Metrics contains metrics exposed by this package.
 // see MetricsProvider for descriptions.
 type Metrics struct {
@@ -25,6 +45,10 @@ type Metrics struct {
 	// Number of failed transactions.
 	FailedTxs metrics.Counter
 
+	// FilteredTxs defines the number of transactions rejected by the mempool's
+	// TxFilter, without being checked by the application.
+	FilteredTxs metrics.Counter
+
 	// RejectedTxs defines the number of rejected transactions. These are
 	// transactions that passed CheckTx but failed to make it into the mempool
 	// due to resource limits, e.g. mempool is full and no lower priority
@@ -37,8 +61,22 @@ type Metrics struct {
 	// CheckTx.
 	EvictedTxs metrics.Counter
 
+	// RemovedTxs defines the number of transactions removed from the mempool,
+	// labeled by the reason of the removal (one of the RemovalReason
+	// constants).
+	RemovedTxs metrics.Counter
+
 	// Number of times transactions are rechecked in the mempool.
 	RecheckTimes metrics.Counter
+
+	// ThrottledTxs defines the number of transactions rejected without being
+	// checked, because too many CheckTx requests were in flight.
+	ThrottledTxs metrics.Counter
+
+	// TimeToFirstGossip defines the time between the admission of a
+	// transaction in the mempool and its first forwarding to a peer, in
+	// seconds.
+	TimeToFirstGossip metrics.Histogram
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -72,6 +110,13 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Help:      "Number of failed transactions.",
 		}, labels).With(labelsAndValues...),
 
+		FilteredTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "filtered_txs",
+			Help:      "Number of transactions rejected by the tx filter before CheckTx.",
+		}, labels).With(labelsAndValues...),
+
 		RejectedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -86,12 +131,34 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Help:      "Number of evicted transactions.",
 		}, labels).With(labelsAndValues...),
 
+		RemovedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "removed_txs",
+			Help:      "Number of transactions removed from the mempool, by reason.",
+		}, append(labels, "reason")).With(labelsAndValues...),
+
 		RecheckTimes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
 			Name:      "recheck_times",
 			Help:      "Number of times transactions are rechecked in the mempool.",
 		}, labels).With(labelsAndValues...),
+
+		ThrottledTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "throttled_txs",
+			Help:      "Number of transactions rejected because too many CheckTx requests were in flight.",
+		}, labels).With(labelsAndValues...),
+
+		TimeToFirstGossip: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "time_to_first_gossip_seconds",
+			Help:      "Time between the admission of a transaction and its first forwarding to a peer, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 14),
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -101,8 +168,13 @@ func NopMetrics() *Metrics {
 		Size:         discard.NewGauge(),
 		TxSizeBytes:  discard.NewHistogram(),
 		FailedTxs:    discard.NewCounter(),
+		FilteredTxs:  discard.NewCounter(),
 		RejectedTxs:  discard.NewCounter(),
 		EvictedTxs:   discard.NewCounter(),
+		RemovedTxs:   discard.NewCounter(),
 		RecheckTimes: discard.NewCounter(),
+		ThrottledTxs: discard.NewCounter(),
+
+		TimeToFirstGossip: discard.NewHistogram(),
 	}
 }
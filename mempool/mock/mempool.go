@@ -19,6 +19,7 @@ func (Mempool) CheckTx(_ types.Tx, _ func(*abci.Response), _ mempool.TxInfo) err
 	return nil
 }
 func (Mempool) RemoveTxByKey(txKey types.TxKey) error   { return nil }
+func (Mempool) RemoveTxByHash(hash []byte) error        { return nil }
 func (Mempool) ReapMaxBytesMaxGas(_, _ int64) types.Txs { return types.Txs{} }
 func (Mempool) ReapMaxTxs(n int) types.Txs              { return types.Txs{} }
 func (Mempool) Update(
@@ -35,6 +36,9 @@ func (Mempool) FlushAppConn() error           { return nil }
 func (Mempool) TxsAvailable() <-chan struct{} { return make(chan struct{}) }
 func (Mempool) EnableTxsAvailable()           {}
 func (Mempool) SizeBytes() int64              { return 0 }
+func (Mempool) PendingTxs() []mempool.PendingTx {
+	return nil
+}
 
 func (Mempool) TxsFront() *clist.CElement    { return nil }
 func (Mempool) TxsWaitChan() <-chan struct{} { return nil }
@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
 )
 
 func BenchmarkReap(b *testing.B) {
@@ -54,6 +56,59 @@ func BenchmarkCheckTx(b *testing.B) {
 	}
 }
 
+// checkTxCounterApp counts the CheckTx calls reaching the application.
+type checkTxCounterApp struct {
+	*kvstore.Application
+	checkTxs int64
+}
+
+func (app *checkTxCounterApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	atomic.AddInt64(&app.checkTxs, 1)
+	return app.Application.CheckTx(req)
+}
+
+func BenchmarkCheckTxWithFilter(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		filter mempool.TxFilter
+	}{
+		{"no filter", nil},
+		{"rejecting half", func(tx types.Tx) (uint32, string) {
+			if tx[len(tx)-1]%2 == 0 {
+				return 1, "filtered"
+			}
+			return abci.CodeTypeOK, ""
+		}},
+		{"rejecting all", func(tx types.Tx) (uint32, string) { return 1, "filtered" }},
+	}
+
+	for _, bm := range benchmarks {
+		bm := bm
+		b.Run(bm.name, func(b *testing.B) {
+			app := &checkTxCounterApp{Application: kvstore.NewApplication()}
+			cc := proxy.NewLocalClientCreator(app)
+			mp, cleanup := newMempoolWithApp(cc)
+			defer cleanup()
+
+			mp.config.Size = 1000000
+			WithTxFilter(bm.filter)(mp)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				tx := make([]byte, 8)
+				binary.BigEndian.PutUint64(tx, uint64(i))
+				b.StartTimer()
+
+				if err := mp.CheckTx(tx, nil, mempool.TxInfo{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(&app.checkTxs))/float64(b.N), "abci_calls/op")
+		})
+	}
+}
+
 func BenchmarkParallelCheckTx(b *testing.B) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
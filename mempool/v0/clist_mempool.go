@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
@@ -38,6 +39,7 @@ type CListMempool struct {
 	// CheckTx or ReapMaxBytesMaxGas(ReapMaxTxs) methods.
 	updateMtx tmsync.RWMutex
 	preCheck  mempool.PreCheckFunc
+	txFilter  mempool.TxFilter
 	postCheck mempool.PostCheckFunc
 
 	txs          *clist.CList // concurrent linked-list of good txs
@@ -57,6 +59,9 @@ type CListMempool struct {
 	// This reduces the pressure on the proxyApp.
 	cache mempool.TxCache
 
+	// Bounds the CheckTx requests of new txs in flight to the proxyApp.
+	checkTxLimiter *mempool.CheckTxLimiter
+
 	logger  log.Logger
 	metrics *mempool.Metrics
 }
@@ -84,6 +89,8 @@ func NewCListMempool(
 		recheckEnd:    nil,
 		logger:        log.NewNopLogger(),
 		metrics:       mempool.NopMetrics(),
+
+		checkTxLimiter: mempool.NewCheckTxLimiter(cfg.MaxInFlightCheckTx, cfg.InFlightCheckTxTimeout),
 	}
 
 	if cfg.CacheSize > 0 {
@@ -118,6 +125,12 @@ func WithPreCheck(f mempool.PreCheckFunc) CListMempoolOption {
 	return func(mem *CListMempool) { mem.preCheck = f }
 }
 
+// WithTxFilter sets a filter ran on every tx before CheckTx. Txs it rejects
+// are never sent to the app. See mempool.TxFilter.
+func WithTxFilter(f mempool.TxFilter) CListMempoolOption {
+	return func(mem *CListMempool) { mem.txFilter = f }
+}
+
 // WithPostCheck sets a filter for the mempool to reject a tx if f(tx) returns
 // false. This is ran after CheckTx. Only applies to the first created block.
 // After that, Update overwrites the existing value.
@@ -206,18 +219,60 @@ func (mem *CListMempool) CheckTx(
 	txInfo mempool.TxInfo,
 ) error {
 
+	if ok, err := mem.precheckTx(tx, cb, txInfo); err != nil || !ok {
+		return err
+	}
+
+	// wait for a CheckTx slot without locking, so as not to block Update
+	if err := mem.checkTxLimiter.Acquire(); err != nil {
+		mem.cache.Remove(tx)
+		mem.metrics.ThrottledTxs.Add(1)
+		mem.logger.Debug("throttled transaction", "tx", tx.Hash(), "peerID", txInfo.SenderP2PID, "err", err)
+		return err
+	}
+
+	// the slot is released once the application has responded
+	dispatched := false
+	defer func() {
+		if !dispatched {
+			mem.checkTxLimiter.Release()
+		}
+	}()
+
 	mem.updateMtx.RLock()
 	// use defer to unlock mutex because application (*local client*) might panic
 	defer mem.updateMtx.RUnlock()
 
+	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
+	dispatched = true
+	resCb := mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, cb)
+	reqRes.SetCallback(func(res *abci.Response) {
+		mem.checkTxLimiter.Release()
+		resCb(res)
+	})
+
+	return nil
+}
+
+// precheckTx validates a new transaction without invoking the application,
+// and pushes it to the cache. It returns false if the transaction was filtered
+// out, in which case cb has been called with the response of the filter.
+func (mem *CListMempool) precheckTx(
+	tx types.Tx,
+	cb func(*abci.Response),
+	txInfo mempool.TxInfo,
+) (bool, error) {
+	mem.updateMtx.RLock()
+	defer mem.updateMtx.RUnlock()
+
 	txSize := len(tx)
 
 	if err := mem.isFull(txSize); err != nil {
-		return err
+		return false, err
 	}
 
 	if txSize > mem.config.MaxTxBytes {
-		return mempool.ErrTxTooLarge{
+		return false, mempool.ErrTxTooLarge{
 			Max:    mem.config.MaxTxBytes,
 			Actual: txSize,
 		}
@@ -225,15 +280,27 @@ func (mem *CListMempool) CheckTx(
 
 	if mem.preCheck != nil {
 		if err := mem.preCheck(tx); err != nil {
-			return mempool.ErrPreCheck{
+			return false, mempool.ErrPreCheck{
 				Reason: err,
 			}
 		}
 	}
 
+	if mem.txFilter != nil {
+		if code, log := mem.txFilter(tx); code != abci.CodeTypeOK {
+			mem.metrics.FilteredTxs.Add(1)
+			mem.logger.Debug("filtered out transaction", "tx", tx.Hash(), "peerID", txInfo.SenderP2PID,
+				"code", code, "log", log)
+			if cb != nil {
+				cb(abci.ToResponseCheckTx(abci.ResponseCheckTx{Code: code, Log: log}))
+			}
+			return false, nil
+		}
+	}
+
 	// NOTE: proxyAppConn may error if tx buffer is full
 	if err := mem.proxyAppConn.Error(); err != nil {
-		return err
+		return false, err
 	}
 
 	if !mem.cache.Push(tx) { // if the transaction already exists in the cache
@@ -248,13 +315,9 @@ func (mem *CListMempool) CheckTx(
 			// its non-trivial since invalid txs can become valid,
 			// but they can spam the same tx with little cost to them atm.
 		}
-		return mempool.ErrTxInCache
+		return false, mempool.ErrTxInCache
 	}
-
-	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
-	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, cb))
-
-	return nil
+	return true, nil
 }
 
 // Global callback that will be called after every ABCI response.
@@ -340,6 +403,7 @@ func (mem *CListMempool) RemoveTxByKey(txKey types.TxKey) error {
 		memTx := e.(*clist.CElement).Value.(*mempoolTx)
 		if memTx != nil {
 			mem.removeTx(memTx.tx, e.(*clist.CElement), false)
+			mem.metrics.RemovedTxs.With("reason", mempool.RemovalReasonRemoved).Add(1)
 			return nil
 		}
 		return errors.New("transaction not found")
@@ -347,6 +411,42 @@ func (mem *CListMempool) RemoveTxByKey(txKey types.TxKey) error {
 	return errors.New("invalid transaction found")
 }
 
+// RemoveTxByHash removes a transaction, identified by its types.TxHash, from
+// the mempool and the cache.
+func (mem *CListMempool) RemoveTxByHash(hash []byte) error {
+	mem.updateMtx.Lock()
+	defer mem.updateMtx.Unlock()
+
+	elem := mem.txByHash(hash)
+	if elem == nil {
+		return mempool.ErrTxNotFound
+	}
+	mem.removeTx(elem.Value.(*mempoolTx).tx, elem, true)
+	mem.metrics.RemovedTxs.With("reason", mempool.RemovalReasonRemoved).Add(1)
+	mem.metrics.Size.Set(float64(mem.Size()))
+	return nil
+}
+
+// txByHash returns the element of the transaction with the given
+// types.TxHash, or nil. The hash is the key of the transaction, unless a custom
+// TxHasher is registered, in which case every transaction is hashed.
+func (mem *CListMempool) txByHash(hash []byte) *clist.CElement {
+	if txKey, err := mempool.TxKeyFromHash(hash); err == nil {
+		if e, ok := mem.txsMap.Load(txKey); ok {
+			elem := e.(*clist.CElement)
+			if bytes.Equal(types.TxHash(elem.Value.(*mempoolTx).tx), hash) {
+				return elem
+			}
+		}
+	}
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		if bytes.Equal(types.TxHash(e.Value.(*mempoolTx).tx), hash) {
+			return e
+		}
+	}
+	return nil
+}
+
 func (mem *CListMempool) isFull(txSize int) error {
 	var (
 		memSize  = mem.Size()
@@ -395,6 +495,7 @@ func (mem *CListMempool) resCbFirstTime(
 				height:    mem.height,
 				gasWanted: r.CheckTx.GasWanted,
 				tx:        tx,
+				timestamp: time.Now(),
 			}
 			memTx.senders.Store(peerID, true)
 			mem.addTx(memTx)
@@ -478,6 +579,7 @@ func (mem *CListMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 			mem.logger.Debug("tx is no longer valid", "tx", types.Tx(tx).Hash(), "res", r, "err", postCheckErr)
 			// NOTE: we remove tx from the cache because it might be good later
 			mem.removeTx(tx, mem.recheckCursor, !mem.config.KeepInvalidTxsInCache)
+			mem.metrics.RemovedTxs.With("reason", mempool.RemovalReasonRecheck).Add(1)
 		}
 		if mem.recheckCursor == mem.recheckEnd {
 			mem.recheckCursor = nil
@@ -575,6 +677,24 @@ func (mem *CListMempool) ReapMaxTxs(max int) types.Txs {
 	return txs
 }
 
+// PendingTxs returns all the transactions in the mempool, by order of arrival.
+// Safe for concurrent use by multiple goroutines.
+func (mem *CListMempool) PendingTxs() []mempool.PendingTx {
+	mem.updateMtx.RLock()
+	defer mem.updateMtx.RUnlock()
+
+	txs := make([]mempool.PendingTx, 0, mem.txs.Len())
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTx := e.Value.(*mempoolTx)
+		txs = append(txs, mempool.PendingTx{
+			Tx:     memTx.tx,
+			Height: memTx.Height(),
+			Time:   memTx.timestamp,
+		})
+	}
+	return txs
+}
+
 // Lock() must be help by the caller during execution.
 func (mem *CListMempool) Update(
 	height int64,
@@ -615,6 +735,7 @@ func (mem *CListMempool) Update(
 		// https://github.com/tendermint/tendermint/issues/3322.
 		if e, ok := mem.txsMap.Load(tx.Key()); ok {
 			mem.removeTx(tx, e.(*clist.CElement), false)
+			mem.metrics.RemovedTxs.With("reason", mempool.RemovalReasonCommitted).Add(1)
 		}
 	}
 
@@ -663,9 +784,11 @@ func (mem *CListMempool) recheckTxs() {
 
 // mempoolTx is a transaction that successfully ran
 type mempoolTx struct {
-	height    int64    // height that this tx had been validated in
-	gasWanted int64    // amount of gas this tx states it will require
-	tx        types.Tx //
+	height    int64     // height that this tx had been validated in
+	gasWanted int64     // amount of gas this tx states it will require
+	tx        types.Tx  //
+	timestamp time.Time // time when this tx was added to the mempool
+	gossiped  int32     // 1 once this tx has been forwarded to a peer
 
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
@@ -676,3 +799,9 @@ type mempoolTx struct {
 func (memTx *mempoolTx) Height() int64 {
 	return atomic.LoadInt64(&memTx.height)
 }
+
+// markGossiped records that this transaction has been forwarded to a peer,
+// and returns true the first time only.
+func (memTx *mempoolTx) markGossiped() bool {
+	return atomic.CompareAndSwapInt32(&memTx.gossiped, 0, 1)
+}
@@ -1,11 +1,13 @@
 package v0
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
 	mrand "math/rand"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -206,6 +208,36 @@ func TestMempoolFilters(t *testing.T) {
 	}
 }
 
+func TestMempoolTxFilter(t *testing.T) {
+	app := &checkTxCounterApp{Application: kvstore.NewApplication()}
+	cc := proxy.NewLocalClientCreator(app)
+	mp, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	denied := types.Tx("denied")
+	WithTxFilter(func(tx types.Tx) (uint32, string) {
+		if bytes.Equal(tx, denied) {
+			return 7, "denylisted"
+		}
+		return abci.CodeTypeOK, ""
+	})(mp)
+
+	var res *abci.ResponseCheckTx
+	err := mp.CheckTx(denied, func(r *abci.Response) { res = r.GetCheckTx() }, mempool.TxInfo{})
+	require.NoError(t, err)
+	require.NotNil(t, res, "the callback must be called for filtered txs")
+	require.EqualValues(t, 7, res.Code)
+	require.Equal(t, "denylisted", res.Log)
+	require.Zero(t, mp.Size())
+	require.Zero(t, atomic.LoadInt64(&app.checkTxs), "filtered txs must not reach the app")
+
+	// filtered txs are not cached, so they can pass once the filter changes
+	WithTxFilter(nil)(mp)
+	require.NoError(t, mp.CheckTx(denied, nil, mempool.TxInfo{}))
+	require.Equal(t, 1, mp.Size())
+	require.EqualValues(t, 1, atomic.LoadInt64(&app.checkTxs))
+}
+
 func TestMempoolUpdate(t *testing.T) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -294,6 +326,55 @@ func TestMempoolUpdateDoesNotPanicWhenApplicationMissedTx(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestMempoolMaxInFlightCheckTx(t *testing.T) {
+	mockClient := new(abciclimocks.Client)
+	mockClient.On("Start").Return(nil)
+	mockClient.On("SetLogger", mock.Anything)
+	mockClient.On("Error").Return(nil)
+	mockClient.On("SetResponseCallback", mock.Anything)
+
+	conf := config.ResetTestRoot("mempool_test")
+	conf.Mempool.MaxInFlightCheckTx = 2
+	conf.Mempool.InFlightCheckTxTimeout = 0
+	mp, cleanup := newMempoolWithAppAndConfigMock(proxy.NewLocalClientCreator(kvstore.NewApplication()), conf, mockClient)
+	defer cleanup()
+
+	// the application doesn't respond until the callbacks are invoked
+	checkTx := func(tx types.Tx) (*abciclient.ReqRes, error) {
+		reqRes := abciclient.NewReqRes(abci.ToRequestCheckTx(abci.RequestCheckTx{Tx: tx}))
+		reqRes.Response = abci.ToResponseCheckTx(abci.ResponseCheckTx{Code: abci.CodeTypeOK})
+		mockClient.On("CheckTxAsync", abci.RequestCheckTx{Tx: tx}).Return(reqRes).Once()
+		return reqRes, mp.CheckTx(tx, nil, mempool.TxInfo{})
+	}
+
+	first, err := checkTx([]byte{0x01})
+	require.NoError(t, err)
+	_, err = checkTx([]byte{0x02})
+	require.NoError(t, err)
+
+	// rejected txs are not requested from the application
+	err = mp.CheckTx([]byte{0x03}, nil, mempool.TxInfo{})
+	require.Equal(t, mempool.ErrCheckTxInFlightLimit{Max: 2}, err)
+	require.Equal(t, 2, mp.checkTxLimiter.InFlight())
+
+	// duplicates and oversize txs are rejected before waiting for a slot
+	require.Equal(t, mempool.ErrTxInCache, mp.CheckTx([]byte{0x02}, nil, mempool.TxInfo{}))
+	require.IsType(t, mempool.ErrTxTooLarge{}, mp.CheckTx(make([]byte, conf.Mempool.MaxTxBytes+1), nil, mempool.TxInfo{}))
+	require.Equal(t, 2, mp.checkTxLimiter.InFlight())
+
+	// a response frees a slot
+	first.InvokeCallback()
+	require.Equal(t, 1, mp.checkTxLimiter.InFlight())
+
+	// txs rejected without being requested don't hold a slot
+	require.Equal(t, mempool.ErrTxInCache, mp.CheckTx([]byte{0x01}, nil, mempool.TxInfo{}))
+	require.Equal(t, 1, mp.checkTxLimiter.InFlight())
+	_, err = checkTx([]byte{0x03})
+	require.NoError(t, err)
+	require.Equal(t, 1, mp.Size())
+	mockClient.AssertExpectations(t)
+}
+
 func TestMempool_KeepInvalidTxsInCache(t *testing.T) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -638,6 +719,41 @@ func TestMempoolTxsBytes(t *testing.T) {
 
 }
 
+func TestMempoolRemoveTxByHash(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mp, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := checkTxs(t, mp, 3, mempool.UnknownPeerID)
+	require.NoError(t, mp.RemoveTxByHash(txs[1].Hash()))
+	assert.Equal(t, 2, mp.Size())
+	assert.EqualValues(t, len(txs[0])+len(txs[2]), mp.SizeBytes())
+	assert.Equal(t, types.Txs{txs[0], txs[2]}, mp.ReapMaxTxs(-1))
+	// it's removed from the cache too, so it can be checked again
+	assert.False(t, mp.cache.Has(txs[1]))
+
+	assert.ErrorIs(t, mp.RemoveTxByHash(txs[1].Hash()), mempool.ErrTxNotFound)
+	assert.Error(t, mp.RemoveTxByHash([]byte{0x01}))
+}
+
+func TestMempoolRemoveTxByHashCustomHasher(t *testing.T) {
+	types.RegisterTxHasher(func(tx types.Tx) []byte { return append([]byte("custom-"), tx...) })
+	t.Cleanup(func() { types.RegisterTxHasher(nil) })
+
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mp, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := checkTxs(t, mp, 3, mempool.UnknownPeerID)
+	// the hash reported by RPC is used, not the key of the tx
+	assert.ErrorIs(t, mp.RemoveTxByHash(txs[1].Hash()), mempool.ErrTxNotFound)
+	require.NoError(t, mp.RemoveTxByHash(types.TxHash(txs[1])))
+	assert.Equal(t, types.Txs{txs[0], txs[2]}, mp.ReapMaxTxs(-1))
+	assert.False(t, mp.cache.Has(txs[1]))
+}
+
 // This will non-deterministically catch some concurrency failures like
 // https://github.com/tendermint/tendermint/issues/3509
 // TODO: all of the tests should probably also run using the remote proxy app
@@ -702,3 +818,26 @@ func abciResponses(n int, code uint32) []*abci.ResponseDeliverTx {
 	}
 	return responses
 }
+
+func TestMempoolTxMarkGossiped(t *testing.T) {
+	memTx := &mempoolTx{tx: types.Tx("tx"), timestamp: time.Now()}
+	require.True(t, memTx.markGossiped())
+	require.False(t, memTx.markGossiped())
+}
+
+func TestMempoolPendingTxs(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mp, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := checkTxs(t, mp, 10, mempool.UnknownPeerID)
+	pending := mp.PendingTxs()
+	require.Len(t, pending, len(txs))
+	for i, tx := range pending {
+		require.Equal(t, txs[i], tx.Tx)
+		if i > 0 {
+			require.False(t, tx.Time.Before(pending[i-1].Time), "txs must be sorted by age")
+		}
+	}
+}
@@ -269,6 +269,11 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 				time.Sleep(mempool.PeerCatchupSleepIntervalMS * time.Millisecond)
 				continue
 			}
+			if memTx.markGossiped() {
+				elapsed := time.Since(memTx.timestamp)
+				memR.mempool.metrics.TimeToFirstGossip.Observe(elapsed.Seconds())
+				memR.Logger.Debug("first gossip of tx", "tx", memTx.tx.Hash(), "peer", peer.ID(), "elapsed", elapsed)
+			}
 		}
 
 		select {
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"runtime"
 	"sort"
@@ -39,7 +41,10 @@ type TxMempool struct {
 	config       *config.MempoolConfig
 	proxyAppConn proxy.AppConnMempool
 	metrics      *mempool.Metrics
-	cache        mempool.TxCache // seen transactions
+	cache        mempool.TxCache  // seen transactions
+	now          func() time.Time // the clock of the arrival times of transactions
+
+	checkTxLimiter *mempool.CheckTxLimiter // bounds the CheckTx requests in flight
 
 	// Atomically-updated fields
 	txsBytes int64 // atomic: the total size of all transactions in the mempool, in bytes
@@ -49,12 +54,18 @@ type TxMempool struct {
 	notifiedTxsAvailable bool
 	txsAvailable         chan struct{} // one value sent per height when mempool is not empty
 	preCheck             mempool.PreCheckFunc
+	txFilter             mempool.TxFilter
+	senderFunc           mempool.SenderFunc
 	postCheck            mempool.PostCheckFunc
 	height               int64 // the latest height passed to Update
 
 	txs        *clist.CList // valid transactions (passed CheckTx)
 	txByKey    map[types.TxKey]*clist.CElement
 	txBySender map[string]*clist.CElement // for sender != ""
+
+	// the total size of the transactions of each sender (as returned by
+	// senderOf), for the sender quota
+	senderBytes map[string]int64
 }
 
 // NewTxMempool constructs a new, empty priority mempool at the specified
@@ -73,11 +84,15 @@ func NewTxMempool(
 		proxyAppConn: proxyAppConn,
 		metrics:      mempool.NopMetrics(),
 		cache:        mempool.NopTxCache{},
+		now:          time.Now,
 		txs:          clist.New(),
 		mtx:          new(sync.RWMutex),
 		height:       height,
 		txByKey:      make(map[types.TxKey]*clist.CElement),
 		txBySender:   make(map[string]*clist.CElement),
+		senderBytes:  make(map[string]int64),
+
+		checkTxLimiter: mempool.NewCheckTxLimiter(cfg.MaxInFlightCheckTx, cfg.InFlightCheckTxTimeout),
 	}
 	if cfg.CacheSize > 0 {
 		txmp.cache = mempool.NewLRUTxCache(cfg.CacheSize)
@@ -97,6 +112,20 @@ func WithPreCheck(f mempool.PreCheckFunc) TxMempoolOption {
 	return func(txmp *TxMempool) { txmp.preCheck = f }
 }
 
+// WithTxFilter sets a filter executed on every transaction before CheckTx.
+// Transactions it rejects are never sent to the application. See
+// mempool.TxFilter.
+func WithTxFilter(f mempool.TxFilter) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.txFilter = f }
+}
+
+// WithSenderFunc sets the function returning the sender of a transaction for
+// the round-robin reaping and the sender quota, in place of the sender assigned
+// by the application in CheckTx.
+func WithSenderFunc(f mempool.SenderFunc) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.senderFunc = f }
+}
+
 // WithPostCheck sets a filter for the mempool to reject a transaction if
 // f(tx, resp) returns an error. This is executed after CheckTx. It only applies
 // to the first created block. After that, Update overwrites the existing value.
@@ -176,6 +205,19 @@ func (txmp *TxMempool) TxsAvailable() <-chan struct{} { return txmp.txsAvailable
 // discarded.
 func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo mempool.TxInfo) error {
 
+	// If a tx filter is defined, reject the transactions it filters out without
+	// invoking the application.
+	if txmp.txFilter != nil {
+		if code, log := txmp.txFilter(tx); code != abci.CodeTypeOK {
+			txmp.metrics.FilteredTxs.Add(1)
+			txmp.logger.Debug("filtered out transaction", "tx", tx.Hash(), "code", code, "log", log)
+			if cb != nil {
+				cb(abci.ToResponseCheckTx(abci.ResponseCheckTx{Code: code, Log: log}))
+			}
+			return nil
+		}
+	}
+
 	// During the initial phase of CheckTx, we do not need to modify any state.
 	// A transaction will not actually be added to the mempool until it survives
 	// a call to the ABCI CheckTx method and size constraint checks.
@@ -217,17 +259,26 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 		return err
 	}
 
+	if err := txmp.checkTxLimiter.Acquire(); err != nil {
+		txmp.cache.Remove(tx)
+		txmp.metrics.ThrottledTxs.Add(1)
+		txmp.logger.Debug("throttled transaction", "tx", tx.Hash(), "err", err)
+		return err
+	}
+
 	// Invoke an ABCI CheckTx for this transaction.
-	rsp, err := txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: tx})
+	rsp, err := func() (*abci.ResponseCheckTx, error) {
+		defer txmp.checkTxLimiter.Release()
+		return txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: tx})
+	}()
 	if err != nil {
 		txmp.cache.Remove(tx)
 		return err
 	}
 	wtx := &WrappedTx{
-		tx:        tx,
-		hash:      tx.Key(),
-		timestamp: time.Now().UTC(),
-		height:    height,
+		tx:     tx,
+		hash:   tx.Key(),
+		height: height,
 	}
 	wtx.SetPeer(txInfo.SenderID)
 	txmp.addNewTransaction(wtx, rsp)
@@ -237,31 +288,308 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 	return nil
 }
 
+// CheckTxBatch executes CheckTx for a batch of related transactions, which
+// are admitted to the mempool as a group: either all of them are added, or
+// none is.
+//
+// The transactions are first validated as by CheckTx, without invoking the
+// application, and the batch as a whole against the size and count limits of
+// the mempool. If any of them fails, the batch is rejected with an error
+// (ErrTxInBatch or ErrMempoolIsFull). Otherwise, each transaction is checked
+// by the application, and the batch is added only if all of them are valid and
+// fit in the mempool: unlike single transactions, a batch never evicts other
+// transactions. If not nil, cb is called with the response of each
+// transaction, in order. The MempoolError of the valid transactions of a
+// rejected batch gives the reason of the rejection.
+//
+// The application checks up to CheckTxConcurrency transactions of the batch
+// at a time, but they are added to the mempool in the order of the batch.
+func (txmp *TxMempool) CheckTxBatch(txs types.Txs, cb func(*abci.Response), txInfo mempool.TxInfo) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	height, err := txmp.checkTxBatchBasic(txs)
+	if err != nil {
+		return err
+	}
+
+	// Invoke an ABCI CheckTx for each transaction of the batch.
+	rsps := make([]*abci.ResponseCheckTx, len(txs))
+	errs := make([]error, len(txs))
+	checkTx := func(i int) {
+		if err := txmp.checkTxLimiter.Acquire(); err != nil {
+			txmp.metrics.ThrottledTxs.Add(1)
+			txmp.logger.Debug("throttled transaction batch", "tx", txs[i].Hash(), "err", err)
+			errs[i] = err
+			return
+		}
+		defer txmp.checkTxLimiter.Release()
+		rsps[i], errs[i] = txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: txs[i]})
+	}
+	if workers := txmp.checkTxWorkers(); workers > 1 {
+		g, start := taskgroup.New(nil).Limit(workers)
+		for i := range txs {
+			i := i
+			start(func() error {
+				checkTx(i)
+				return nil
+			})
+		}
+		_ = g.Wait()
+	} else {
+		for i := range txs {
+			checkTx(i)
+		}
+	}
+	for i, err := range errs {
+		if err != nil {
+			txmp.removeFromCache(txs)
+			return mempool.ErrTxInBatch{Index: i, Err: err}
+		}
+	}
+
+	wtxs := make([]*WrappedTx, len(txs))
+	for i, tx := range txs {
+		wtxs[i] = &WrappedTx{
+			tx:     tx,
+			hash:   tx.Key(),
+			height: height,
+		}
+		wtxs[i].SetPeer(txInfo.SenderID)
+	}
+	txmp.addNewBatch(wtxs, rsps)
+	if cb != nil {
+		for _, rsp := range rsps {
+			cb(&abci.Response{Value: &abci.Response_CheckTx{CheckTx: rsp}})
+		}
+	}
+	return nil
+}
+
+// checkTxBatchBasic validates the transactions of a batch without invoking the
+// application, and adds them to the cache. It returns the current height.
+func (txmp *TxMempool) checkTxBatchBasic(txs types.Txs) (int64, error) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	var batchBytes int64
+	seen := make(map[types.TxKey]struct{}, len(txs))
+	for i, tx := range txs {
+		if txmp.txFilter != nil {
+			if code, log := txmp.txFilter(tx); code != abci.CodeTypeOK {
+				txmp.metrics.FilteredTxs.Add(1)
+				return 0, mempool.ErrTxInBatch{Index: i, Err: fmt.Errorf("filtered out with code %d: %s", code, log)}
+			}
+		}
+		if len(tx) > txmp.config.MaxTxBytes {
+			return 0, mempool.ErrTxInBatch{
+				Index: i,
+				Err:   mempool.ErrTxTooLarge{Max: txmp.config.MaxTxBytes, Actual: len(tx)},
+			}
+		}
+		if txmp.preCheck != nil {
+			if err := txmp.preCheck(tx); err != nil {
+				return 0, mempool.ErrTxInBatch{Index: i, Err: mempool.ErrPreCheck{Reason: err}}
+			}
+		}
+		if _, ok := seen[tx.Key()]; ok {
+			return 0, mempool.ErrTxInBatch{Index: i, Err: errors.New("duplicate tx in the batch")}
+		}
+		seen[tx.Key()] = struct{}{}
+		batchBytes += int64(len(tx))
+	}
+
+	// The limits apply to the batch as a whole.
+	if err := txmp.canAddTxs(len(txs), batchBytes); err != nil {
+		return 0, err
+	}
+
+	// Early exit if the proxy connection has an error.
+	if err := txmp.proxyAppConn.Error(); err != nil {
+		return 0, err
+	}
+
+	for i, tx := range txs {
+		if !txmp.cache.Push(tx) {
+			txmp.removeFromCache(txs[:i])
+			return 0, mempool.ErrTxInBatch{Index: i, Err: mempool.ErrTxInCache}
+		}
+	}
+	return txmp.height, nil
+}
+
+// addNewBatch adds the transactions of a batch checked by the application to
+// the mempool if all of them are valid, and none of them otherwise.
+func (txmp *TxMempool) addNewBatch(wtxs []*WrappedTx, rsps []*abci.ResponseCheckTx) {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+
+	txmp.purgeTxsPastTTLDuration()
+
+	var (
+		reason      string
+		invalid     = make([]bool, len(wtxs))
+		senders     = make(map[string]struct{}, len(wtxs))
+		senderBytes = make(map[string]int64)
+		batchBytes  int64
+	)
+	for i, wtx := range wtxs {
+		rsp := rsps[i]
+		var err error
+		if txmp.postCheck != nil {
+			err = txmp.postCheck(wtx.tx, rsp)
+		}
+		if err != nil || rsp.Code != abci.CodeTypeOK {
+			if err != nil {
+				rsp.MempoolError = err.Error()
+			}
+			invalid[i] = true
+			if reason == "" {
+				reason = fmt.Sprintf("tx %X of the batch is invalid", wtx.tx.Hash())
+			}
+			continue
+		}
+
+		// As with single transactions, a sender may only have one transaction
+		// in the mempool.
+		if sender := rsp.Sender; sender != "" && reason == "" {
+			_, inPool := txmp.txBySender[sender]
+			_, inBatch := senders[sender]
+			if inPool || inBatch {
+				reason = fmt.Sprintf("tx already exists for sender %q", sender)
+			}
+			senders[sender] = struct{}{}
+		}
+		if s := txmp.senderOf(wtx.tx, rsp.Sender); s != "" {
+			senderBytes[s] += wtx.Size()
+		}
+		batchBytes += wtx.Size()
+	}
+	if reason == "" {
+		for s, bz := range senderBytes {
+			if err := txmp.checkSenderQuota(s, bz); err != nil {
+				reason = err.Error()
+				break
+			}
+		}
+	}
+	// The mempool may have filled up since the batch was validated.
+	if reason == "" {
+		if err := txmp.canAddTxs(len(wtxs), batchBytes); err != nil {
+			reason = err.Error()
+		}
+	}
+
+	if reason != "" {
+		txmp.logger.Info("rejected transaction batch", "num_txs", len(wtxs), "reason", reason)
+		for i, wtx := range wtxs {
+			if invalid[i] {
+				txmp.metrics.FailedTxs.Add(1)
+				if txmp.config.KeepInvalidTxsInCache {
+					continue
+				}
+			} else {
+				rsps[i].MempoolError = "rejected valid incoming transaction with its batch; " + reason
+				txmp.metrics.RejectedTxs.Add(1)
+			}
+			txmp.cache.Remove(wtx.tx)
+		}
+		return
+	}
+
+	for i, wtx := range wtxs {
+		wtx.SetGasWanted(rsps[i].GasWanted)
+		wtx.SetPriority(rsps[i].Priority)
+		wtx.SetSender(rsps[i].Sender)
+		txmp.insertTx(wtx)
+		txmp.metrics.TxSizeBytes.Observe(float64(wtx.Size()))
+	}
+	txmp.metrics.Size.Set(float64(txmp.Size()))
+	txmp.logger.Debug(
+		"inserted new valid transaction batch",
+		"num_txs", len(wtxs),
+		"height", txmp.height,
+		"mempool_num_txs", txmp.Size(),
+	)
+	txmp.notifyTxsAvailable()
+}
+
+func (txmp *TxMempool) removeFromCache(txs types.Txs) {
+	for _, tx := range txs {
+		txmp.cache.Remove(tx)
+	}
+}
+
 // RemoveTxByKey removes the transaction with the specified key from the
 // mempool. It reports an error if no such transaction exists.  This operation
 // does not remove the transaction from the cache.
 func (txmp *TxMempool) RemoveTxByKey(txKey types.TxKey) error {
 	txmp.mtx.Lock()
 	defer txmp.mtx.Unlock()
-	return txmp.removeTxByKey(txKey)
+	if err := txmp.removeTxByKey(txKey); err != nil {
+		return err
+	}
+	txmp.metrics.RemovedTxs.With("reason", mempool.RemovalReasonRemoved).Add(1)
+	return nil
+}
+
+// RemoveTxByHash removes the transaction with the specified types.TxHash from
+// the mempool and the cache. It returns mempool.ErrTxNotFound if no such
+// transaction exists.
+func (txmp *TxMempool) RemoveTxByHash(hash []byte) error {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+
+	elt := txmp.txByHash(hash)
+	if elt == nil {
+		return mempool.ErrTxNotFound
+	}
+	w := elt.Value.(*WrappedTx)
+	txmp.removeTxWithReason(elt, mempool.RemovalReasonRemoved)
+	txmp.cache.Remove(w.tx)
+	txmp.metrics.Size.Set(float64(txmp.Size()))
+	txmp.logger.Debug("removed transaction", "tx", fmt.Sprintf("%X", w.tx.Hash()))
+	return nil
+}
+
+// txByHash returns the element of the transaction with the specified
+// types.TxHash, or nil. The hash is the key of the transaction, unless a custom
+// TxHasher is registered, in which case every transaction is hashed. The caller
+// must hold txmp.mtx.
+func (txmp *TxMempool) txByHash(hash []byte) *clist.CElement {
+	if txKey, err := mempool.TxKeyFromHash(hash); err == nil {
+		if elt, ok := txmp.txByKey[txKey]; ok && bytes.Equal(types.TxHash(elt.Value.(*WrappedTx).tx), hash) {
+			return elt
+		}
+	}
+	for elt := txmp.txs.Front(); elt != nil; elt = elt.Next() {
+		if bytes.Equal(types.TxHash(elt.Value.(*WrappedTx).tx), hash) {
+			return elt
+		}
+	}
+	return nil
 }
 
 // removeTxByKey removes the specified transaction key from the mempool.
 // The caller must hold txmp.mtx excluxively.
 func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 	if elt, ok := txmp.txByKey[key]; ok {
-		w := elt.Value.(*WrappedTx)
-		delete(txmp.txByKey, key)
-		delete(txmp.txBySender, w.sender)
-		txmp.txs.Remove(elt)
-		elt.DetachPrev()
-		elt.DetachNext()
-		atomic.AddInt64(&txmp.txsBytes, -w.Size())
+		txmp.removeTxByElement(elt)
 		return nil
 	}
 	return fmt.Errorf("transaction %x not found", key)
 }
 
+// removeTxWithReason removes the transaction of elt from the mempool, and
+// counts its removal for the given reason.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) removeTxWithReason(elt *clist.CElement, reason string) {
+	txmp.removeTxByElement(elt)
+	txmp.metrics.RemovedTxs.With("reason", reason).Add(1)
+}
+
 // removeTxByElement removes the specified transaction element from the mempool.
 // The caller must hold txmp.mtx exclusively.
 func (txmp *TxMempool) removeTxByElement(elt *clist.CElement) {
@@ -272,6 +600,12 @@ func (txmp *TxMempool) removeTxByElement(elt *clist.CElement) {
 	elt.DetachPrev()
 	elt.DetachNext()
 	atomic.AddInt64(&txmp.txsBytes, -w.Size())
+	if s := txmp.senderOf(w.tx, w.sender); s != "" {
+		txmp.senderBytes[s] -= w.Size()
+		if txmp.senderBytes[s] <= 0 {
+			delete(txmp.senderBytes, s)
+		}
+	}
 }
 
 // Flush purges the contents of the mempool and the cache, leaving both empty.
@@ -311,10 +645,56 @@ func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
 	return all
 }
 
+// reapOrder returns the transactions in the order in which they are reaped.
+// In round-robin mode, the transactions sorted by priority are interleaved
+// across senders: each round takes the next transaction of every sender, the
+// senders being ordered by their highest priority transaction. At most
+// ReapMaxTxsPerSender transactions of a sender are returned, if set.
+func (txmp *TxMempool) reapOrder() []*WrappedTx {
+	sorted := txmp.allEntriesSorted()
+	if txmp.config.ReapMode != config.ReapModeRoundRobin {
+		return sorted
+	}
+
+	var (
+		groups   [][]*WrappedTx
+		bySender = make(map[string]int)
+	)
+	for _, w := range sorted {
+		sender := txmp.senderOf(w.tx, w.Sender())
+		i, ok := bySender[sender]
+		if !ok || sender == "" {
+			i = len(groups)
+			groups = append(groups, nil)
+			if sender != "" {
+				bySender[sender] = i
+			}
+		}
+		groups[i] = append(groups[i], w)
+	}
+
+	max := txmp.config.ReapMaxTxsPerSender
+	order := make([]*WrappedTx, 0, len(sorted))
+	for round := 0; max <= 0 || round < max; round++ {
+		added := false
+		for _, group := range groups {
+			if round < len(group) {
+				order = append(order, group[round])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return order
+}
+
 // ReapMaxBytesMaxGas returns a slice of valid transactions that fit within the
 // size and gas constraints. The results are ordered by nonincreasing priority,
-// with ties broken by increasing order of arrival.  Reaping transactions does
-// not remove them from the mempool.
+// with ties broken by increasing order of arrival, or as configured by the
+// reap mode (see reapOrder). Reaping transactions does not remove them from
+// the mempool.
 //
 // If maxBytes < 0, no limit is set on the total size in bytes.
 // If maxGas < 0, no limit is set on the total gas cost.
@@ -325,7 +705,7 @@ func (txmp *TxMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	var totalGas, totalBytes int64
 
 	var keep []types.Tx //nolint:prealloc
-	for _, w := range txmp.allEntriesSorted() {
+	for _, w := range txmp.reapOrder() {
 		// N.B. When computing byte size, we need to include the overhead for
 		// encoding as protobuf to send to the application.
 		totalGas += w.gasWanted
@@ -348,7 +728,8 @@ func (txmp *TxMempool) TxsFront() *clist.CElement { return txmp.txs.Front() }
 
 // ReapMaxTxs returns up to max transactions from the mempool. The results are
 // ordered by nonincreasing priority with ties broken by increasing order of
-// arrival. Reaping transactions does not remove them from the mempool.
+// arrival, or as configured by the reap mode (see reapOrder). Reaping
+// transactions does not remove them from the mempool.
 //
 // If max < 0, all transactions in the mempool are reaped.
 //
@@ -357,7 +738,7 @@ func (txmp *TxMempool) TxsFront() *clist.CElement { return txmp.txs.Front() }
 func (txmp *TxMempool) ReapMaxTxs(max int) types.Txs {
 	var keep []types.Tx //nolint:prealloc
 
-	for _, w := range txmp.allEntriesSorted() {
+	for _, w := range txmp.reapOrder() {
 		if max >= 0 && len(keep) >= max {
 			break
 		}
@@ -366,6 +747,31 @@ func (txmp *TxMempool) ReapMaxTxs(max int) types.Txs {
 	return keep
 }
 
+// PendingTxs returns all the transactions in the mempool, sorted by order of
+// arrival.
+func (txmp *TxMempool) PendingTxs() []mempool.PendingTx {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	txs := make([]mempool.PendingTx, 0, len(txmp.txByKey))
+	for _, tx := range txmp.txByKey {
+		w := tx.Value.(*WrappedTx)
+		txs = append(txs, mempool.PendingTx{
+			Tx:       w.tx,
+			Height:   w.height,
+			Time:     w.timestamp,
+			Priority: w.priority,
+		})
+	}
+	sort.SliceStable(txs, func(i, j int) bool {
+		if !txs[i].Time.Equal(txs[j].Time) {
+			return txs[i].Time.Before(txs[j].Time)
+		}
+		return txs[i].Height < txs[j].Height
+	})
+	return txs
+}
+
 // Update removes all the given transactions from the mempool and the cache,
 // and updates the current block height. The blockTxs and deliverTxResponses
 // must have the same length with each response corresponding to the tx at the
@@ -411,7 +817,9 @@ func (txmp *TxMempool) Update(
 		}
 
 		// Regardless of success, remove the transaction from the mempool.
-		_ = txmp.removeTxByKey(tx.Key())
+		if elt, ok := txmp.txByKey[tx.Key()]; ok {
+			txmp.removeTxWithReason(elt, mempool.RemovalReasonCommitted)
+		}
 	}
 
 	txmp.purgeExpiredTxs(blockHeight)
@@ -453,6 +861,10 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 		err = txmp.postCheck(wtx.tx, checkTxRes)
 	}
 
+	// Evict the transactions which have outlived their TTL without waiting for
+	// the next block, as they may make room for the new one.
+	txmp.purgeTxsPastTTLDuration()
+
 	if err != nil || checkTxRes.Code != abci.CodeTypeOK {
 		txmp.logger.Info(
 			"rejected bad transaction",
@@ -502,6 +914,20 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 		}
 	}
 
+	// A sender may not occupy more than its quota, even if the mempool isn't
+	// full: evicting other transactions doesn't make room for it.
+	if err := txmp.checkSenderQuota(txmp.senderOf(wtx.tx, sender), wtx.Size()); err != nil {
+		txmp.cache.Remove(wtx.tx)
+		txmp.logger.Debug(
+			"rejected valid incoming transaction; sender quota exceeded",
+			"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+			"err", err.Error(),
+		)
+		checkTxRes.MempoolError = err.Error()
+		txmp.metrics.RejectedTxs.Add(1)
+		return
+	}
+
 	// At this point the application has ruled the transaction valid, but the
 	// mempool might be full. If so, find the lowest-priority items with lower
 	// priority than the application assigned to this new one, and evict as many
@@ -562,7 +988,7 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 				"old_tx", fmt.Sprintf("%X", w.tx.Hash()),
 				"old_priority", w.priority,
 			)
-			txmp.removeTxByElement(vic)
+			txmp.removeTxWithReason(vic, mempool.RemovalReasonEvicted)
 			txmp.cache.Remove(w.tx)
 			txmp.metrics.EvictedTxs.Add(1)
 
@@ -592,16 +1018,56 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 	txmp.notifyTxsAvailable()
 }
 
+// insertTx adds wtx to the mempool. Its timestamp is taken here, under the
+// lock, so that the order of the transactions in txmp.txs agrees with their
+// timestamps even when the application completes concurrent CheckTx calls out
+// of order.
+//
+// The caller must hold txmp.mtx exclusively.
 func (txmp *TxMempool) insertTx(wtx *WrappedTx) {
+	wtx.timestamp = txmp.now().UTC()
 	elt := txmp.txs.PushBack(wtx)
 	txmp.txByKey[wtx.tx.Key()] = elt
 	if s := wtx.Sender(); s != "" {
 		txmp.txBySender[s] = elt
 	}
+	if s := txmp.senderOf(wtx.tx, wtx.Sender()); s != "" {
+		txmp.senderBytes[s] += wtx.Size()
+	}
 
 	atomic.AddInt64(&txmp.txsBytes, wtx.Size())
 }
 
+// senderOf returns the sender of a transaction for the reap order and the
+// sender quota: the one returned by the sender function, if any, and the one
+// assigned by the application otherwise.
+func (txmp *TxMempool) senderOf(tx types.Tx, appSender string) string {
+	if txmp.senderFunc != nil {
+		return txmp.senderFunc(tx)
+	}
+	return appSender
+}
+
+// checkSenderQuota returns an error if txBytes more bytes of transactions of
+// the given sender would exceed its quota.
+//
+// The caller must hold txmp.mtx.
+func (txmp *TxMempool) checkSenderQuota(sender string, txBytes int64) error {
+	max := txmp.config.MaxTxBytesPerSender
+	if max <= 0 || sender == "" {
+		return nil
+	}
+	if senderBytes := txmp.senderBytes[sender]; senderBytes+txBytes > max {
+		return mempool.ErrSenderQuotaExceeded{
+			Sender:      sender,
+			SenderBytes: senderBytes,
+			TxBytes:     txBytes,
+			MaxBytes:    max,
+		}
+	}
+	return nil
+}
+
 // handleRecheckResult handles the responses from ABCI CheckTx calls issued
 // during the recheck phase of a block Update.  It removes any transactions
 // invalidated by the application.
@@ -640,7 +1106,7 @@ func (txmp *TxMempool) handleRecheckResult(tx types.Tx, checkTxRes *abci.Respons
 		"err", err,
 		"code", checkTxRes.Code,
 	)
-	txmp.removeTxByElement(elt)
+	txmp.removeTxWithReason(elt, mempool.RemovalReasonRecheck)
 	txmp.metrics.FailedTxs.Add(1)
 	if !txmp.config.KeepInvalidTxsInCache {
 		txmp.cache.Remove(wtx.tx)
@@ -673,7 +1139,13 @@ func (txmp *TxMempool) recheckTransactions() {
 	// Issue CheckTx calls for each remaining transaction, and when all the
 	// rechecks are complete signal watchers that transactions may be available.
 	go func() {
-		g, start := taskgroup.New(nil).Limit(2 * runtime.NumCPU())
+		// check_tx_concurrency can only raise the number of rechecks in
+		// flight, never lower it below the default
+		workers := 2 * runtime.NumCPU()
+		if w := txmp.checkTxWorkers(); w > workers {
+			workers = w
+		}
+		g, start := taskgroup.New(nil).Limit(workers)
 
 		for _, wtx := range wtxs {
 			wtx := wtx
@@ -702,18 +1174,33 @@ func (txmp *TxMempool) recheckTransactions() {
 	}()
 }
 
+// checkTxWorkers returns the number of CheckTx calls of a batch to run
+// concurrently.
+func (txmp *TxMempool) checkTxWorkers() int {
+	if txmp.config.CheckTxConcurrency > 1 {
+		return txmp.config.CheckTxConcurrency
+	}
+	return 1
+}
+
 // canAddTx returns an error if we cannot insert the provided *WrappedTx into
 // the mempool due to mempool configured constraints. Otherwise, nil is
 // returned and the transaction can be inserted into the mempool.
 func (txmp *TxMempool) canAddTx(wtx *WrappedTx) error {
-	numTxs := txmp.Size()
-	txBytes := txmp.SizeBytes()
+	return txmp.canAddTxs(1, wtx.Size())
+}
+
+// canAddTxs returns an error if numTxs transactions of the given total size
+// cannot be inserted into the mempool due to mempool configured constraints.
+func (txmp *TxMempool) canAddTxs(numTxs int, txsBytes int64) error {
+	size := txmp.Size()
+	sizeBytes := txmp.SizeBytes()
 
-	if numTxs >= txmp.config.Size || wtx.Size()+txBytes > txmp.config.MaxTxsBytes {
+	if size+numTxs > txmp.config.Size || sizeBytes+txsBytes > txmp.config.MaxTxsBytes {
 		return mempool.ErrMempoolIsFull{
-			NumTxs:      numTxs,
+			NumTxs:      size,
 			MaxTxs:      txmp.config.Size,
-			TxsBytes:    txBytes,
+			TxsBytes:    sizeBytes,
 			MaxTxsBytes: txmp.config.MaxTxsBytes,
 		}
 	}
@@ -731,7 +1218,7 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 		return // nothing to do
 	}
 
-	now := time.Now()
+	now := txmp.now()
 	cur := txmp.txs.Front()
 	for cur != nil {
 		// N.B. Grab the next element first, since if we remove cur its successor
@@ -740,11 +1227,11 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 
 		w := cur.Value.(*WrappedTx)
 		if txmp.config.TTLNumBlocks > 0 && (blockHeight-w.height) > txmp.config.TTLNumBlocks {
-			txmp.removeTxByElement(cur)
+			txmp.removeTxWithReason(cur, mempool.RemovalReasonExpired)
 			txmp.cache.Remove(w.tx)
 			txmp.metrics.EvictedTxs.Add(1)
 		} else if txmp.config.TTLDuration > 0 && now.Sub(w.timestamp) > txmp.config.TTLDuration {
-			txmp.removeTxByElement(cur)
+			txmp.removeTxWithReason(cur, mempool.RemovalReasonExpired)
 			txmp.cache.Remove(w.tx)
 			txmp.metrics.EvictedTxs.Add(1)
 		}
@@ -752,6 +1239,38 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 	}
 }
 
+// purgeTxsPastTTLDuration removes the transactions at the front of the
+// mempool which have exceeded TTLDuration, if any. Since transactions are
+// ordered by arrival, it stops at the first one which hasn't: one inserted
+// slightly out of order is removed by the next purge. Transactions removed by
+// this operation are removed from the cache, so that they may be admitted
+// again.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) purgeTxsPastTTLDuration() {
+	if txmp.config.TTLDuration == 0 {
+		return
+	}
+
+	now := txmp.now()
+	for cur := txmp.txs.Front(); cur != nil; {
+		next := cur.Next()
+		w := cur.Value.(*WrappedTx)
+		if now.Sub(w.timestamp) <= txmp.config.TTLDuration {
+			return
+		}
+		txmp.logger.Debug(
+			"evicted expired transaction",
+			"tx", fmt.Sprintf("%X", w.tx.Hash()),
+			"age", now.Sub(w.timestamp),
+		)
+		txmp.removeTxWithReason(cur, mempool.RemovalReasonExpired)
+		txmp.cache.Remove(w.tx)
+		txmp.metrics.EvictedTxs.Add(1)
+		cur = next
+	}
+}
+
 func (txmp *TxMempool) notifyTxsAvailable() {
 	if txmp.Size() == 0 {
 		return // nothing to do
@@ -3,12 +3,14 @@ package v1
 import (
 	"fmt"
 	"math/rand"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
 )
 
 func BenchmarkTxMempool_CheckTx(b *testing.B) {
@@ -30,3 +32,70 @@ func BenchmarkTxMempool_CheckTx(b *testing.B) {
 		require.NoError(b, txmp.CheckTx(tx, nil, mempool.TxInfo{}))
 	}
 }
+
+func BenchmarkTxMempool_CheckTxConcurrency(b *testing.B) {
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency-%d", concurrency), func(b *testing.B) {
+			txmp := setupWithConcurrency(b, 10000, concurrency)
+			txmp.config.Size = b.N
+			txs := benchmarkTxBatches(b, 1, b.N)[0]
+			var next int64 = -1
+
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					tx := txs[atomic.AddInt64(&next, 1)]
+					require.NoError(b, txmp.CheckTx(tx, nil, mempool.TxInfo{}))
+				}
+			})
+		})
+	}
+}
+
+// benchmarkTxBatches returns n batches of batchSize random transactions.
+func benchmarkTxBatches(b *testing.B, n, batchSize int) []types.Txs {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	batches := make([]types.Txs, n)
+	for i := range batches {
+		batches[i] = make(types.Txs, batchSize)
+		for j := range batches[i] {
+			prefix := make([]byte, 20)
+			_, err := rng.Read(prefix)
+			require.NoError(b, err)
+
+			priority := int64(rng.Intn(9999-1000) + 1000)
+			batches[i][j] = []byte(fmt.Sprintf("%X=%d", prefix, priority))
+		}
+	}
+	return batches
+}
+
+func BenchmarkTxMempool_CheckTxBatch(b *testing.B) {
+	for _, batchSize := range []int{10, 100} {
+		b.Run(fmt.Sprintf("batch-%d", batchSize), func(b *testing.B) {
+			txmp := setup(b, 10000)
+			txmp.config.Size = b.N * batchSize
+			batches := benchmarkTxBatches(b, b.N, batchSize)
+
+			b.ResetTimer()
+
+			for n := 0; n < b.N; n++ {
+				require.NoError(b, txmp.CheckTxBatch(batches[n], nil, mempool.TxInfo{}))
+			}
+		})
+		b.Run(fmt.Sprintf("serial-%d", batchSize), func(b *testing.B) {
+			txmp := setup(b, 10000)
+			txmp.config.Size = b.N * batchSize
+			batches := benchmarkTxBatches(b, b.N, batchSize)
+
+			b.ResetTimer()
+
+			for n := 0; n < b.N; n++ {
+				for _, tx := range batches[n] {
+					require.NoError(b, txmp.CheckTx(tx, nil, mempool.TxInfo{}))
+				}
+			}
+		})
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/abci/example/code"
@@ -74,12 +76,20 @@ func (app *application) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
 
 func setup(t testing.TB, cacheSize int, options ...TxMempoolOption) *TxMempool {
 	t.Helper()
+	return setupWithConcurrency(t, cacheSize, 1, options...)
+}
+
+// setupWithConcurrency is like setup, but lets up to concurrency CheckTx calls
+// run against the application at the same time.
+func setupWithConcurrency(t testing.TB, cacheSize, concurrency int, options ...TxMempoolOption) *TxMempool {
+	t.Helper()
 
 	app := &application{kvstore.NewApplication()}
-	cc := proxy.NewLocalClientCreator(app)
+	cc := proxy.WithCheckTxConcurrency(proxy.NewLocalClientCreator(app), concurrency)
 
 	cfg := config.ResetTestRoot(strings.ReplaceAll(t.Name(), "/", "|"))
 	cfg.Mempool.CacheSize = cacheSize
+	cfg.Mempool.CheckTxConcurrency = concurrency
 
 	appConnMem, err := cc.NewABCIClient()
 	require.NoError(t, err)
@@ -277,6 +287,101 @@ func TestTxMempool_Eviction(t *testing.T) {
 	require.False(t, txExists("key7=0006=7"))
 }
 
+// reasonCounter is a metrics.Counter recording its count per value of the
+// "reason" label.
+type reasonCounter struct {
+	mtx    *sync.Mutex
+	counts map[string]float64
+	reason string
+}
+
+func newReasonCounter() *reasonCounter {
+	return &reasonCounter{mtx: new(sync.Mutex), counts: make(map[string]float64)}
+}
+
+func (c *reasonCounter) With(labelValues ...string) metrics.Counter {
+	with := *c
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "reason" {
+			with.reason = labelValues[i+1]
+		}
+	}
+	return &with
+}
+
+func (c *reasonCounter) Add(delta float64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.counts[c.reason] += delta
+}
+
+func (c *reasonCounter) count(reason string) float64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.counts[reason]
+}
+
+func TestTxMempool_RemovedTxsMetrics(t *testing.T) {
+	removed := newReasonCounter()
+	m := mempool.NopMetrics()
+	m.RemovedTxs = removed
+	var rejectRecheck int32
+	txmp := setup(t, 100, WithMetrics(m), WithPostCheck(func(tx types.Tx, _ *abci.ResponseCheckTx) error {
+		if atomic.LoadInt32(&rejectRecheck) == 1 && bytes.HasPrefix(tx, []byte("recheck")) {
+			return errors.New("no longer valid")
+		}
+		return nil
+	}))
+	txmp.config.Size = 5
+	txmp.config.TTLNumBlocks = 1
+	txmp.config.Recheck = true
+	txmp.EnableTxsAvailable()
+
+	update := func(height int64, txs ...string) {
+		block := make(types.Txs, len(txs))
+		responses := make([]*abci.ResponseDeliverTx, len(txs))
+		for i, tx := range txs {
+			block[i] = []byte(tx)
+			responses[i] = &abci.ResponseDeliverTx{Code: abci.CodeTypeOK}
+		}
+		txmp.Lock()
+		require.NoError(t, txmp.Update(height, block, responses, nil, nil))
+		rechecking := txmp.Size() > 0
+		txmp.Unlock()
+		if rechecking {
+			<-txmp.TxsAvailable()
+		}
+	}
+
+	// a committed tx, a tx rejected by the recheck, and one left to expire
+	mustCheckTx(t, txmp, "committed=k1=10")
+	mustCheckTx(t, txmp, "recheck=k2=10")
+	mustCheckTx(t, txmp, "expired=k3=10")
+	<-txmp.TxsAvailable()
+	atomic.StoreInt32(&rejectRecheck, 1)
+	update(1, "committed=k1=10")
+	require.Equal(t, float64(1), removed.count(mempool.RemovalReasonCommitted))
+	require.Equal(t, float64(1), removed.count(mempool.RemovalReasonRecheck))
+
+	// a tx past its TTL
+	update(3)
+	require.Equal(t, float64(1), removed.count(mempool.RemovalReasonExpired))
+	require.Equal(t, 0, txmp.Size())
+
+	// a tx evicted for a higher priority one
+	for i := 0; i < 5; i++ {
+		mustCheckTx(t, txmp, fmt.Sprintf("evicted%d=k%d=%d", i, i, i+1))
+	}
+	mustCheckTx(t, txmp, "new=k6=100")
+	require.Equal(t, float64(1), removed.count(mempool.RemovalReasonEvicted))
+
+	// a tx removed by hash or key
+	require.NoError(t, txmp.RemoveTxByHash(types.Tx("new=k6=100").Hash()))
+	require.NoError(t, txmp.RemoveTxByKey(types.Tx("evicted1=k1=2").Key()))
+	require.Error(t, txmp.RemoveTxByKey(types.Tx("evicted1=k1=2").Key()))
+	require.Equal(t, float64(2), removed.count(mempool.RemovalReasonRemoved))
+}
+
 func TestTxMempool_Flush(t *testing.T) {
 	txmp := setup(t, 0)
 	txs := checkTxs(t, txmp, 100, 0)
@@ -352,6 +457,86 @@ func TestTxMempool_ReapMaxBytesMaxGas(t *testing.T) {
 	require.Len(t, reapedTxs, 25)
 }
 
+func TestTxMempool_ReapRoundRobin(t *testing.T) {
+	// the application assigns a distinct sender to every tx, so group them by
+	// account, the first letter of the sender
+	txmp := setup(t, 0, WithSenderFunc(func(tx types.Tx) string { return string(tx[:1]) }))
+	for _, spec := range []string{"a1=k1=100", "a2=k2=90", "a3=k3=80", "b1=k4=50", "c1=k5=40"} {
+		mustCheckTx(t, txmp, spec)
+	}
+	reaped := func() []string {
+		txs := txmp.ReapMaxTxs(-1)
+		require.Equal(t, txs, txmp.ReapMaxBytesMaxGas(-1, -1))
+		specs := make([]string, len(txs))
+		for i, tx := range txs {
+			specs[i] = string(tx)
+		}
+		return specs
+	}
+
+	// strict priority lets the high-volume sender come first
+	require.Equal(t, []string{"a1=k1=100", "a2=k2=90", "a3=k3=80", "b1=k4=50", "c1=k5=40"}, reaped())
+
+	txmp.config.ReapMode = config.ReapModeRoundRobin
+	require.Equal(t, []string{"a1=k1=100", "b1=k4=50", "c1=k5=40", "a2=k2=90", "a3=k3=80"}, reaped())
+
+	txmp.config.ReapMaxTxsPerSender = 2
+	require.Equal(t, []string{"a1=k1=100", "b1=k4=50", "c1=k5=40", "a2=k2=90"}, reaped())
+	require.Equal(t, types.Txs{types.Tx("a1=k1=100"), types.Tx("b1=k4=50")}, txmp.ReapMaxTxs(2))
+
+	// without a sender function, the senders assigned by the application are
+	// all distinct
+	txmp.senderFunc = nil
+	require.Equal(t, []string{"a1=k1=100", "a2=k2=90"}, reaped()[:2])
+}
+
+func TestTxMempool_SenderQuota(t *testing.T) {
+	// group the txs by account, the first letter of the sender, so that an
+	// account may have several txs
+	txmp := setup(t, 100, WithSenderFunc(func(tx types.Tx) string { return string(tx[:1]) }))
+	txmp.config.MaxTxBytesPerSender = 20
+	checkTx := func(spec string) string {
+		var rsp *abci.ResponseCheckTx
+		require.NoError(t, txmp.CheckTx([]byte(spec), func(res *abci.Response) {
+			rsp = res.GetCheckTx()
+		}, mempool.TxInfo{}))
+		return rsp.MempoolError
+	}
+
+	// "a1=k1=100" is 9 bytes long, the others 8
+	require.Empty(t, checkTx("a1=k1=100"))
+	require.Empty(t, checkTx("a2=k2=90"))
+	require.Equal(t, mempool.ErrSenderQuotaExceeded{
+		Sender:      "a",
+		SenderBytes: 17,
+		TxBytes:     8,
+		MaxBytes:    20,
+	}.Error(), checkTx("a3=k3=80"))
+	require.False(t, txmp.cache.Has([]byte("a3=k3=80")))
+	// other senders aren't affected
+	require.Empty(t, checkTx("b1=k4=50"))
+	require.Equal(t, 3, txmp.Size())
+
+	// the quota is released once the txs of the sender are committed
+	txmp.Lock()
+	require.NoError(t, txmp.Update(1, types.Txs{[]byte("a1=k1=100")}, []*abci.ResponseDeliverTx{{Code: abci.CodeTypeOK}}, nil, nil))
+	txmp.Unlock()
+	require.Empty(t, checkTx("a3=k3=80"))
+	require.Equal(t, int64(16), txmp.senderBytes["a"])
+
+	// or removed
+	require.NoError(t, txmp.RemoveTxByHash(types.Tx("a2=k2=90").Hash()))
+	require.NoError(t, txmp.RemoveTxByHash(types.Tx("a3=k3=80").Hash()))
+	_, ok := txmp.senderBytes["a"]
+	require.False(t, ok)
+
+	// a batch may not exceed the quota either
+	require.NoError(t, txmp.CheckTxBatch(types.Txs{
+		[]byte("a4=k6=10"), []byte("a5=k7=10"), []byte("a6=k8=10"),
+	}, nil, mempool.TxInfo{}))
+	require.Equal(t, 1, txmp.Size())
+}
+
 func TestTxMempool_ReapMaxTxs(t *testing.T) {
 	txmp := setup(t, 0)
 	tTxs := checkTxs(t, txmp, 100, 0)
@@ -418,6 +603,159 @@ func TestTxMempool_CheckTxExceedsMaxSize(t *testing.T) {
 	require.NoError(t, txmp.CheckTx(tx, nil, mempool.TxInfo{SenderID: 0}))
 }
 
+func TestTxMempool_CheckTxFilter(t *testing.T) {
+	filter := func(tx types.Tx) (uint32, string) {
+		if bytes.HasPrefix(tx, []byte("spam")) {
+			return code.CodeTypeUnauthorized, "spam"
+		}
+		return abci.CodeTypeOK, ""
+	}
+	txmp := setup(t, 100, WithTxFilter(filter))
+
+	var res *abci.ResponseCheckTx
+	require.NoError(t, txmp.CheckTx([]byte("spam=1"), func(r *abci.Response) {
+		res = r.GetCheckTx()
+	}, mempool.TxInfo{SenderID: 0}))
+	require.NotNil(t, res)
+	require.Equal(t, code.CodeTypeUnauthorized, res.Code)
+	require.Equal(t, "spam", res.Log)
+	require.Zero(t, txmp.Size())
+
+	// a filtered out transaction is not cached, and may be checked again
+	require.True(t, txmp.cache.Push([]byte("spam=1")))
+
+	mustCheckTx(t, txmp, "sender-0=key=1")
+	require.Equal(t, 1, txmp.Size())
+}
+
+func TestTxMempool_CheckTxBatch(t *testing.T) {
+	txmp := setup(t, 100)
+	txmp.config.Size = 6
+
+	checkTxBatch := func(txs ...string) ([]*abci.ResponseCheckTx, error) {
+		batch := make(types.Txs, len(txs))
+		for i, tx := range txs {
+			batch[i] = types.Tx(tx)
+		}
+		var rsps []*abci.ResponseCheckTx
+		err := txmp.CheckTxBatch(batch, func(r *abci.Response) {
+			rsps = append(rsps, r.GetCheckTx())
+		}, mempool.TxInfo{SenderID: 1})
+		return rsps, err
+	}
+
+	rsps, err := checkTxBatch("sender-0=key0=1", "sender-1=key1=2", "sender-2=key2=3")
+	require.NoError(t, err)
+	require.Len(t, rsps, 3)
+	for _, rsp := range rsps {
+		require.Equal(t, abci.CodeTypeOK, rsp.Code)
+		require.Empty(t, rsp.MempoolError)
+	}
+	require.Equal(t, 3, txmp.Size())
+
+	// an invalid transaction rejects the whole batch
+	rsps, err = checkTxBatch("sender-3=key3=4", "invalid")
+	require.NoError(t, err)
+	require.Len(t, rsps, 2)
+	require.Equal(t, abci.CodeTypeOK, rsps[0].Code)
+	require.Contains(t, rsps[0].MempoolError, "rejected valid incoming transaction with its batch")
+	require.NotEqual(t, abci.CodeTypeOK, rsps[1].Code)
+	require.Equal(t, 3, txmp.Size())
+
+	// as does a sender which already has a transaction in the mempool
+	rsps, err = checkTxBatch("sender-3=key3=4", "sender-0=key4=5")
+	require.NoError(t, err)
+	require.Contains(t, rsps[0].MempoolError, "tx already exists for sender")
+	require.Equal(t, 3, txmp.Size())
+
+	// the transactions of rejected batches are not cached
+	rsps, err = checkTxBatch("sender-3=key3=4")
+	require.NoError(t, err)
+	require.Empty(t, rsps[0].MempoolError)
+	require.Equal(t, 4, txmp.Size())
+
+	// the basic validation fails the batch without invoking the application
+	var batchErr mempool.ErrTxInBatch
+	_, err = checkTxBatch("sender-5=key5=6", "sender-0=key0=1")
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, 1, batchErr.Index)
+	require.ErrorIs(t, err, mempool.ErrTxInCache)
+
+	_, err = checkTxBatch("sender-5=key5=6", "sender-5=key5=6")
+	require.ErrorAs(t, err, &batchErr)
+
+	_, err = checkTxBatch("sender-5=key5=6", string(make([]byte, txmp.config.MaxTxBytes+1)))
+	require.ErrorAs(t, err, &mempool.ErrTxTooLarge{})
+
+	// the size limits apply to the batch as a whole
+	_, err = checkTxBatch("sender-5=key5=6", "sender-6=key6=7", "sender-7=key7=8")
+	require.ErrorAs(t, err, &mempool.ErrMempoolIsFull{})
+	require.Equal(t, 4, txmp.Size())
+
+	rsps, err = checkTxBatch("sender-5=key5=6")
+	require.NoError(t, err)
+	require.Empty(t, rsps[0].MempoolError)
+	require.Equal(t, 5, txmp.Size())
+}
+
+func TestTxMempool_RemoveTxByHash(t *testing.T) {
+	txmp := setup(t, 500)
+	removed := checkTxs(t, txmp, 20, 0)
+
+	// remove transactions while others are being checked
+	var wg sync.WaitGroup
+	var added []testTx
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		added = checkTxs(t, txmp, 100, 1)
+	}()
+	for _, tx := range removed {
+		require.NoError(t, txmp.RemoveTxByHash(tx.tx.Hash()))
+	}
+	wg.Wait()
+
+	require.Equal(t, len(added), txmp.Size())
+	var addedBytes int64
+	for _, tx := range added {
+		addedBytes += int64(len(tx.tx))
+	}
+	require.Equal(t, addedBytes, txmp.SizeBytes())
+	for _, tx := range removed {
+		_, ok := txmp.txByKey[tx.tx.Key()]
+		require.False(t, ok)
+		require.False(t, txmp.cache.Has(tx.tx))
+	}
+	// the ordered list only holds the remaining transactions
+	var listed int
+	for e := txmp.txs.Front(); e != nil; e = e.Next() {
+		_, ok := txmp.txByKey[e.Value.(*WrappedTx).tx.Key()]
+		require.True(t, ok)
+		listed++
+	}
+	require.Equal(t, len(added), listed)
+	require.Len(t, txmp.ReapMaxTxs(-1), len(added))
+
+	require.ErrorIs(t, txmp.RemoveTxByHash(removed[0].tx.Hash()), mempool.ErrTxNotFound)
+	require.Error(t, txmp.RemoveTxByHash([]byte("short")))
+}
+
+func TestTxMempool_RemoveTxByHashCustomHasher(t *testing.T) {
+	types.RegisterTxHasher(func(tx types.Tx) []byte { return append([]byte("custom-"), tx...) })
+	t.Cleanup(func() { types.RegisterTxHasher(nil) })
+
+	txmp := setup(t, 100)
+	txs := checkTxs(t, txmp, 3, 0)
+
+	// the hash reported by RPC is used, not the key of the tx
+	require.ErrorIs(t, txmp.RemoveTxByHash(txs[1].tx.Hash()), mempool.ErrTxNotFound)
+	require.NoError(t, txmp.RemoveTxByHash(types.TxHash(txs[1].tx)))
+	require.Equal(t, 2, txmp.Size())
+	_, ok := txmp.txByKey[txs[1].tx.Key()]
+	require.False(t, ok)
+	require.False(t, txmp.cache.Has(txs[1].tx))
+}
+
 func TestTxMempool_CheckTxSamePeer(t *testing.T) {
 	txmp := setup(t, 100)
 	peerID := uint16(1)
@@ -567,6 +905,128 @@ func TestTxMempool_ExpiredTxs_Timestamp(t *testing.T) {
 	}
 }
 
+func TestTxMempool_ExpiredTxs_CheckTx(t *testing.T) {
+	txmp := setup(t, 5000)
+	txmp.config.TTLDuration = time.Minute
+	now := time.Now()
+	txmp.now = func() time.Time { return now }
+
+	hasTx := func(tx string) bool {
+		_, ok := txmp.txByKey[types.Tx(tx).Key()]
+		return ok
+	}
+
+	mustCheckTx(t, txmp, "sender-0=key0=1")
+	now = now.Add(40 * time.Second)
+	mustCheckTx(t, txmp, "sender-1=key1=1")
+
+	// a transaction seen again through gossip keeps its arrival time
+	require.ErrorIs(t, txmp.CheckTx([]byte("sender-0=key0=1"), nil, mempool.TxInfo{SenderID: 1}), mempool.ErrTxInCache)
+
+	// the first transaction expires, and is evicted by the next CheckTx
+	// without waiting for the next block
+	now = now.Add(30 * time.Second)
+	require.True(t, hasTx("sender-0=key0=1"))
+	mustCheckTx(t, txmp, "sender-2=key2=1")
+	require.Equal(t, 2, txmp.Size())
+	require.False(t, hasTx("sender-0=key0=1"))
+	require.False(t, txmp.cache.Has([]byte("sender-0=key0=1")))
+
+	// it may then be admitted again, with a new arrival time
+	mustCheckTx(t, txmp, "sender-0=key0=1")
+	require.Equal(t, 3, txmp.Size())
+
+	now = now.Add(40 * time.Second)
+	require.NoError(t, txmp.CheckTxBatch(types.Txs{[]byte("sender-3=key3=1")}, nil, mempool.TxInfo{}))
+	require.Equal(t, 3, txmp.Size())
+	require.False(t, hasTx("sender-1=key1=1"))
+	require.True(t, hasTx("sender-0=key0=1"))
+	require.True(t, hasTx("sender-2=key2=1"))
+}
+
+func TestTxMempool_CheckTxConcurrency(t *testing.T) {
+	// distinct priorities, so that the reap order doesn't depend on arrival
+	txs := make(types.Txs, 200)
+	for i := range txs {
+		txs[i] = []byte(fmt.Sprintf("sender-%d=key%d=%d", i, i, 1000+(i*7919)%1000))
+	}
+	// after the block, the even priorities become invalid
+	var rejectEven int32
+	postCheck := func(tx types.Tx, rsp *abci.ResponseCheckTx) error {
+		if atomic.LoadInt32(&rejectEven) == 1 && rsp.Priority%2 == 0 {
+			return errors.New("even priority")
+		}
+		return nil
+	}
+
+	run := func(txmp *TxMempool, parallel bool) ([]types.Tx, []types.Tx) {
+		txmp.EnableTxsAvailable()
+		single, batch := txs[:150], txs[150:]
+		if parallel {
+			var wg sync.WaitGroup
+			for w := 0; w < 8; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := w; i < len(single); i += 8 {
+						require.NoError(t, txmp.CheckTx(single[i], nil, mempool.TxInfo{}))
+					}
+				}(w)
+			}
+			wg.Wait()
+		} else {
+			for _, tx := range single {
+				require.NoError(t, txmp.CheckTx(tx, nil, mempool.TxInfo{}))
+			}
+		}
+		require.NoError(t, txmp.CheckTxBatch(batch, nil, mempool.TxInfo{}))
+		<-txmp.TxsAvailable()
+
+		// the batch is inserted in its order, after the single txs
+		var listed []types.Tx
+		for e := txmp.txs.Front(); e != nil; e = e.Next() {
+			listed = append(listed, e.Value.(*WrappedTx).tx)
+		}
+		require.Len(t, listed, len(txs))
+		require.Equal(t, []types.Tx(batch), listed[len(single):])
+		// and the timestamps agree with the order of the list
+		for e := txmp.txs.Front(); e != nil && e.Next() != nil; e = e.Next() {
+			require.False(t, e.Next().Value.(*WrappedTx).timestamp.Before(e.Value.(*WrappedTx).timestamp))
+		}
+		reaped := txmp.ReapMaxTxs(-1)
+
+		// recheck the remaining txs after a block
+		atomic.StoreInt32(&rejectEven, 1)
+		defer atomic.StoreInt32(&rejectEven, 0)
+		responses := make([]*abci.ResponseDeliverTx, 10)
+		for i := range responses {
+			responses[i] = &abci.ResponseDeliverTx{Code: abci.CodeTypeOK}
+		}
+		txmp.Lock()
+		require.NoError(t, txmp.Update(1, reaped[:10], responses, nil, nil))
+		txmp.Unlock()
+		select {
+		case <-txmp.TxsAvailable():
+		case <-time.After(10 * time.Second):
+			t.Fatal("recheck did not complete")
+		}
+		return reaped, txmp.ReapMaxTxs(-1)
+	}
+
+	serial := setup(t, 1000, WithPostCheck(postCheck))
+	serial.config.Recheck = true
+	serialReaped, serialRechecked := run(serial, false)
+
+	concurrent := setupWithConcurrency(t, 1000, 8, WithPostCheck(postCheck))
+	concurrent.config.Recheck = true
+	reaped, rechecked := run(concurrent, true)
+
+	require.Equal(t, serialReaped, reaped)
+	require.Equal(t, serialRechecked, rechecked)
+	require.NotEmpty(t, rechecked)
+	require.Less(t, len(rechecked), len(txs)-10)
+}
+
 func TestTxMempool_ExpiredTxs_NumBlocks(t *testing.T) {
 	txmp := setup(t, 500)
 	txmp.height = 100
@@ -652,3 +1112,28 @@ func TestTxMempool_CheckTxPostCheckError(t *testing.T) {
 		})
 	}
 }
+
+func TestWrappedTx_MarkGossiped(t *testing.T) {
+	wtx := &WrappedTx{tx: types.Tx("tx"), timestamp: time.Now()}
+	require.True(t, wtx.MarkGossiped())
+	require.False(t, wtx.MarkGossiped())
+}
+
+func TestTxMempool_PendingTxs(t *testing.T) {
+	txmp := setup(t, 0)
+	txs := checkTxs(t, txmp, 20, 0)
+
+	priorities := make(map[string]int64, len(txs))
+	for _, tx := range txs {
+		priorities[string(tx.tx)] = tx.priority
+	}
+
+	pending := txmp.PendingTxs()
+	require.Len(t, pending, len(txs))
+	for i, tx := range pending {
+		require.Equal(t, priorities[string(tx.Tx)], tx.Priority)
+		if i > 0 {
+			require.False(t, tx.Time.Before(pending[i-1].Time), "txs must be sorted by age")
+		}
+	}
+}
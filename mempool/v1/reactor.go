@@ -270,6 +270,11 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 				time.Sleep(mempool.PeerCatchupSleepIntervalMS * time.Millisecond)
 				continue
 			}
+			if memTx.MarkGossiped() {
+				elapsed := time.Since(memTx.timestamp)
+				memR.mempool.metrics.TimeToFirstGossip.Observe(elapsed.Seconds())
+				memR.Logger.Debug("first gossip of tx", "tx", memTx.hash, "peer", peer.ID(), "elapsed", elapsed)
+			}
 		}
 
 		select {
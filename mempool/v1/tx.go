@@ -20,6 +20,7 @@ type WrappedTx struct {
 	priority  int64           // app: priority value for this transaction
 	sender    string          // app: assigned sender label
 	peers     map[uint16]bool // peer IDs who have sent us this transaction
+	gossiped  bool            // whether this transaction has been forwarded to a peer
 }
 
 // Size reports the size of the raw transaction in bytes.
@@ -44,6 +45,16 @@ func (w *WrappedTx) HasPeer(id uint16) bool {
 	return ok
 }
 
+// MarkGossiped records that w has been forwarded to a peer, and reports whether
+// it is the first time.
+func (w *WrappedTx) MarkGossiped() bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	first := !w.gossiped
+	w.gossiped = true
+	return first
+}
+
 // SetGasWanted sets the application-assigned gas requirement of w.
 func (w *WrappedTx) SetGasWanted(gas int64) {
 	w.mtx.Lock()
@@ -114,20 +114,21 @@ func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
 	)
 }
 
-// MetricsProvider returns a consensus, p2p and mempool Metrics.
-type MetricsProvider func(chainID string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics)
+// MetricsProvider returns a consensus, p2p, mempool, state and rpc Metrics.
+type MetricsProvider func(chainID string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics, *rpccore.Metrics)
 
 // DefaultMetricsProvider returns Metrics build using Prometheus client library
 // if Prometheus is enabled. Otherwise, it returns no-op Metrics.
 func DefaultMetricsProvider(config *cfg.InstrumentationConfig) MetricsProvider {
-	return func(chainID string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics) {
+	return func(chainID string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics, *rpccore.Metrics) {
 		if config.Prometheus {
 			return cs.PrometheusMetrics(config.Namespace, "chain_id", chainID),
 				p2p.PrometheusMetrics(config.Namespace, "chain_id", chainID),
 				mempl.PrometheusMetrics(config.Namespace, "chain_id", chainID),
-				sm.PrometheusMetrics(config.Namespace, "chain_id", chainID)
+				sm.PrometheusMetrics(config.Namespace, "chain_id", chainID),
+				rpccore.PrometheusMetrics(config.Namespace, "chain_id", chainID)
 		}
-		return cs.NopMetrics(), p2p.NopMetrics(), mempl.NopMetrics(), sm.NopMetrics()
+		return cs.NopMetrics(), p2p.NopMetrics(), mempl.NopMetrics(), sm.NopMetrics(), rpccore.NopMetrics()
 	}
 }
 
@@ -189,6 +190,36 @@ func StateProvider(stateProvider statesync.StateProvider) Option {
 	}
 }
 
+// MempoolTxFilter sets a filter run by the mempool on every transaction before
+// it is checked by the application. Transactions it rejects never reach the
+// application. See mempool.TxFilter.
+func MempoolTxFilter(f mempl.TxFilter) Option {
+	return func(n *Node) {
+		switch mp := n.mempool.(type) {
+		case *mempoolv0.CListMempool:
+			mempoolv0.WithTxFilter(f)(mp)
+		case *mempoolv1.TxMempool:
+			mempoolv1.WithTxFilter(f)(mp)
+		default:
+			n.Logger.Error("Mempool does not support tx filters", "mempool", fmt.Sprintf("%T", mp))
+		}
+	}
+}
+
+// MempoolSenderFunc sets the function returning the sender of a transaction,
+// used by the v1 mempool in place of the sender assigned by the application in
+// CheckTx. See mempool.SenderFunc.
+func MempoolSenderFunc(f mempl.SenderFunc) Option {
+	return func(n *Node) {
+		switch mp := n.mempool.(type) {
+		case *mempoolv1.TxMempool:
+			mempoolv1.WithSenderFunc(f)(mp)
+		default:
+			n.Logger.Error("Mempool does not support sender functions", "mempool", fmt.Sprintf("%T", mp))
+		}
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Node is the highest level interface to a full Tendermint node.
@@ -226,6 +257,7 @@ type Node struct {
 	evidencePool      *evidence.Pool          // tracking evidence
 	proxyApp          proxy.AppConns          // connection to the application
 	rpcListeners      []net.Listener          // rpc servers
+	rpcMetrics        *rpccore.Metrics
 	txIndexer         txindex.TxIndexer
 	blockIndexer      indexer.BlockIndexer
 	indexerService    *txindex.IndexerService
@@ -728,6 +760,12 @@ func NewNode(config *cfg.Config,
 	}
 
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
+	if config.Mempool.CheckTxConcurrency > 1 {
+		logger.Info("Allowing concurrent CheckTx calls to in-process apps",
+			"concurrency", config.Mempool.CheckTxConcurrency)
+		clientCreator = proxy.WithCheckTxConcurrency(clientCreator, config.Mempool.CheckTxConcurrency)
+	}
+
 	proxyApp, err := createAndStartProxyAppConns(clientCreator, logger)
 	if err != nil {
 		return nil, err
@@ -793,7 +831,7 @@ func NewNode(config *cfg.Config,
 
 	logNodeStartupInfo(state, pubKey, logger, consensusLogger)
 
-	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID)
+	csMetrics, p2pMetrics, memplMetrics, smMetrics, rpcMetrics := metricsProvider(genDoc.ChainID)
 
 	// Make MempoolReactor
 	mempool, mempoolReactor := createMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, logger)
@@ -927,6 +965,7 @@ func NewNode(config *cfg.Config,
 		indexerService:   indexerService,
 		blockIndexer:     blockIndexer,
 		eventBus:         eventBus,
+		rpcMetrics:       rpcMetrics,
 	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
 
@@ -1087,11 +1126,13 @@ func (n *Node) ConfigureRPC() error {
 
 		Logger: n.Logger.With("module", "rpc"),
 
-		Config: *n.config.RPC,
+		Config:  *n.config.RPC,
+		Metrics: n.rpcMetrics,
 	})
 	if err := rpccore.InitGenesisChunks(); err != nil {
 		return err
 	}
+	rpccore.InitTxSearchCache()
 
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
 	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/evidence"
@@ -447,6 +448,82 @@ func TestNodeNewNodeCustomReactors(t *testing.T) {
 	assert.Contains(t, channels, cr.Channels[0].ID)
 }
 
+func TestNodeMempoolSenderFunc(t *testing.T) {
+	config := cfg.ResetTestRoot("node_mempool_sender_func_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Mempool.Version = cfg.MempoolV1
+	config.Mempool.ReapMode = cfg.ReapModeRoundRobin
+	config.Mempool.ReapMaxTxsPerSender = 1
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	// the kvstore app assigns no sender, the sender is the key of the tx
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.NewLocalClientCreator(kvstore.NewApplication()),
+		DefaultGenesisDocProviderFunc(config),
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		MempoolSenderFunc(func(tx types.Tx) string { return string(tx[:1]) }),
+	)
+	require.NoError(t, err)
+
+	for _, tx := range []types.Tx{types.Tx("a=1"), types.Tx("a=2"), types.Tx("b=1")} {
+		require.NoError(t, n.Mempool().CheckTx(tx, nil, mempl.TxInfo{}))
+	}
+	require.Equal(t, 3, n.Mempool().Size())
+
+	// a single tx of each sender is reaped
+	txs := n.Mempool().ReapMaxBytesMaxGas(-1, -1)
+	require.Len(t, txs, 2)
+	assert.NotEqual(t, txs[0][:1], txs[1][:1])
+}
+
+func TestNodeMempoolSenderQuota(t *testing.T) {
+	config := cfg.ResetTestRoot("node_mempool_sender_quota_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Mempool.Version = cfg.MempoolV1
+	config.Mempool.MaxTxBytesPerSender = 7
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.NewLocalClientCreator(kvstore.NewApplication()),
+		DefaultGenesisDocProviderFunc(config),
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		MempoolSenderFunc(func(tx types.Tx) string { return string(tx[:1]) }),
+	)
+	require.NoError(t, err)
+
+	checkTx := func(tx types.Tx) string {
+		var rsp *abci.ResponseCheckTx
+		require.NoError(t, n.Mempool().CheckTx(tx, func(res *abci.Response) {
+			rsp = res.GetCheckTx()
+		}, mempl.TxInfo{}))
+		return rsp.MempoolError
+	}
+
+	// the txs of a single sender are 3 bytes long, the third exceeds the quota
+	require.Empty(t, checkTx(types.Tx("a=1")))
+	require.Empty(t, checkTx(types.Tx("a=2")))
+	require.Equal(t, mempl.ErrSenderQuotaExceeded{
+		Sender:      "a",
+		SenderBytes: 6,
+		TxBytes:     3,
+		MaxBytes:    7,
+	}.Error(), checkTx(types.Tx("a=3")))
+	require.Empty(t, checkTx(types.Tx("b=1")))
+	require.Equal(t, 3, n.Mempool().Size())
+}
+
 func state(nVals int, height int64) (sm.State, dbm.DB, []types.PrivValidator) {
 	privVals := make([]types.PrivValidator, nVals)
 	vals := make([]types.GenesisValidator, nVals)
@@ -92,6 +92,30 @@ func TestAddrBookSaveLoad(t *testing.T) {
 	assert.Equal(t, 100, book.Size())
 }
 
+func TestReadAddrBookFile(t *testing.T) {
+	book, fname := createAddrBookWithMOldAndNNewAddrs(t, 3, 5)
+	defer deleteTempFile(fname)
+	book.Save()
+
+	entries, err := ReadAddrBookFile(fname)
+	require.NoError(t, err)
+	require.Len(t, entries, 8)
+
+	good := 0
+	for _, e := range entries {
+		assert.True(t, book.HasAddress(e.Addr), e.Addr.String())
+		assert.Equal(t, book.IsGood(e.Addr), e.Good, e.Addr.String())
+		if e.Good {
+			good++
+			assert.False(t, e.LastSuccess.IsZero())
+		}
+	}
+	assert.Equal(t, 3, good)
+
+	_, err = ReadAddrBookFile(fname + ".missing")
+	require.Error(t, err)
+}
+
 func TestAddrBookLookup(t *testing.T) {
 	fname := createTempFileName("addrbook_test")
 	defer deleteTempFile(fname)
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/tendermint/tendermint/libs/tempfile"
+	"github.com/tendermint/tendermint/p2p"
 )
 
 /* Loading & Saving */
@@ -81,3 +83,45 @@ func (a *addrBook) loadFromFile(filePath string) bool {
 	}
 	return true
 }
+
+// AddrBookEntry is an address stored in an address book file. Banned
+// addresses are not stored.
+type AddrBookEntry struct {
+	Addr *p2p.NetAddress
+	// Good is true if the address is in the old buckets, i.e. if a peer has
+	// successfully been connected to at this address.
+	Good        bool
+	Attempts    int32
+	LastAttempt time.Time
+	LastSuccess time.Time
+}
+
+// ReadAddrBookFile returns the addresses stored in the address book file,
+// without loading the address book. Unlike the address book, it returns an
+// error if the file doesn't exist or is corrupt, so that it can be used by
+// tools while the node is stopped.
+func ReadAddrBookFile(filePath string) ([]AddrBookEntry, error) {
+	bz, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	aJSON := &addrBookJSON{}
+	if err := json.Unmarshal(bz, aJSON); err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", filePath, err)
+	}
+
+	entries := make([]AddrBookEntry, 0, len(aJSON.Addrs))
+	for _, ka := range aJSON.Addrs {
+		if ka == nil || ka.Addr == nil {
+			continue
+		}
+		entries = append(entries, AddrBookEntry{
+			Addr:        ka.Addr,
+			Good:        ka.isOld(),
+			Attempts:    ka.Attempts,
+			LastAttempt: ka.LastAttempt,
+			LastSuccess: ka.LastSuccess,
+		})
+	}
+	return entries, nil
+}
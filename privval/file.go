@@ -138,6 +138,73 @@ func (lss *FilePVLastSignState) Save() {
 	}
 }
 
+// CheckNotBehind returns an error if lss is behind other, i.e. if replacing
+// other with lss would allow the validator to sign again for an already signed
+// height, round and step (HRS). Replacing a state with one at the same HRS is
+// only allowed if both carry the same SignBytes.
+func (lss *FilePVLastSignState) CheckNotBehind(other *FilePVLastSignState) error {
+	switch {
+	case lss.Height < other.Height:
+		return fmt.Errorf("height regression. Got %v, last height %v", lss.Height, other.Height)
+	case lss.Height > other.Height:
+		return nil
+	case lss.Round < other.Round:
+		return fmt.Errorf("round regression at height %v. Got %v, last round %v", lss.Height, lss.Round, other.Round)
+	case lss.Round > other.Round:
+		return nil
+	case lss.Step < other.Step:
+		return fmt.Errorf(
+			"step regression at height %v round %v. Got %v, last step %v",
+			lss.Height,
+			lss.Round,
+			lss.Step,
+			other.Step,
+		)
+	case lss.Step > other.Step:
+		return nil
+	case !bytes.Equal(lss.SignBytes, other.SignBytes):
+		return fmt.Errorf("conflicting SignBytes at height %v round %v step %v", lss.Height, lss.Round, lss.Step)
+	}
+	return nil
+}
+
+// LoadFilePVLastSignState loads the FilePVLastSignState stored at filePath.
+// Unlike LoadFilePV, it returns an error instead of exiting.
+func LoadFilePVLastSignState(filePath string) (*FilePVLastSignState, error) {
+	stateJSONBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	lss := &FilePVLastSignState{}
+	if err := tmjson.Unmarshal(stateJSONBytes, lss); err != nil {
+		return nil, fmt.Errorf("error reading PrivValidator state from %v: %w", filePath, err)
+	}
+	lss.filePath = filePath
+	return lss, nil
+}
+
+// ImportFilePVLastSignState atomically replaces the FilePVLastSignState stored
+// at filePath with lss. It fails if the stored state is ahead of lss (see
+// CheckNotBehind), so importing a state never lowers the last signed HRS. If
+// there is no state stored at filePath yet, lss is written as is.
+func ImportFilePVLastSignState(filePath string, lss FilePVLastSignState) error {
+	current, err := LoadFilePVLastSignState(filePath)
+	switch {
+	case err == nil:
+		if err := lss.CheckNotBehind(current); err != nil {
+			return fmt.Errorf("refusing to import validator state: %w", err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	jsonBytes, err := tmjson.MarshalIndent(lss, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(filePath, jsonBytes, 0o600)
+}
+
 //-------------------------------------------------------------------------------
 
 // FilePV implements PrivValidator using data persisted to disk
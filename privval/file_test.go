@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -116,6 +117,49 @@ func TestUnmarshalValidatorState(t *testing.T) {
 	assert.JSONEq(serialized, string(out))
 }
 
+func TestImportValidatorState(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "priv_validator_state.json")
+
+	// importing into an empty location writes the state as is
+	err := ImportFilePVLastSignState(stateFilePath, FilePVLastSignState{Height: 10, Round: 1, Step: stepPrevote})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		state  FilePVLastSignState
+		expErr bool
+	}{
+		{"lower height", FilePVLastSignState{Height: 9, Round: 5, Step: stepPrecommit}, true},
+		{"lower round", FilePVLastSignState{Height: 10, Round: 0, Step: stepPrecommit}, true},
+		{"lower step", FilePVLastSignState{Height: 10, Round: 1, Step: stepPropose}, true},
+		{"conflicting sign bytes", FilePVLastSignState{Height: 10, Round: 1, Step: stepPrevote, SignBytes: []byte{1}}, true},
+		{"same state", FilePVLastSignState{Height: 10, Round: 1, Step: stepPrevote}, false},
+		{"higher step", FilePVLastSignState{Height: 10, Round: 1, Step: stepPrecommit}, false},
+		{"higher height", FilePVLastSignState{Height: 11, Round: 0, Step: stepNone}, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			before, err := LoadFilePVLastSignState(stateFilePath)
+			require.NoError(t, err)
+
+			err = ImportFilePVLastSignState(stateFilePath, tc.state)
+			after, lerr := LoadFilePVLastSignState(stateFilePath)
+			require.NoError(t, lerr)
+			if tc.expErr {
+				require.Error(t, err)
+				assert.Equal(t, before, after, "state must be left untouched")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.state.Height, after.Height)
+			assert.Equal(t, tc.state.Round, after.Round)
+			assert.Equal(t, tc.state.Step, after.Step)
+		})
+	}
+}
+
 func TestUnmarshalValidatorKey(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 
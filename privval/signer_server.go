@@ -68,6 +68,9 @@ func (ss *SignerServer) servicePendingRequest() {
 		if err != io.EOF {
 			ss.Logger.Error("SignerServer: HandleMessage", "err", err)
 		}
+		// the connection is unusable (e.g. closed by the listener), so drop it
+		// for the service loop to dial a new one
+		ss.endpoint.DropConnection()
 		return
 	}
 
@@ -40,6 +40,42 @@ func (l *localClientCreator) NewABCIClient() (abcicli.Client, error) {
 	return abcicli.NewLocalClient(l.mtx, l.app), nil
 }
 
+// concurrent local proxy lets CheckTx calls on an in-proc app run
+// concurrently, while serializing them with every other call
+
+type concurrentCheckTxLocalClientCreator struct {
+	mtx         *tmsync.RWMutex
+	app         types.Application
+	concurrency int
+}
+
+// NewConcurrentCheckTxLocalClientCreator returns a ClientCreator for the given
+// app, which will be running locally, allowing up to concurrency CheckTx calls
+// to run at the same time. See abcicli.NewConcurrentCheckTxLocalClient.
+func NewConcurrentCheckTxLocalClientCreator(app types.Application, concurrency int) ClientCreator {
+	return &concurrentCheckTxLocalClientCreator{
+		mtx:         new(tmsync.RWMutex),
+		app:         app,
+		concurrency: concurrency,
+	}
+}
+
+func (l *concurrentCheckTxLocalClientCreator) NewABCIClient() (abcicli.Client, error) {
+	return abcicli.NewConcurrentCheckTxLocalClient(l.mtx, l.app, l.concurrency), nil
+}
+
+// WithCheckTxConcurrency returns a ClientCreator allowing up to concurrency
+// CheckTx calls to run at the same time, if cc was returned by
+// NewLocalClientCreator and concurrency is greater than 1. Otherwise, cc is
+// returned unchanged: out-of-process apps control their own concurrency.
+func WithCheckTxConcurrency(cc ClientCreator, concurrency int) ClientCreator {
+	l, ok := cc.(*localClientCreator)
+	if !ok || concurrency <= 1 {
+		return cc
+	}
+	return NewConcurrentCheckTxLocalClientCreator(l.app, concurrency)
+}
+
 //---------------------------------------------------------------
 // remote proxy opens new connections to an external app process
 
@@ -309,6 +309,25 @@ func (c *baseRPCClient) NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUn
 	return result, nil
 }
 
+func (c *baseRPCClient) UnconfirmedTxsByAge(
+	ctx context.Context,
+	olderThan int64,
+	limit *int,
+) (*ctypes.ResultUnconfirmedTxsByAge, error) {
+	result := new(ctypes.ResultUnconfirmedTxsByAge)
+	params := map[string]interface{}{
+		"older_than": olderThan,
+	}
+	if limit != nil {
+		params["limit"] = limit
+	}
+	_, err := c.caller.Call(ctx, "unconfirmed_txs_by_age", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.ResultCheckTx, error) {
 	result := new(ctypes.ResultCheckTx)
 	_, err := c.caller.Call(ctx, "check_tx", map[string]interface{}{"tx": tx}, result)
@@ -370,6 +389,15 @@ func (c *baseRPCClient) Health(ctx context.Context) (*ctypes.ResultHealth, error
 	return result, nil
 }
 
+func (c *baseRPCClient) RPCLimits(ctx context.Context) (*ctypes.ResultRPCLimits, error) {
+	result := new(ctypes.ResultRPCLimits)
+	_, err := c.caller.Call(ctx, "rpc_limits", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) BlockchainInfo(
 	ctx context.Context,
 	minHeight,
@@ -416,6 +444,19 @@ func (c *baseRPCClient) Block(ctx context.Context, height *int64) (*ctypes.Resul
 	return result, nil
 }
 
+func (c *baseRPCClient) RawBlock(ctx context.Context, height *int64) (*ctypes.ResultRawBlock, error) {
+	result := new(ctypes.ResultRawBlock)
+	params := make(map[string]interface{})
+	if height != nil {
+		params["height"] = height
+	}
+	_, err := c.caller.Call(ctx, "raw_block", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) BlockByHash(ctx context.Context, hash []byte) (*ctypes.ResultBlock, error) {
 	result := new(ctypes.ResultBlock)
 	params := map[string]interface{}{
@@ -470,6 +511,115 @@ func (c *baseRPCClient) Tx(ctx context.Context, hash []byte, prove bool) (*ctype
 	return result, nil
 }
 
+func (c *baseRPCClient) TxByHeightIndex(
+	ctx context.Context,
+	height int64,
+	index uint32,
+	prove bool,
+) (*ctypes.ResultTx, error) {
+	result := new(ctypes.ResultTx)
+	params := map[string]interface{}{
+		"height": height,
+		"index":  index,
+		"prove":  prove,
+	}
+	_, err := c.caller.Call(ctx, "tx_by_height_index", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) TxAll(
+	ctx context.Context,
+	hash []byte,
+	page,
+	perPage *int,
+) (*ctypes.ResultTxSearch, error) {
+	result := new(ctypes.ResultTxSearch)
+	params := map[string]interface{}{
+		"hash": hash,
+	}
+	if page != nil {
+		params["page"] = page
+	}
+	if perPage != nil {
+		params["per_page"] = perPage
+	}
+
+	_, err := c.caller.Call(ctx, "tx_all", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) TxCountByHeight(
+	ctx context.Context,
+	minHeight,
+	maxHeight int64,
+) (*ctypes.ResultTxCountByHeight, error) {
+	result := new(ctypes.ResultTxCountByHeight)
+	params := map[string]interface{}{
+		"min_height": minHeight,
+		"max_height": maxHeight,
+	}
+	_, err := c.caller.Call(ctx, "tx_count_by_height", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) TxSearchByHashes(
+	ctx context.Context,
+	hashes [][]byte,
+	prove bool,
+	page,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	result := new(ctypes.ResultTxSearch)
+	hexHashes := make([]bytes.HexBytes, len(hashes))
+	for i, hash := range hashes {
+		hexHashes[i] = hash
+	}
+	params := map[string]interface{}{
+		"hashes":   hexHashes,
+		"prove":    prove,
+		"order_by": orderBy,
+	}
+	if page != nil {
+		params["page"] = page
+	}
+	if perPage != nil {
+		params["per_page"] = perPage
+	}
+
+	_, err := c.caller.Call(ctx, "tx_search_by_hashes", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) TxBatch(ctx context.Context, hashes [][]byte, prove bool) (*ctypes.ResultTxBatch, error) {
+	result := new(ctypes.ResultTxBatch)
+	hexHashes := make([]bytes.HexBytes, len(hashes))
+	for i, hash := range hashes {
+		hexHashes[i] = hash
+	}
+	params := map[string]interface{}{
+		"hashes": hexHashes,
+		"prove":  prove,
+	}
+	_, err := c.caller.Call(ctx, "tx_batch", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) TxSearch(
 	ctx context.Context,
 	query string,
@@ -529,6 +679,15 @@ func (c *baseRPCClient) BlockSearch(
 	return result, nil
 }
 
+func (c *baseRPCClient) MinIndexedHeight(ctx context.Context) (*ctypes.ResultMinIndexedHeight, error) {
+	result := new(ctypes.ResultMinIndexedHeight)
+	_, err := c.caller.Call(ctx, "min_indexed_height", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) Validators(
 	ctx context.Context,
 	height *int64,
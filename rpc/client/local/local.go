@@ -107,6 +107,14 @@ func (c *Local) NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUnconfirme
 	return core.NumUnconfirmedTxs(c.ctx)
 }
 
+func (c *Local) UnconfirmedTxsByAge(
+	ctx context.Context,
+	olderThan int64,
+	limit *int,
+) (*ctypes.ResultUnconfirmedTxsByAge, error) {
+	return core.UnconfirmedTxsByAge(c.ctx, olderThan, limit)
+}
+
 func (c *Local) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.ResultCheckTx, error) {
 	return core.CheckTx(c.ctx, tx)
 }
@@ -131,6 +139,10 @@ func (c *Local) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
 	return core.Health(c.ctx)
 }
 
+func (c *Local) RPCLimits(ctx context.Context) (*ctypes.ResultRPCLimits, error) {
+	return core.RPCLimits(c.ctx)
+}
+
 func (c *Local) DialSeeds(ctx context.Context, seeds []string) (*ctypes.ResultDialSeeds, error) {
 	return core.UnsafeDialSeeds(c.ctx, seeds)
 }
@@ -161,6 +173,10 @@ func (c *Local) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock,
 	return core.Block(c.ctx, height)
 }
 
+func (c *Local) RawBlock(ctx context.Context, height *int64) (*ctypes.ResultRawBlock, error) {
+	return core.RawBlock(c.ctx, height)
+}
+
 func (c *Local) BlockByHash(ctx context.Context, hash []byte) (*ctypes.ResultBlock, error) {
 	return core.BlockByHash(c.ctx, hash)
 }
@@ -181,6 +197,41 @@ func (c *Local) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.Result
 	return core.Tx(c.ctx, hash, prove)
 }
 
+func (c *Local) TxByHeightIndex(ctx context.Context, height int64, index uint32, prove bool) (*ctypes.ResultTx, error) {
+	return core.TxByHeightIndex(c.ctx, height, index, prove)
+}
+
+func (c *Local) TxAll(ctx context.Context, hash []byte, page, perPage *int) (*ctypes.ResultTxSearch, error) {
+	return core.TxAll(c.ctx, hash, page, perPage)
+}
+
+func (c *Local) TxCountByHeight(_ context.Context, minHeight, maxHeight int64) (*ctypes.ResultTxCountByHeight, error) {
+	return core.TxCountByHeight(c.ctx, minHeight, maxHeight)
+}
+
+func (c *Local) TxSearchByHashes(
+	_ context.Context,
+	hashes [][]byte,
+	prove bool,
+	page,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	hexHashes := make([]bytes.HexBytes, len(hashes))
+	for i, hash := range hashes {
+		hexHashes[i] = hash
+	}
+	return core.TxSearchByHashes(c.ctx, hexHashes, prove, page, perPage, orderBy)
+}
+
+func (c *Local) TxBatch(_ context.Context, hashes [][]byte, prove bool) (*ctypes.ResultTxBatch, error) {
+	hexHashes := make([]bytes.HexBytes, len(hashes))
+	for i, hash := range hashes {
+		hexHashes[i] = hash
+	}
+	return core.TxBatch(c.ctx, hexHashes, prove)
+}
+
 func (c *Local) TxSearch(
 	_ context.Context,
 	query string,
@@ -189,7 +240,7 @@ func (c *Local) TxSearch(
 	perPage *int,
 	orderBy string,
 ) (*ctypes.ResultTxSearch, error) {
-	return core.TxSearch(c.ctx, query, prove, page, perPage, orderBy)
+	return core.TxSearch(c.ctx, query, prove, page, perPage, orderBy, false, "", false, "", false, 0, 0, "")
 }
 
 func (c *Local) BlockSearch(
@@ -201,6 +252,10 @@ func (c *Local) BlockSearch(
 	return core.BlockSearch(c.ctx, query, page, perPage, orderBy)
 }
 
+func (c *Local) MinIndexedHeight(ctx context.Context) (*ctypes.ResultMinIndexedHeight, error) {
+	return core.MinIndexedHeight(c.ctx)
+}
+
 func (c *Local) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*ctypes.ResultBroadcastEvidence, error) {
 	return core.BroadcastEvidence(c.ctx, ev)
 }
@@ -71,7 +71,7 @@ func (a ABCIApp) BroadcastTxAsync(ctx context.Context, tx types.Tx) (*ctypes.Res
 		Data:      c.Data,
 		Log:       c.Log,
 		Codespace: c.Codespace,
-		Hash:      tx.Hash(),
+		Hash:      types.TxHash(tx),
 	}, nil
 }
 
@@ -86,7 +86,7 @@ func (a ABCIApp) BroadcastTxSync(ctx context.Context, tx types.Tx) (*ctypes.Resu
 		Data:      c.Data,
 		Log:       c.Log,
 		Codespace: c.Codespace,
-		Hash:      tx.Hash(),
+		Hash:      types.TxHash(tx),
 	}, nil
 }
 
@@ -163,6 +163,26 @@ func TestHealth(t *testing.T) {
 	}
 }
 
+func TestRPCLimits(t *testing.T) {
+	clients := []interface {
+		RPCLimits(context.Context) (*ctypes.ResultRPCLimits, error)
+	}{getHTTPClient(), getLocalClient()}
+
+	rpcConfig := rpctest.GetConfig().RPC
+	for i, c := range clients {
+		limits, err := c.RPCLimits(context.Background())
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, 30, limits.DefaultPerPage)
+		assert.Equal(t, 100, limits.MaxPerPage)
+		assert.Equal(t, 512, limits.MaxQueryLength)
+		assert.Equal(t, 10000, limits.MaxTxSearchStreamResults)
+		assert.Equal(t, 100, limits.MaxTxBatchHashes)
+		assert.Equal(t, 10000, limits.MaxTxCountHeights)
+		assert.Equal(t, rpcConfig.MaxBodyBytes, limits.MaxBodyBytes)
+		assert.Equal(t, rpcConfig.MaxSubscriptionsPerClient, limits.MaxSubscriptionsPerClient)
+	}
+}
+
 func TestGenesisAndValidators(t *testing.T) {
 	for i, c := range GetClients() {
 
@@ -630,6 +650,99 @@ func TestTxSearch(t *testing.T) {
 	}
 }
 
+func TestTxByHeightIndex(t *testing.T) {
+	c := getHTTPClient()
+
+	_, _, tx := MakeTxKV()
+	bres, err := c.BroadcastTxCommit(context.Background(), tx)
+	require.NoError(t, err)
+	res, err := c.Tx(context.Background(), bres.Hash, false)
+	require.NoError(t, err)
+
+	clients := []interface {
+		TxByHeightIndex(context.Context, int64, uint32, bool) (*ctypes.ResultTx, error)
+	}{getHTTPClient(), getLocalClient()}
+
+	for i, c := range clients {
+		t.Logf("client %d", i)
+
+		ptx, err := c.TxByHeightIndex(context.Background(), res.Height, res.Index, true)
+		require.NoError(t, err)
+		assert.EqualValues(t, bres.Hash, ptx.Hash)
+		assert.EqualValues(t, tx, ptx.Tx)
+		assert.Equal(t, res.Height, ptx.Height)
+		assert.Equal(t, res.Index, ptx.Index)
+		assert.True(t, ptx.TxResult.IsOK())
+		if assert.EqualValues(t, tx, ptx.Proof.Data) {
+			assert.NoError(t, ptx.Proof.Proof.Verify(ptx.Proof.RootHash, ptx.Hash))
+		}
+
+		ptx, err = c.TxByHeightIndex(context.Background(), res.Height, res.Index, false)
+		require.NoError(t, err)
+		assert.Empty(t, ptx.Proof.Data)
+
+		// out of range index
+		_, err = c.TxByHeightIndex(context.Background(), res.Height, res.Index+1, false)
+		assert.Error(t, err)
+
+		// future height
+		_, err = c.TxByHeightIndex(context.Background(), res.Height+1000, 0, false)
+		assert.Error(t, err)
+	}
+}
+
+func TestTxSearchByHashes(t *testing.T) {
+	c := getHTTPClient()
+
+	// first we broadcast a few txs
+	var hashes [][]byte
+	for i := 0; i < 3; i++ {
+		_, _, tx := MakeTxKV()
+		_, err := c.BroadcastTxCommit(context.Background(), tx)
+		require.NoError(t, err)
+		hashes = append(hashes, types.Tx(tx).Hash())
+	}
+	missingHash := types.Tx("a different tx").Hash()
+
+	clients := []interface {
+		TxSearchByHashes(context.Context, [][]byte, bool, *int, *int, string) (*ctypes.ResultTxSearch, error)
+	}{getHTTPClient(), getLocalClient()}
+
+	for i, c := range clients {
+		t.Logf("client %d", i)
+
+		// missing and duplicated hashes are left out of the results
+		query := [][]byte{hashes[2], missingHash, hashes[0], hashes[1], hashes[0]}
+		result, err := c.TxSearchByHashes(context.Background(), query, true, nil, nil, "asc")
+		require.NoError(t, err)
+		require.Equal(t, 3, result.TotalCount)
+		require.Len(t, result.Txs, 3)
+		for k, ptx := range result.Txs {
+			assert.EqualValues(t, hashes[k], ptx.Hash)
+			assert.True(t, ptx.TxResult.IsOK())
+			if assert.EqualValues(t, ptx.Tx, ptx.Proof.Data) {
+				assert.NoError(t, ptx.Proof.Proof.Verify(ptx.Proof.RootHash, ptx.Hash))
+			}
+		}
+
+		// check sorting and pagination
+		page, perPage := 1, 2
+		result, err = c.TxSearchByHashes(context.Background(), query, false, &page, &perPage, "desc")
+		require.NoError(t, err)
+		require.Equal(t, 3, result.TotalCount)
+		require.Len(t, result.Txs, 2)
+		assert.EqualValues(t, hashes[2], result.Txs[0].Hash)
+		assert.EqualValues(t, hashes[1], result.Txs[1].Hash)
+		assert.Empty(t, result.Txs[0].Proof.Data)
+
+		// only missing hashes
+		result, err = c.TxSearchByHashes(context.Background(), [][]byte{missingHash}, false, nil, nil, "")
+		require.NoError(t, err)
+		require.Zero(t, result.TotalCount)
+		require.Empty(t, result.Txs)
+	}
+}
+
 func TestBatchedJSONRPCCalls(t *testing.T) {
 	c := getHTTPClient()
 	testBatchedJSONRPCCalls(t, c)
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"container/list"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// maxBlockCacheBytes bounds the total size of the blocks cached by a
+// blockCache.
+const maxBlockCacheBytes = 64 * 1024 * 1024 // 64MB
+
+// blockCache is an LRU cache of the blocks loaded from the block store,
+// bounded by their total size. The block store reassembles a block from all
+// its parts on every load: a cache shared by the proofs of a single request
+// loads each block once, however many of its txs are proven. It is meant to be
+// discarded with the request, so it's not safe for concurrent use.
+type blockCache struct {
+	maxBytes int
+	size     int
+	blocks   map[int64]*list.Element
+	list     *list.List
+}
+
+type blockCacheEntry struct {
+	height int64
+	block  *types.Block
+	size   int
+}
+
+func newBlockCache(maxBytes int) *blockCache {
+	return &blockCache{
+		maxBytes: maxBytes,
+		blocks:   make(map[int64]*list.Element),
+		list:     list.New(),
+	}
+}
+
+// LoadBlock returns the block at the given height, loading it from the block
+// store if it's not cached. A missing block (e.g. if one of its parts is
+// missing) is returned as nil and not cached, so that it's loaded again next
+// time. A block larger than the bound of the cache is not cached either.
+func (c *blockCache) LoadBlock(height int64) *types.Block {
+	if e, ok := c.blocks[height]; ok {
+		c.list.MoveToFront(e)
+		return e.Value.(*blockCacheEntry).block
+	}
+
+	block := env.BlockStore.LoadBlock(height)
+	if block == nil {
+		return nil
+	}
+	size := block.Size()
+	if size > c.maxBytes {
+		return block
+	}
+	for c.size+size > c.maxBytes {
+		back := c.list.Back()
+		entry := back.Value.(*blockCacheEntry)
+		c.list.Remove(back)
+		delete(c.blocks, entry.height)
+		c.size -= entry.size
+	}
+	c.blocks[height] = c.list.PushFront(&blockCacheEntry{height: height, block: block, size: size})
+	c.size += size
+	return block
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/crypto"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+	"github.com/tendermint/tendermint/store"
+	"github.com/tendermint/tendermint/types"
+)
+
+// blocksStore serves the given blocks, and counts the loads of each height.
+type blocksStore struct {
+	mockBlockStore
+	blocks map[int64]*types.Block
+	loads  map[int64]int
+}
+
+func (s *blocksStore) LoadBlock(height int64) *types.Block {
+	s.loads[height]++
+	return s.blocks[height]
+}
+
+func TestBlockCache(t *testing.T) {
+	blocks := make(map[int64]*types.Block)
+	for h := int64(1); h <= 3; h++ {
+		blocks[h] = types.MakeBlock(h, []types.Tx{types.Tx(fmt.Sprintf("tx-%d", h))}, &types.Commit{}, nil)
+	}
+	bs := &blocksStore{blocks: blocks, loads: make(map[int64]int)}
+	env = &Environment{BlockStore: bs}
+
+	// room for two blocks
+	c := newBlockCache(2*blocks[1].Size() + 1)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, blocks[1], c.LoadBlock(1))
+	}
+	assert.Equal(t, 1, bs.loads[1])
+
+	// a missing block isn't cached
+	assert.Nil(t, c.LoadBlock(4))
+	assert.Nil(t, c.LoadBlock(4))
+	assert.Equal(t, 2, bs.loads[4])
+
+	// the least recently used block is evicted
+	assert.Equal(t, blocks[2], c.LoadBlock(2))
+	assert.Equal(t, blocks[1], c.LoadBlock(1))
+	assert.Equal(t, blocks[3], c.LoadBlock(3))
+	assert.Equal(t, blocks[1], c.LoadBlock(1))
+	assert.Equal(t, blocks[2], c.LoadBlock(2))
+	assert.Equal(t, map[int64]int{1: 1, 2: 2, 3: 1, 4: 2}, bs.loads)
+
+	// a block larger than the cache isn't cached
+	c = newBlockCache(blocks[1].Size() - 1)
+	assert.Equal(t, blocks[1], c.LoadBlock(1))
+	assert.Equal(t, blocks[1], c.LoadBlock(1))
+	assert.Equal(t, 3, bs.loads[1])
+}
+
+// BenchmarkProveTx proves the 50 txs of a block, as TxSearch does when they
+// all match.
+func BenchmarkProveTx(b *testing.B) {
+	const numTxs = 50
+
+	txs := make([]types.Tx, numTxs)
+	for i := range txs {
+		txs[i] = types.Tx(fmt.Sprintf("tx-%d-%0400d", i, i))
+	}
+	block := types.MakeBlock(1, txs, &types.Commit{}, nil)
+	block.ProposerAddress = tmrand.Bytes(crypto.AddressSize)
+	parts := block.MakePartSet(types.BlockPartSizeBytes)
+	bs := store.NewBlockStore(dbm.NewMemDB())
+	bs.SaveBlock(block, parts, &types.Commit{Height: 1})
+	env = &Environment{BlockStore: bs}
+
+	for _, bc := range []struct {
+		name     string
+		maxBytes int
+	}{
+		{"uncached", 0},
+		{"cached", maxBlockCacheBytes},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				blocks := newBlockCache(bc.maxBytes)
+				for index := uint32(0); index < numTxs; index++ {
+					_, err := proveTx(context.Background(), blocks, 1, index)
+					require.NoError(b, err)
+				}
+			}
+		})
+	}
+}
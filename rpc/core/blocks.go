@@ -92,6 +92,43 @@ func Block(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlock, error)
 	return &ctypes.ResultBlock{BlockID: blockMeta.BlockID, Block: block}, nil
 }
 
+// RawBlock gets the bytes of the block at a given height, as reassembled from
+// its parts by the block store, and its BlockID. Clients can decode them as a
+// tendermint.types.Block, or hash them to verify proofs locally. If no height
+// is provided, it will fetch the latest block. ErrBlockPruned is returned if
+// the block, or one of its parts, is missing.
+// More: https://docs.tendermint.com/v0.34/rpc/#/Info/raw_block
+func RawBlock(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultRawBlock, error) {
+	height, err := getHeight(env.BlockStore.Height(), heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, blockID, err := loadRawBlock(height)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load raw block: %w", err)
+	}
+	return &ctypes.ResultRawBlock{BlockID: blockID, Block: bz}, nil
+}
+
+// loadRawBlock reassembles the block at the given height from its parts, as
+// the block store does before decoding it, and returns its bytes and BlockID.
+func loadRawBlock(height int64) ([]byte, types.BlockID, error) {
+	blockMeta := env.BlockStore.LoadBlockMeta(height)
+	if blockMeta == nil {
+		return nil, types.BlockID{}, ErrBlockPruned{Height: height, Base: env.BlockStore.Base()}
+	}
+	var bz []byte
+	for i := 0; i < int(blockMeta.BlockID.PartSetHeader.Total); i++ {
+		part := env.BlockStore.LoadBlockPart(height, i)
+		if part == nil {
+			return nil, types.BlockID{}, ErrBlockPruned{Height: height, Base: env.BlockStore.Base()}
+		}
+		bz = append(bz, part.Bytes...)
+	}
+	return bz, blockMeta.BlockID, nil
+}
+
 // BlockByHash gets block by hash.
 // More: https://docs.tendermint.com/v0.34/rpc/#/Info/block_by_hash
 func BlockByHash(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultBlock, error) {
@@ -160,6 +197,22 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	}, nil
 }
 
+// MinIndexedHeight returns the lowest height indexed by the block indexer, or
+// 0 if no height has been indexed yet.
+func MinIndexedHeight(ctx *rpctypes.Context) (*ctypes.ResultMinIndexedHeight, error) {
+	// skip if block indexing is disabled
+	if _, ok := env.BlockIndexer.(*blockidxnull.BlockerIndexer); ok {
+		return nil, errors.New("block indexing is disabled")
+	}
+
+	height, err := env.BlockIndexer.MinIndexedHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ctypes.ResultMinIndexedHeight{Height: height}, nil
+}
+
 // BlockSearch searches for a paginated set of blocks matching BeginBlock and
 // EndBlock event search criteria.
 func BlockSearch(
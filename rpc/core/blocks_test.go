@@ -4,16 +4,21 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	dbm "github.com/tendermint/tm-db"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
 	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -117,6 +122,63 @@ func TestBlockResults(t *testing.T) {
 	}
 }
 
+// partlessBlockStore is a block store missing a part of every block.
+type partlessBlockStore struct {
+	*store.BlockStore
+	missing int
+}
+
+func (s partlessBlockStore) LoadBlockPart(height int64, index int) *types.Part {
+	if index == s.missing {
+		return nil
+	}
+	return s.BlockStore.LoadBlockPart(height, index)
+}
+
+func TestRawBlock(t *testing.T) {
+	// large enough txs to span several parts
+	txs := make([]types.Tx, 3)
+	for i := range txs {
+		txs[i] = tmrand.Bytes(int(types.BlockPartSizeBytes / 2))
+	}
+	block := types.MakeBlock(1, txs, &types.Commit{}, nil)
+	block.ProposerAddress = tmrand.Bytes(crypto.AddressSize)
+	parts := block.MakePartSet(types.BlockPartSizeBytes)
+	require.Greater(t, parts.Total(), uint32(1))
+	bs := store.NewBlockStore(dbm.NewMemDB())
+	bs.SaveBlock(block, parts, &types.Commit{Height: 1})
+	env = &Environment{BlockStore: bs}
+
+	var want []byte
+	for i := 0; i < int(parts.Total()); i++ {
+		want = append(want, parts.GetPart(i).Bytes...)
+	}
+	height := int64(1)
+	res, err := RawBlock(&rpctypes.Context{}, &height)
+	require.NoError(t, err)
+	assert.Equal(t, want, res.Block)
+	assert.Equal(t, types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}, res.BlockID)
+
+	// the bytes decode to the block
+	pbb := new(tmproto.Block)
+	require.NoError(t, proto.Unmarshal(res.Block, pbb))
+	decoded, err := types.BlockFromProto(pbb)
+	require.NoError(t, err)
+	assert.Equal(t, block.Hash(), decoded.Hash())
+
+	// the latest block by default
+	res, err = RawBlock(&rpctypes.Context{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, res.Block)
+
+	// a missing part fails the request
+	env.BlockStore = partlessBlockStore{BlockStore: bs, missing: 1}
+	_, err = RawBlock(&rpctypes.Context{}, &height)
+	var pruned ErrBlockPruned
+	require.ErrorAs(t, err, &pruned)
+	assert.Equal(t, ErrBlockPruned{Height: 1, Base: 1}, pruned)
+}
+
 type mockBlockStore struct {
 	height int64
 }
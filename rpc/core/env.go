@@ -31,6 +31,18 @@ const (
 	// genesisChunkSize is the maximum size, in bytes, of each
 	// chunk in the genesis structure for the chunked API
 	genesisChunkSize = 16 * 1024 * 1024 // 16
+
+	// maxTxSearchHashes is the maximum number of hashes which can be looked up
+	// by a single TxSearchByHashes call
+	maxTxSearchHashes = 1000
+
+	// maxTxCountHeights is the maximum number of heights whose tx count can be
+	// returned by a single TxCountByHeight call
+	maxTxCountHeights = 10000
+
+	// maxTxSearchStreamResults is the maximum number of transactions which can
+	// be streamed by a single TxSearchStream call over the WebSocket
+	maxTxSearchStreamResults = 10000
 )
 
 var (
@@ -96,14 +108,65 @@ type Environment struct {
 
 	Logger log.Logger
 
-	Config cfg.RPCConfig
+	Config  cfg.RPCConfig
+	Metrics *Metrics
 
 	// cache of chunked genesis data.
 	genChunks []string
+
+	// cache of tx_search results, nil if disabled.
+	txSearchCache *txSearchCache
 }
 
 //----------------------------------------------
 
+// ErrBlockPruned is returned when a block is needed, e.g. to prove a tx, but
+// is not available anymore: it has been pruned, or one of its parts is missing.
+// The height and the base height of the block store are returned in the data
+// of the RPC error response, so that clients can fall back, e.g. to an
+// archive node, or retry without a proof.
+type ErrBlockPruned struct {
+	Height int64
+	Base   int64
+}
+
+func (e ErrBlockPruned) Error() string {
+	return fmt.Sprintf("block at height %d has been pruned (base height %d)", e.Height, e.Base)
+}
+
+// RPCErrorData implements rpctypes.ErrorWithData.
+func (e ErrBlockPruned) RPCErrorData() interface{} {
+	return map[string]interface{}{
+		"error":  e.Error(),
+		"code":   "block_pruned",
+		"height": e.Height,
+		"base":   e.Base,
+	}
+}
+
+// ErrPageOutOfRange is returned when the requested page is outside of the
+// pages of results. The valid range is returned in the data of the RPC error
+// response, so that clients can clamp the page and retry.
+type ErrPageOutOfRange struct {
+	Page    int
+	MinPage int
+	MaxPage int
+}
+
+func (e ErrPageOutOfRange) Error() string {
+	return fmt.Sprintf("page should be within [%d, %d] range, given %d", e.MinPage, e.MaxPage, e.Page)
+}
+
+// RPCErrorData implements rpctypes.ErrorWithData.
+func (e ErrPageOutOfRange) RPCErrorData() interface{} {
+	return map[string]interface{}{
+		"error":    e.Error(),
+		"page":     e.Page,
+		"min_page": e.MinPage,
+		"max_page": e.MaxPage,
+	}
+}
+
 func validatePage(pagePtr *int, perPage, totalCount int) (int, error) {
 	if perPage < 1 {
 		panic(fmt.Sprintf("zero or negative perPage: %d", perPage))
@@ -119,7 +182,7 @@ func validatePage(pagePtr *int, perPage, totalCount int) (int, error) {
 	}
 	page := *pagePtr
 	if page <= 0 || page > pages {
-		return 1, fmt.Errorf("page should be within [1, %d] range, given %d", pages, page)
+		return 1, ErrPageOutOfRange{Page: page, MinPage: 1, MaxPage: pages}
 	}
 
 	return page, nil
@@ -1,10 +1,12 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPaginationPage(t *testing.T) {
@@ -56,6 +58,21 @@ func TestPaginationPage(t *testing.T) {
 	}
 }
 
+func TestPaginationPageOutOfRange(t *testing.T) {
+	page := 4
+	_, err := validatePage(&page, 2, 5)
+	var pageErr ErrPageOutOfRange
+	require.True(t, errors.As(err, &pageErr))
+	assert.Equal(t, ErrPageOutOfRange{Page: 4, MinPage: 1, MaxPage: 3}, pageErr)
+	assert.Equal(t, "page should be within [1, 3] range, given 4", err.Error())
+	assert.Equal(t, map[string]interface{}{
+		"error":    "page should be within [1, 3] range, given 4",
+		"page":     4,
+		"min_page": 1,
+		"max_page": 3,
+	}, pageErr.RPCErrorData())
+}
+
 func TestPaginationPerPage(t *testing.T) {
 	cases := []struct {
 		totalCount int
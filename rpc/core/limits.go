@@ -0,0 +1,24 @@
+package core
+
+import (
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+// RPCLimits returns the limits enforced by this node's RPC server, so clients
+// can adapt their requests (e.g. page sizes) to them.
+func RPCLimits(ctx *rpctypes.Context) (*ctypes.ResultRPCLimits, error) {
+	return &ctypes.ResultRPCLimits{
+		DefaultPerPage:            defaultPerPage,
+		MaxPerPage:                maxPerPage,
+		MaxQueryLength:            maxQueryLength,
+		MaxTxSearchHashes:         maxTxSearchHashes,
+		MaxTxSearchStreamResults:  maxTxSearchStreamResults,
+		MaxTxBatchHashes:          maxPerPage,
+		MaxTxCountHeights:         maxTxCountHeights,
+		MaxBodyBytes:              env.Config.MaxBodyBytes,
+		MaxHeaderBytes:            env.Config.MaxHeaderBytes,
+		MaxSubscriptionClients:    env.Config.MaxSubscriptionClients,
+		MaxSubscriptionsPerClient: env.Config.MaxSubscriptionsPerClient,
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	tmmath "github.com/tendermint/tendermint/libs/math"
 	mempl "github.com/tendermint/tendermint/mempool"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
@@ -25,7 +26,7 @@ func BroadcastTxAsync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadca
 	if err != nil {
 		return nil, err
 	}
-	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+	return &ctypes.ResultBroadcastTx{Hash: types.TxHash(tx)}, nil
 }
 
 // BroadcastTxSync returns with the response from CheckTx. Does not wait for
@@ -54,7 +55,7 @@ func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcas
 			Data:      r.Data,
 			Log:       r.Log,
 			Codespace: r.Codespace,
-			Hash:      tx.Hash(),
+			Hash:      types.TxHash(tx),
 		}, nil
 	}
 }
@@ -107,7 +108,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 			return &ctypes.ResultBroadcastTxCommit{
 				CheckTx:   *checkTxRes,
 				DeliverTx: abci.ResponseDeliverTx{},
-				Hash:      tx.Hash(),
+				Hash:      types.TxHash(tx),
 			}, nil
 		}
 
@@ -118,7 +119,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 			return &ctypes.ResultBroadcastTxCommit{
 				CheckTx:   *checkTxRes,
 				DeliverTx: deliverTxRes.Result,
-				Hash:      tx.Hash(),
+				Hash:      types.TxHash(tx),
 				Height:    deliverTxRes.Height,
 			}, nil
 		case <-deliverTxSub.Cancelled():
@@ -133,7 +134,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 			return &ctypes.ResultBroadcastTxCommit{
 				CheckTx:   *checkTxRes,
 				DeliverTx: abci.ResponseDeliverTx{},
-				Hash:      tx.Hash(),
+				Hash:      types.TxHash(tx),
 			}, err
 		case <-time.After(env.Config.TimeoutBroadcastTxCommit):
 			err = errors.New("timed out waiting for tx to be included in a block")
@@ -141,7 +142,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 			return &ctypes.ResultBroadcastTxCommit{
 				CheckTx:   *checkTxRes,
 				DeliverTx: abci.ResponseDeliverTx{},
-				Hash:      tx.Hash(),
+				Hash:      types.TxHash(tx),
 			}, err
 		}
 	}
@@ -171,6 +172,44 @@ func NumUnconfirmedTxs(ctx *rpctypes.Context) (*ctypes.ResultUnconfirmedTxs, err
 		TotalBytes: env.Mempool.SizeBytes()}, nil
 }
 
+// UnconfirmedTxsByAge gets the unconfirmed transactions added to the mempool
+// at least ?older_than seconds ago (maximum ?limit entries), oldest first,
+// along with their priority and size.
+// More: https://docs.tendermint.com/v0.34/rpc/#/Info/unconfirmed_txs_by_age
+func UnconfirmedTxsByAge(ctx *rpctypes.Context, olderThan int64, limitPtr *int) (*ctypes.ResultUnconfirmedTxsByAge, error) {
+	if olderThan < 0 {
+		return nil, errors.New("older_than can't be negative")
+	}
+	// reuse per_page validator
+	limit := validatePerPage(limitPtr)
+
+	now := time.Now()
+	pending := env.Mempool.PendingTxs()
+	txs := make([]ctypes.UnconfirmedTx, 0, tmmath.MinInt(len(pending), limit))
+	for _, tx := range pending {
+		if len(txs) >= limit {
+			break
+		}
+		age := now.Sub(tx.Time)
+		if age < time.Duration(olderThan)*time.Second {
+			continue
+		}
+		txs = append(txs, ctypes.UnconfirmedTx{
+			Hash:     types.TxHash(tx.Tx),
+			Size:     len(tx.Tx),
+			Priority: tx.Priority,
+			Height:   tx.Height,
+			Time:     tx.Time,
+			Age:      age,
+		})
+	}
+	return &ctypes.ResultUnconfirmedTxsByAge{
+		Count: len(txs),
+		Total: len(pending),
+		Txs:   txs,
+	}, nil
+}
+
 // CheckTx checks the transaction without executing it. The transaction won't
 // be added to the mempool either.
 // More: https://docs.tendermint.com/v0.34/rpc/#/Tx/check_tx
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mempl "github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/mempool/mock"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+type pendingTxsMempool struct {
+	mock.Mempool
+	txs []mempl.PendingTx
+}
+
+func (mp pendingTxsMempool) PendingTxs() []mempl.PendingTx { return mp.txs }
+
+func TestUnconfirmedTxsByAge(t *testing.T) {
+	now := time.Now()
+	txs := []mempl.PendingTx{
+		{Tx: types.Tx("a"), Height: 1, Time: now.Add(-time.Hour), Priority: 1},
+		{Tx: types.Tx("bb"), Height: 5, Time: now.Add(-10 * time.Minute), Priority: 2},
+		{Tx: types.Tx("ccc"), Height: 9, Time: now, Priority: 3},
+	}
+	env = &Environment{}
+	env.Mempool = pendingTxsMempool{txs: txs}
+
+	res, err := UnconfirmedTxsByAge(&rpctypes.Context{}, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Count)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, types.Tx("a").Hash(), []byte(res.Txs[0].Hash))
+	assert.Equal(t, 2, res.Txs[1].Size)
+	assert.Equal(t, int64(2), res.Txs[1].Priority)
+	assert.Equal(t, int64(5), res.Txs[1].Height)
+	assert.GreaterOrEqual(t, res.Txs[0].Age, time.Hour)
+
+	res, err = UnconfirmedTxsByAge(&rpctypes.Context{}, 300, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Count)
+	assert.Equal(t, 3, res.Total)
+
+	limit := 1
+	res, err = UnconfirmedTxsByAge(&rpctypes.Context{}, 0, &limit)
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 1)
+	assert.Equal(t, int64(1), res.Txs[0].Height)
+
+	_, err = UnconfirmedTxsByAge(&rpctypes.Context{}, -1, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "rpc"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of tx_search queries served from the cache.
+	TxSearchCacheHits metrics.Counter
+
+	// Number of tx_search queries not found in the cache, while the cache is
+	// enabled. The hit rate is hits / (hits + misses).
+	TxSearchCacheMisses metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		TxSearchCacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "tx_search_cache_hits",
+			Help:      "Number of tx_search queries served from the cache.",
+		}, labels).With(labelsAndValues...),
+
+		TxSearchCacheMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "tx_search_cache_misses",
+			Help:      "Number of tx_search queries not found in the cache.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		TxSearchCacheHits:   discard.NewCounter(),
+		TxSearchCacheMisses: discard.NewCounter(),
+	}
+}
@@ -14,26 +14,36 @@ var Routes = map[string]*rpc.RPCFunc{
 	"unsubscribe_all": rpc.NewWSRPCFunc(UnsubscribeAll, ""),
 
 	// info API
-	"health":               rpc.NewRPCFunc(Health, ""),
-	"status":               rpc.NewRPCFunc(Status, ""),
-	"net_info":             rpc.NewRPCFunc(NetInfo, ""),
-	"blockchain":           rpc.NewRPCFunc(BlockchainInfo, "minHeight,maxHeight", rpc.Cacheable()),
-	"genesis":              rpc.NewRPCFunc(Genesis, "", rpc.Cacheable()),
-	"genesis_chunked":      rpc.NewRPCFunc(GenesisChunked, "chunk", rpc.Cacheable()),
-	"block":                rpc.NewRPCFunc(Block, "height", rpc.Cacheable("height")),
-	"block_by_hash":        rpc.NewRPCFunc(BlockByHash, "hash", rpc.Cacheable()),
-	"block_results":        rpc.NewRPCFunc(BlockResults, "height", rpc.Cacheable("height")),
-	"commit":               rpc.NewRPCFunc(Commit, "height", rpc.Cacheable("height")),
-	"check_tx":             rpc.NewRPCFunc(CheckTx, "tx"),
-	"tx":                   rpc.NewRPCFunc(Tx, "hash,prove", rpc.Cacheable()),
-	"tx_search":            rpc.NewRPCFunc(TxSearch, "query,prove,page,per_page,order_by"),
-	"block_search":         rpc.NewRPCFunc(BlockSearch, "query,page,per_page,order_by"),
-	"validators":           rpc.NewRPCFunc(Validators, "height,page,per_page", rpc.Cacheable("height")),
-	"dump_consensus_state": rpc.NewRPCFunc(DumpConsensusState, ""),
-	"consensus_state":      rpc.NewRPCFunc(ConsensusState, ""),
-	"consensus_params":     rpc.NewRPCFunc(ConsensusParams, "height", rpc.Cacheable("height")),
-	"unconfirmed_txs":      rpc.NewRPCFunc(UnconfirmedTxs, "limit"),
-	"num_unconfirmed_txs":  rpc.NewRPCFunc(NumUnconfirmedTxs, ""),
+	"health":                 rpc.NewRPCFunc(Health, ""),
+	"rpc_limits":             rpc.NewRPCFunc(RPCLimits, ""),
+	"status":                 rpc.NewRPCFunc(Status, ""),
+	"net_info":               rpc.NewRPCFunc(NetInfo, ""),
+	"blockchain":             rpc.NewRPCFunc(BlockchainInfo, "minHeight,maxHeight", rpc.Cacheable()),
+	"genesis":                rpc.NewRPCFunc(Genesis, "", rpc.Cacheable()),
+	"genesis_chunked":        rpc.NewRPCFunc(GenesisChunked, "chunk", rpc.Cacheable()),
+	"block":                  rpc.NewRPCFunc(Block, "height", rpc.Cacheable("height")),
+	"block_by_hash":          rpc.NewRPCFunc(BlockByHash, "hash", rpc.Cacheable()),
+	"raw_block":              rpc.NewRPCFunc(RawBlock, "height", rpc.Cacheable("height")),
+	"block_results":          rpc.NewRPCFunc(BlockResults, "height", rpc.Cacheable("height")),
+	"commit":                 rpc.NewRPCFunc(Commit, "height", rpc.Cacheable("height")),
+	"check_tx":               rpc.NewRPCFunc(CheckTx, "tx"),
+	"tx":                     rpc.NewRPCFunc(Tx, "hash,prove", rpc.Cacheable()),
+	"tx_by_height_index":     rpc.NewRPCFunc(TxByHeightIndex, "height,index,prove", rpc.Cacheable()),
+	"tx_all":                 rpc.NewRPCFunc(TxAll, "hash,page,per_page"),
+	"tx_search_by_hashes":    rpc.NewRPCFunc(TxSearchByHashes, "hashes,prove,page,per_page,order_by"),
+	"tx_batch":               rpc.NewRPCFunc(TxBatch, "hashes,prove"),
+	"tx_search_stream":       rpc.NewRPCFunc(TxSearchStream, "query,prove,page,per_page,order_by"),
+	"tx_count_by_height":     rpc.NewRPCFunc(TxCountByHeight, "min_height,max_height"),
+	"tx_search":              rpc.NewRPCFunc(TxSearch, "query,prove,page,per_page,order_by,skip_pruned_proofs,sort_by,include_match_info,cursor,count_only,min_height,max_height,order_by_event"),
+	"block_search":           rpc.NewRPCFunc(BlockSearch, "query,page,per_page,order_by"),
+	"min_indexed_height":     rpc.NewRPCFunc(MinIndexedHeight, ""),
+	"validators":             rpc.NewRPCFunc(Validators, "height,page,per_page", rpc.Cacheable("height")),
+	"dump_consensus_state":   rpc.NewRPCFunc(DumpConsensusState, ""),
+	"consensus_state":        rpc.NewRPCFunc(ConsensusState, ""),
+	"consensus_params":       rpc.NewRPCFunc(ConsensusParams, "height", rpc.Cacheable("height")),
+	"unconfirmed_txs":        rpc.NewRPCFunc(UnconfirmedTxs, "limit"),
+	"num_unconfirmed_txs":    rpc.NewRPCFunc(NumUnconfirmedTxs, ""),
+	"unconfirmed_txs_by_age": rpc.NewRPCFunc(UnconfirmedTxsByAge, "older_than,limit"),
 
 	// tx broadcast API
 	"broadcast_tx_commit": rpc.NewRPCFunc(BroadcastTxCommit, "tx"),
@@ -1,14 +1,22 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
 	tmmath "github.com/tendermint/tendermint/libs/math"
 	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	blockidxnull "github.com/tendermint/tendermint/state/indexer/block/null"
 	"github.com/tendermint/tendermint/state/txindex/null"
 	"github.com/tendermint/tendermint/types"
 )
@@ -29,7 +37,7 @@ func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error
 	}
 
 	if r == nil {
-		return nil, fmt.Errorf("tx (%X) not found", hash)
+		return nil, txNotFoundError(hash)
 	}
 
 	height := r.Height
@@ -37,22 +45,198 @@ func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error
 
 	var proof types.TxProof
 	if prove {
-		block := env.BlockStore.LoadBlock(height)
-		proof = block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
+		if proof, err = proveTx(ctx.Context(), newBlockCache(maxBlockCacheBytes), height, index); err != nil {
+			return nil, err
+		}
 	}
 
 	return &ctypes.ResultTx{
 		Hash:     hash,
 		Height:   height,
 		Index:    index,
-		TxResult: r.Result,
+		TxResult: stripExcludedEvents(r.Result),
 		Tx:       r.Tx,
 		Proof:    proof,
 	}, nil
 }
 
+// TxByHeightIndex returns the transaction at the given index of the block at
+// the given height, read from the block store. Unlike Tx, it does not require
+// the transaction to be indexed, so it works even when indexing is disabled.
+// The result of the transaction is left empty if the node discards the ABCI
+// responses.
+func TxByHeightIndex(ctx *rpctypes.Context, height int64, index uint32, prove bool) (*ctypes.ResultTx, error) {
+	height, err := getHeight(env.BlockStore.Height(), &height)
+	if err != nil {
+		return nil, err
+	}
+
+	block := env.BlockStore.LoadBlock(height)
+	if block == nil {
+		return nil, fmt.Errorf("block at height %d not found", height)
+	}
+	if int(index) >= len(block.Data.Txs) {
+		return nil, fmt.Errorf("index %d is out of range: block at height %d has %d txs",
+			index, height, len(block.Data.Txs))
+	}
+	tx := block.Data.Txs[index]
+
+	var txResult abci.ResponseDeliverTx
+	results, err := env.StateStore.LoadABCIResponses(height)
+	if err == nil && int(index) < len(results.DeliverTxs) {
+		txResult = *results.DeliverTxs[index]
+	}
+
+	var proof types.TxProof
+	if prove {
+		proof = block.Data.Txs.Proof(int(index))
+	}
+
+	return &ctypes.ResultTx{
+		Hash:     types.TxHash(tx),
+		Height:   height,
+		Index:    index,
+		TxResult: stripExcludedEvents(txResult),
+		Tx:       tx,
+		Proof:    proof,
+	}, nil
+}
+
+// TxCountByHeight returns the number of indexed transactions at each height of
+// [minHeight, maxHeight], computed from the height index of the tx indexer,
+// without loading the transactions. maxHeight defaults to the latest height,
+// and the range is limited to the last maxTxCountHeights heights of it.
+// More: https://docs.tendermint.com/v0.34/rpc/#/Info/tx_count_by_height
+func TxCountByHeight(ctx *rpctypes.Context, minHeight, maxHeight int64) (*ctypes.ResultTxCountByHeight, error) {
+	// if index is disabled, return error
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil, errors.New("transaction indexing is disabled")
+	}
+
+	// the tx index may retain heights pruned from the block store
+	minHeight, maxHeight, err := filterMinMax(1, env.BlockStore.Height(), minHeight, maxHeight, maxTxCountHeights)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := env.TxIndexer.CountByHeight(ctx.Context(), minHeight, maxHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultTxCountByHeight{MinHeight: minHeight, MaxHeight: maxHeight, Counts: counts}, nil
+}
+
+// stripExcludedEvents returns the result without the events whose type is
+// listed in rpc.excluded_event_types.
+func stripExcludedEvents(result abci.ResponseDeliverTx) abci.ResponseDeliverTx {
+	excluded := env.Config.ExcludedEventTypes
+	if len(excluded) == 0 || len(result.Events) == 0 {
+		return result
+	}
+
+	events := make([]abci.Event, 0, len(result.Events))
+	for _, event := range result.Events {
+		if !containsString(excluded, event.Type) {
+			events = append(events, event)
+		}
+	}
+	result.Events = events
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// txNotFoundError returns the error reported when the tx with the given hash
+// is not indexed. If indexing started after the initial height of the chain
+// (e.g. it was enabled late, or the node was state synced), the tx may have
+// been committed before the lowest indexed height, which is then reported.
+func txNotFoundError(hash []byte) error {
+	if _, ok := env.BlockIndexer.(*blockidxnull.BlockerIndexer); ok || env.BlockIndexer == nil {
+		return fmt.Errorf("tx (%X) not found", hash)
+	}
+
+	initialHeight := int64(1)
+	if env.GenDoc != nil && env.GenDoc.InitialHeight > 1 {
+		initialHeight = env.GenDoc.InitialHeight
+	}
+	minIndexed, err := env.BlockIndexer.MinIndexedHeight()
+	if err != nil || minIndexed <= initialHeight {
+		return fmt.Errorf("tx (%X) not found", hash)
+	}
+
+	return fmt.Errorf("tx (%X) not found, but it may predate index availability: "+
+		"heights below %d are not indexed, run reindex-event to index them", hash, minIndexed)
+}
+
+// TxAll returns all indexed occurrences of the transaction with the given
+// hash (maximum ?per_page entries), sorted by height. Unlike Tx, it does not
+// hide duplicates, which may exist if the app does not enforce tx uniqueness.
+func TxAll(
+	ctx *rpctypes.Context,
+	hash []byte,
+	pagePtr, perPagePtr *int,
+) (*ctypes.ResultTxSearch, error) {
+	// if index is disabled, return error
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil, errors.New("transaction indexing is disabled")
+	}
+
+	results, err := env.TxIndexer.GetAll(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// paginate results
+	totalCount := len(results)
+	perPage := validatePerPage(perPagePtr)
+
+	page, err := validatePage(pagePtr, perPage, totalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	skipCount := validateSkipCount(page, perPage)
+	pageSize := tmmath.MinInt(perPage, totalCount-skipCount)
+
+	apiResults := make([]*ctypes.ResultTx, 0, pageSize)
+	for i := skipCount; i < skipCount+pageSize; i++ {
+		r := results[i]
+		apiResults = append(apiResults, &ctypes.ResultTx{
+			Hash:     hash,
+			Height:   r.Height,
+			Index:    r.Index,
+			TxResult: stripExcludedEvents(r.Result),
+			Tx:       r.Tx,
+		})
+	}
+
+	return &ctypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}, nil
+}
+
 // TxSearch allows you to query for multiple transactions results. It returns a
 // list of transactions (maximum ?per_page entries) and the total count.
+// If skipPrunedProofs is true, proofs are silently left out for transactions
+// whose block has been pruned, instead of failing the whole request.
+// If sortBy is "time-attr", orderBy names an indexed time attribute by which
+// the transactions are sorted, see sortTxResultsByTimeAttr.
+// If includeMatchInfo is true, each transaction is annotated with the indexed
+// attributes which satisfied a condition of the query, see txMatchInfo.
+// Results sorted by height come with a cursor when more results follow the
+// page. Given instead of a page, it resumes after the last transaction of the
+// previous page, without sorting the results which precede it.
+// If countOnly is true, only the total count is returned, without loading,
+// sorting or paginating the transactions. It can't be combined with prove.
+// If minHeight or maxHeight isn't 0, the transactions committed outside of
+// [minHeight, maxHeight] are left out of the results and the total count.
+// If orderByEvent isn't empty, it names an indexed event attribute by whose
+// value the transactions are sorted instead, see sortTxResultsByEventAttr.
 // More: https://docs.tendermint.com/v0.34/rpc/#/Info/tx_search
 func TxSearch(
 	ctx *rpctypes.Context,
@@ -60,6 +244,13 @@ func TxSearch(
 	prove bool,
 	pagePtr, perPagePtr *int,
 	orderBy string,
+	skipPrunedProofs bool,
+	sortBy string,
+	includeMatchInfo bool,
+	cursor string,
+	countOnly bool,
+	minHeight, maxHeight int64,
+	orderByEvent string,
 ) (*ctypes.ResultTxSearch, error) {
 
 	// if index is disabled, return error
@@ -67,6 +258,14 @@ func TxSearch(
 		return nil, errors.New("transaction indexing is disabled")
 	} else if len(query) > maxQueryLength {
 		return nil, errors.New("maximum query length exceeded")
+	} else if countOnly && prove {
+		return nil, errors.New("count_only can't be combined with prove")
+	} else if minHeight < 0 || maxHeight < 0 {
+		return nil, errors.New("min_height and max_height can't be negative")
+	} else if maxHeight > 0 && minHeight > maxHeight {
+		return nil, fmt.Errorf("min_height %d can't be greater than max_height %d", minHeight, maxHeight)
+	} else if orderByEvent != "" && sortBy != "" {
+		return nil, errors.New("order_by_event can't be combined with sort_by")
 	}
 
 	q, err := tmquery.New(query)
@@ -74,32 +273,478 @@ func TxSearch(
 		return nil, err
 	}
 
+	var (
+		cacheKey string
+		height   int64
+		now      time.Time
+	)
+	if env.txSearchCache != nil {
+		cacheKey = txSearchCacheKey(query, prove, pagePtr, perPagePtr, orderBy, skipPrunedProofs, sortBy,
+			includeMatchInfo, cursor, countOnly, minHeight, maxHeight, orderByEvent)
+		height, now = env.BlockStore.Height(), time.Now()
+		if result, ok := env.txSearchCache.Get(cacheKey, height, now); ok {
+			env.Metrics.TxSearchCacheHits.Add(1)
+			return result, nil
+		}
+		env.Metrics.TxSearchCacheMisses.Add(1)
+	}
+
+	results, err := env.TxIndexer.Search(ctx.Context(), q)
+	if err != nil {
+		return nil, err
+	}
+	if minHeight > 0 || maxHeight > 0 {
+		results = filterTxResultsByHeight(results, minHeight, maxHeight)
+	}
+	totalCount := len(results)
+	if countOnly {
+		result := &ctypes.ResultTxSearch{Txs: []*ctypes.ResultTx{}, TotalCount: totalCount}
+		if env.txSearchCache != nil {
+			env.txSearchCache.Push(cacheKey, height, now, result)
+		}
+		return result, nil
+	}
+
+	byHeight := (sortBy == "" || sortBy == "height") && orderByEvent == ""
+	if cursor != "" {
+		if !byHeight {
+			return nil, errors.New("cursor can only be used with transactions sorted by height")
+		} else if pagePtr != nil {
+			return nil, errors.New("cursor can't be combined with page")
+		}
+		c, err := decodeTxSearchCursor(cursor, orderBy)
+		if err != nil {
+			return nil, err
+		}
+		results = c.after(results)
+	}
+
+	// sort results (must be done before pagination)
+	switch {
+	case orderByEvent != "":
+		err = sortTxResultsByEventAttr(results, orderByEvent)
+	case sortBy == "", sortBy == "height":
+		err = sortTxResults(results, orderBy)
+	case sortBy == "time-attr":
+		err = sortTxResultsByTimeAttr(results, orderBy)
+	default:
+		err = errors.New("expected sort_by to be either `height` or `time-attr` or empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := paginateTxResults(ctx.Context(), results, prove, skipPrunedProofs, pagePtr, perPagePtr)
+	if err != nil {
+		return nil, err
+	}
+	result.TotalCount = totalCount
+	if n := len(result.Txs); byHeight && n > 0 {
+		last, lastResult := result.Txs[n-1], results[len(results)-1]
+		if last.Height != lastResult.Height || last.Index != lastResult.Index {
+			result.NextCursor = encodeTxSearchCursor(last, orderBy == "desc")
+		}
+	}
+	if includeMatchInfo {
+		for _, tx := range result.Txs {
+			if tx.MatchInfo, err = txMatchInfo(q, tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if env.txSearchCache != nil {
+		env.txSearchCache.Push(cacheKey, height, now, result)
+	}
+	return result, nil
+}
+
+// filterTxResultsByHeight returns the results committed within [minHeight,
+// maxHeight]. A bound of 0 is unbounded.
+func filterTxResultsByHeight(results []*abci.TxResult, minHeight, maxHeight int64) []*abci.TxResult {
+	filtered := results[:0]
+	for _, r := range results {
+		if r.Height < minHeight || (maxHeight > 0 && r.Height > maxHeight) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// TxSearchStream searches transactions like TxSearch, sorted by height. Over
+// the WebSocket, the results are not paginated: each transaction is written as
+// a separate response to the request, in order, and the final response only
+// carries the total count, so that clients don't have to buffer large result
+// sets. Queries matching more than maxTxSearchStreamResults transactions are
+// rejected. Over HTTP, the transactions are returned as a page, as by TxSearch.
+// More: https://docs.tendermint.com/v0.34/rpc/#/Info/tx_search_stream
+func TxSearchStream(
+	ctx *rpctypes.Context,
+	query string,
+	prove bool,
+	pagePtr, perPagePtr *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	if ctx.WSConn == nil || ctx.JSONReq == nil {
+		return TxSearch(ctx, query, prove, pagePtr, perPagePtr, orderBy, false, "", false, "", false, 0, 0, "")
+	}
+
+	// if index is disabled, return error
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil, errors.New("transaction indexing is disabled")
+	} else if len(query) > maxQueryLength {
+		return nil, errors.New("maximum query length exceeded")
+	}
+
+	q, err := tmquery.New(query)
+	if err != nil {
+		return nil, err
+	}
 	results, err := env.TxIndexer.Search(ctx.Context(), q)
 	if err != nil {
 		return nil, err
 	}
+	if len(results) > maxTxSearchStreamResults {
+		return nil, fmt.Errorf("too many results: %d, max: %d", len(results), maxTxSearchStreamResults)
+	}
+	if err := sortTxResults(results, orderBy); err != nil {
+		return nil, err
+	}
+
+	base := env.BlockStore.Base()
+	blocks := newBlockCache(maxBlockCacheBytes)
+	for _, r := range results {
+		tx, err := newResultTx(ctx.Context(), blocks, base, r, prove, false)
+		if err != nil {
+			return nil, err
+		}
+		resp := rpctypes.NewRPCSuccessResponse(ctx.JSONReq.ID, tx)
+		if err := ctx.WSConn.WriteRPCResponse(ctx.Context(), resp); err != nil {
+			return nil, fmt.Errorf("streaming tx %X: %w", tx.Hash, err)
+		}
+	}
+	return &ctypes.ResultTxSearch{Txs: []*ctypes.ResultTx{}, TotalCount: len(results)}, nil
+}
+
+// txMatchInfo returns the attributes of the transaction which satisfy a
+// condition of the query: its indexed event attributes, along with the tx.hash
+// and tx.height keys the indexer adds. The events excluded from the results
+// by rpc.excluded_event_types are not reported.
+func txMatchInfo(q *tmquery.Query, tx *ctypes.ResultTx) ([]ctypes.TxMatch, error) {
+	events := map[string][]string{
+		types.TxHashKey:   {fmt.Sprintf("%X", tx.Hash)},
+		types.TxHeightKey: {strconv.FormatInt(tx.Height, 10)},
+	}
+	for _, event := range tx.TxResult.Events {
+		for _, attr := range event.Attributes {
+			if !attr.GetIndex() {
+				continue
+			}
+			key := event.Type + "." + string(attr.Key)
+			events[key] = append(events[key], string(attr.Value))
+		}
+	}
+
+	matched, err := q.MatchedAttributes(events)
+	if err != nil {
+		return nil, err
+	}
+	info := make([]ctypes.TxMatch, len(matched))
+	for i, m := range matched {
+		info[i] = ctypes.TxMatch{Key: m.CompositeKey, Value: m.Value}
+	}
+	return info, nil
+}
+
+// TxSearchByHashes returns the transactions with the given hashes (maximum
+// ?per_page entries) and the total count, like TxSearch does for a query.
+// Hashes which are not found are left out of the results and the total count.
+func TxSearchByHashes(
+	ctx *rpctypes.Context,
+	hashes []bytes.HexBytes,
+	prove bool,
+	pagePtr, perPagePtr *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	// if index is disabled, return error
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil, errors.New("transaction indexing is disabled")
+	} else if len(hashes) > maxTxSearchHashes {
+		return nil, fmt.Errorf("too many hashes: %d, max: %d", len(hashes), maxTxSearchHashes)
+	}
+
+	seen := make(map[string]struct{}, len(hashes))
+	results := make([]*abci.TxResult, 0, len(hashes))
+	for _, hash := range hashes {
+		if _, ok := seen[string(hash)]; ok {
+			continue
+		}
+		seen[string(hash)] = struct{}{}
+
+		r, err := env.TxIndexer.Get(hash)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			results = append(results, r)
+		}
+	}
 
 	// sort results (must be done before pagination)
+	if err := sortTxResults(results, orderBy); err != nil {
+		return nil, err
+	}
+
+	return paginateTxResults(ctx.Context(), results, prove, false, pagePtr, perPagePtr)
+}
+
+// TxBatch returns the transactions with the given hashes (at most max_per_page
+// hashes), in the order of the hashes. Unlike Tx, a hash which is not found
+// doesn't fail the request, but is reported as not found.
+// More: https://docs.tendermint.com/v0.34/rpc/#/Info/tx_batch
+func TxBatch(ctx *rpctypes.Context, hashes []bytes.HexBytes, prove bool) (*ctypes.ResultTxBatch, error) {
+	// if index is disabled, return error
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil, errors.New("transaction indexing is disabled")
+	} else if len(hashes) > maxPerPage {
+		return nil, fmt.Errorf("too many hashes: %d, max: %d", len(hashes), maxPerPage)
+	}
+
+	entries := make([]*ctypes.TxBatchEntry, len(hashes))
+	blocks := newBlockCache(maxBlockCacheBytes)
+	for i, hash := range hashes {
+		r, err := env.TxIndexer.Get(hash)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = &ctypes.TxBatchEntry{Hash: hash}
+		if r == nil {
+			continue
+		}
+
+		var proof types.TxProof
+		if prove {
+			if proof, err = proveTx(ctx.Context(), blocks, r.Height, r.Index); err != nil {
+				return nil, err
+			}
+		}
+		entries[i].Found = true
+		entries[i].Tx = &ctypes.ResultTx{
+			Hash:     hash,
+			Height:   r.Height,
+			Index:    r.Index,
+			TxResult: stripExcludedEvents(r.Result),
+			Tx:       r.Tx,
+			Proof:    proof,
+		}
+	}
+	return &ctypes.ResultTxBatch{Txs: entries}, nil
+}
+
+// proveTx returns the proof of the tx at the given index of the block at the
+// given height, loaded through the cache of the request. ErrBlockPruned is
+// returned if the block, or one of its parts, is missing, and the error of
+// ctx if it's done, e.g. because the client disconnected: requests proving
+// many txs then stop early.
+func proveTx(ctx context.Context, blocks *blockCache, height int64, index uint32) (types.TxProof, error) {
+	if err := ctx.Err(); err != nil {
+		return types.TxProof{}, fmt.Errorf("cannot prove tx: %w", err)
+	}
+	block := blocks.LoadBlock(height)
+	if block == nil {
+		return types.TxProof{}, fmt.Errorf("cannot prove tx: %w",
+			ErrBlockPruned{Height: height, Base: env.BlockStore.Base()})
+	}
+	return block.Data.Txs.Proof(int(index)), nil // XXX: overflow on 32-bit machines
+}
+
+// sortTxResults sorts the results by height and index, in the order given by
+// orderBy ("asc" or "desc", empty meaning "asc").
+func sortTxResults(results []*abci.TxResult, orderBy string) error {
 	switch orderBy {
 	case "desc":
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Height == results[j].Height {
-				return results[i].Index > results[j].Index
-			}
-			return results[i].Height > results[j].Height
-		})
+		sort.Slice(results, func(i, j int) bool { return txResultLess(results[j], results[i]) })
 	case "asc", "":
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Height == results[j].Height {
-				return results[i].Index < results[j].Index
+		sort.Slice(results, func(i, j int) bool { return txResultLess(results[i], results[j]) })
+	default:
+		return errors.New("expected order_by to be either `asc` or `desc` or empty")
+	}
+	return nil
+}
+
+// sortTxResultsByTimeAttr sorts the results chronologically by the value of an
+// indexed time attribute, then by height & index. orderBy is the composite key
+// of the attribute (e.g. "transfer.time"), optionally followed by the order,
+// "asc" or "desc". Every result must have a valid value for the attribute.
+func sortTxResultsByTimeAttr(results []*abci.TxResult, orderBy string) error {
+	fields := strings.Fields(orderBy)
+	if len(fields) == 0 || len(fields) > 2 {
+		return errors.New("expected order_by to be the composite key of a time attribute, " +
+			"optionally followed by `asc` or `desc`")
+	}
+	key, desc := fields[0], false
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return errors.New("expected the order of order_by to be either `asc` or `desc`")
+		}
+	}
+
+	times := make(map[*abci.TxResult]time.Time, len(results))
+	for _, r := range results {
+		t, err := txTimeAttr(r, key)
+		if err != nil {
+			return err
+		}
+		times[r] = t
+	}
+
+	less := func(a, b *abci.TxResult) bool {
+		ta, tb := times[a], times[b]
+		if !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return txResultLess(a, b)
+	}
+	if desc {
+		sort.Slice(results, func(i, j int) bool { return less(results[j], results[i]) })
+	} else {
+		sort.Slice(results, func(i, j int) bool { return less(results[i], results[j]) })
+	}
+	return nil
+}
+
+// sortTxResultsByEventAttr sorts the results by the value of an indexed event
+// attribute, then by height & index. orderByEvent is the composite key of the
+// attribute (e.g. "transfer.amount"), optionally followed by the order, "asc"
+// or "desc". Integer values are compared numerically, and come before the
+// other values, which are compared lexicographically. The results without the
+// attribute come last, whatever the order.
+func sortTxResultsByEventAttr(results []*abci.TxResult, orderByEvent string) error {
+	fields := strings.Fields(orderByEvent)
+	if len(fields) == 0 || len(fields) > 2 {
+		return errors.New("expected order_by_event to be the composite key of an event attribute, " +
+			"optionally followed by `asc` or `desc`")
+	}
+	key, desc := fields[0], false
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return errors.New("expected the order of order_by_event to be either `asc` or `desc`")
+		}
+	}
+
+	values := make(map[*abci.TxResult]txAttrValue, len(results))
+	for _, r := range results {
+		values[r] = newTxAttrValue(r, key)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := values[results[i]], values[results[j]]
+		if a.present != b.present {
+			return a.present
+		}
+		if c := a.compare(b); c != 0 {
+			return (c < 0) != desc
+		}
+		if desc {
+			return txResultLess(results[j], results[i])
+		}
+		return txResultLess(results[i], results[j])
+	})
+	return nil
+}
+
+// txAttrValue is the value of an event attribute of a tx, by which results
+// are sorted.
+type txAttrValue struct {
+	present bool
+	value   string
+	number  *big.Int // nil if the value isn't an integer
+}
+
+// newTxAttrValue returns the value of the first indexed event attribute of the
+// tx with the given composite key.
+func newTxAttrValue(r *abci.TxResult, key string) txAttrValue {
+	for _, event := range r.Result.Events {
+		for _, attr := range event.Attributes {
+			if !attr.GetIndex() || event.Type+"."+string(attr.Key) != key {
+				continue
 			}
-			return results[i].Height < results[j].Height
-		})
+			v := txAttrValue{present: true, value: string(attr.Value)}
+			if n, ok := new(big.Int).SetString(v.value, 10); ok {
+				v.number = n
+			}
+			return v
+		}
+	}
+	return txAttrValue{}
+}
+
+// compare orders the integers numerically, before the other values, which are
+// ordered lexicographically.
+func (v txAttrValue) compare(o txAttrValue) int {
+	switch {
+	case v.number != nil && o.number != nil:
+		return v.number.Cmp(o.number)
+	case v.number != nil:
+		return -1
+	case o.number != nil:
+		return 1
 	default:
-		return nil, errors.New("expected order_by to be either `asc` or `desc` or empty")
+		return strings.Compare(v.value, o.value)
+	}
+}
+
+// txTimeAttr returns the value of the indexed time attribute with the given
+// composite key of a transaction.
+func txTimeAttr(r *abci.TxResult, key string) (time.Time, error) {
+	for _, event := range r.Result.Events {
+		for _, attr := range event.Attributes {
+			if event.Type+"."+string(attr.Key) != key {
+				continue
+			}
+			if !attr.GetIndex() {
+				return time.Time{}, fmt.Errorf("attribute %s of tx %X is not indexed", key, types.TxHash(r.Tx))
+			}
+			t, err := time.Parse(tmquery.TimeLayout, string(attr.Value))
+			if err != nil {
+				return time.Time{}, fmt.Errorf("attribute %s of tx %X is not a time: %w", key, types.TxHash(r.Tx), err)
+			}
+			return t, nil
+		}
 	}
+	return time.Time{}, fmt.Errorf("tx %X has no attribute %s", types.TxHash(r.Tx), key)
+}
 
-	// paginate results
+// txResultLess orders results by height, then index. Results should never share
+// both, but a faulty indexer could produce such entries: they are then ordered
+// by tx hash, so that the order, and thus pagination, remains deterministic.
+func txResultLess(a, b *abci.TxResult) bool {
+	if a.Height != b.Height {
+		return a.Height < b.Height
+	}
+	if a.Index != b.Index {
+		return a.Index < b.Index
+	}
+	return string(types.Tx(a.Tx).Hash()) < string(types.Tx(b.Tx).Hash())
+}
+
+// paginateTxResults returns the requested page of the sorted results, with
+// the proof of each transaction if prove is true. Proving a transaction whose
+// block has been pruned fails, unless skipPrunedProofs is true, in which case
+// its proof is left empty.
+func paginateTxResults(
+	ctx context.Context,
+	results []*abci.TxResult,
+	prove, skipPrunedProofs bool,
+	pagePtr, perPagePtr *int,
+) (*ctypes.ResultTxSearch, error) {
 	totalCount := len(results)
 	perPage := validatePerPage(perPagePtr)
 
@@ -111,25 +756,52 @@ func TxSearch(
 	skipCount := validateSkipCount(page, perPage)
 	pageSize := tmmath.MinInt(perPage, totalCount-skipCount)
 
+	base := env.BlockStore.Base()
+	blocks := newBlockCache(maxBlockCacheBytes)
 	apiResults := make([]*ctypes.ResultTx, 0, pageSize)
 	for i := skipCount; i < skipCount+pageSize; i++ {
-		r := results[i]
-
-		var proof types.TxProof
-		if prove {
-			block := env.BlockStore.LoadBlock(r.Height)
-			proof = block.Data.Txs.Proof(int(r.Index)) // XXX: overflow on 32-bit machines
+		tx, err := newResultTx(ctx, blocks, base, results[i], prove, skipPrunedProofs)
+		if err != nil {
+			return nil, err
 		}
-
-		apiResults = append(apiResults, &ctypes.ResultTx{
-			Hash:     types.Tx(r.Tx).Hash(),
-			Height:   r.Height,
-			Index:    r.Index,
-			TxResult: r.Result,
-			Tx:       r.Tx,
-			Proof:    proof,
-		})
+		apiResults = append(apiResults, tx)
 	}
 
 	return &ctypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}, nil
 }
+
+// newResultTx returns the result of a tx, with its proof if prove is true.
+// base is the base height of the block store: proving a tx below it fails,
+// unless skipPrunedProofs is true, in which case its proof is left empty.
+func newResultTx(
+	ctx context.Context,
+	blocks *blockCache,
+	base int64,
+	r *abci.TxResult,
+	prove, skipPrunedProofs bool,
+) (*ctypes.ResultTx, error) {
+	var (
+		proof types.TxProof
+		err   error
+	)
+	switch {
+	case !prove:
+	case r.Height < base && skipPrunedProofs:
+	case r.Height < base:
+		return nil, fmt.Errorf("cannot prove tx: %w", ErrBlockPruned{Height: r.Height, Base: base})
+	default:
+		proof, err = proveTx(ctx, blocks, r.Height, r.Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ctypes.ResultTx{
+		Hash:     types.TxHash(r.Tx),
+		Height:   r.Height,
+		Index:    r.Index,
+		TxResult: stripExcludedEvents(r.Result),
+		Tx:       r.Tx,
+		Proof:    proof,
+	}, nil
+}
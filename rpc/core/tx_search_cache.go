@@ -0,0 +1,126 @@
+package core
+
+import (
+	"container/list"
+	"fmt"
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// txSearchCache is an LRU cache of tx_search results. An entry is served until
+// its TTL expires or the block store height changes, since the results of a
+// query may change with every new block.
+type txSearchCache struct {
+	mtx      tmsync.Mutex
+	size     int
+	ttl      time.Duration
+	cacheMap map[string]*list.Element
+	list     *list.List
+}
+
+type txSearchCacheEntry struct {
+	key     string
+	height  int64
+	expires time.Time
+	result  *ctypes.ResultTxSearch
+}
+
+func newTxSearchCache(size int, ttl time.Duration) *txSearchCache {
+	return &txSearchCache{
+		size:     size,
+		ttl:      ttl,
+		cacheMap: make(map[string]*list.Element, size),
+		list:     list.New(),
+	}
+}
+
+// txSearchCacheKey returns the key of the results of a tx_search call.
+func txSearchCacheKey(
+	query string,
+	prove bool,
+	pagePtr, perPagePtr *int,
+	orderBy string,
+	skipPrunedProofs bool,
+	sortBy string,
+	includeMatchInfo bool,
+	cursor string,
+	countOnly bool,
+	minHeight, maxHeight int64,
+	orderByEvent string,
+) string {
+	page, perPage := 0, 0
+	if pagePtr != nil {
+		page = *pagePtr
+	}
+	if perPagePtr != nil {
+		perPage = *perPagePtr
+	}
+	return fmt.Sprintf("%q/%t/%d/%d/%q/%t/%q/%t/%q/%t/%d/%d/%q",
+		query, prove, page, perPage, orderBy, skipPrunedProofs, sortBy, includeMatchInfo, cursor, countOnly,
+		minHeight, maxHeight, orderByEvent)
+}
+
+// Get returns the result cached for the key, if it was cached at the given
+// height and hasn't expired.
+func (c *txSearchCache) Get(key string, height int64, now time.Time) (*ctypes.ResultTxSearch, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.cacheMap[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*txSearchCacheEntry)
+	if entry.height != height || !now.Before(entry.expires) {
+		delete(c.cacheMap, key)
+		c.list.Remove(e)
+		return nil, false
+	}
+	c.list.MoveToBack(e)
+	return entry.result, true
+}
+
+// Push caches the result for the key, evicting the least recently used entry
+// if the cache is full.
+func (c *txSearchCache) Push(key string, height int64, now time.Time, result *ctypes.ResultTxSearch) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry := &txSearchCacheEntry{key: key, height: height, expires: now.Add(c.ttl), result: result}
+	if e, ok := c.cacheMap[key]; ok {
+		e.Value = entry
+		c.list.MoveToBack(e)
+		return
+	}
+
+	if c.list.Len() >= c.size {
+		front := c.list.Front()
+		if front != nil {
+			delete(c.cacheMap, front.Value.(*txSearchCacheEntry).key)
+			c.list.Remove(front)
+		}
+	}
+	c.cacheMap[key] = c.list.PushBack(entry)
+}
+
+// Len returns the number of cached results, including expired ones which
+// haven't been evicted yet.
+func (c *txSearchCache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.list.Len()
+}
+
+// InitTxSearchCache creates the tx_search cache if it is enabled by the RPC
+// config.
+func InitTxSearchCache() {
+	if env.Metrics == nil {
+		env.Metrics = NopMetrics()
+	}
+	if env.txSearchCache != nil || env.Config.TxSearchCacheSize <= 0 || env.Config.TxSearchCacheTTL <= 0 {
+		return
+	}
+	env.txSearchCache = newTxSearchCache(env.Config.TxSearchCacheSize, env.Config.TxSearchCacheTTL)
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tm-db"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/state/txindex/kv"
+	"github.com/tendermint/tendermint/types"
+)
+
+// counter is a metrics.Counter which can be read.
+type counter struct {
+	value float64
+}
+
+func (c *counter) With(...string) metrics.Counter { return c }
+func (c *counter) Add(delta float64)              { c.value += delta }
+
+func TestTxSearchCacheEviction(t *testing.T) {
+	now := time.Now()
+	c := newTxSearchCache(2, time.Second)
+	res1, res2, res3 := &ctypes.ResultTxSearch{TotalCount: 1}, &ctypes.ResultTxSearch{TotalCount: 2},
+		&ctypes.ResultTxSearch{TotalCount: 3}
+	c.Push("a", 1, now, res1)
+	c.Push("b", 1, now, res2)
+
+	// a is the most recently used, so b is evicted
+	res, ok := c.Get("a", 1, now)
+	require.True(t, ok)
+	assert.Equal(t, res1, res)
+	c.Push("c", 1, now, res3)
+	_, ok = c.Get("b", 1, now)
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	// entries expire after the TTL
+	_, ok = c.Get("c", 1, now.Add(time.Second))
+	assert.False(t, ok)
+
+	// and are invalidated by a new height
+	_, ok = c.Get("a", 2, now)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestTxSearchCached(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	require.NoError(t, txIndexer.Index(&abci.TxResult{Height: 1, Index: 0, Tx: types.Tx("tx-1")}))
+
+	hits, misses := &counter{}, &counter{}
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 1}
+	env.Config.TxSearchCacheSize = 10
+	env.Config.TxSearchCacheTTL = time.Minute
+	env.Metrics = &Metrics{TxSearchCacheHits: hits, TxSearchCacheMisses: misses}
+	InitTxSearchCache()
+
+	res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 1)
+
+	// the repeated query is served from the cache, and doesn't see a tx which
+	// has been indexed since
+	require.NoError(t, txIndexer.Index(&abci.TxResult{Height: 1, Index: 1, Tx: types.Tx("tx-2")}))
+	res, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 1)
+	assert.EqualValues(t, 1, hits.value)
+
+	// another order is another query
+	res, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, "desc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+
+	// a new block invalidates the cached results
+	env.BlockStore = mockBlockStore{height: 2}
+	res, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	assert.EqualValues(t, 1, hits.value)
+	assert.EqualValues(t, 3, misses.value)
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// txSearchCursor is the position of the last transaction of a page of
+// tx_search results sorted by height, from which the next page resumes.
+type txSearchCursor struct {
+	height int64
+	index  uint32
+	desc   bool
+}
+
+// encodeTxSearchCursor returns the opaque cursor of the page ending with tx.
+func encodeTxSearchCursor(tx *ctypes.ResultTx, desc bool) string {
+	order := "asc"
+	if desc {
+		order = "desc"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d/%d/%s", tx.Height, tx.Index, order)))
+}
+
+// decodeTxSearchCursor parses a cursor returned by encodeTxSearchCursor, and
+// checks that it was returned for the same order.
+func decodeTxSearchCursor(cursor, orderBy string) (*txSearchCursor, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var (
+		c     txSearchCursor
+		order string
+	)
+	if n, err := fmt.Sscanf(string(bz), "%d/%d/%s", &c.height, &c.index, &order); err != nil || n != 3 ||
+		(order != "asc" && order != "desc") {
+		return nil, errors.New("invalid cursor")
+	}
+	c.desc = order == "desc"
+
+	if orderBy == "" {
+		orderBy = "asc"
+	}
+	if orderBy != order {
+		return nil, fmt.Errorf("cursor was returned for order_by %q, not %q", order, orderBy)
+	}
+	return &c, nil
+}
+
+// after returns the results which come after the cursor, in its order. The
+// results are not expected to be sorted.
+func (c *txSearchCursor) after(results []*abci.TxResult) []*abci.TxResult {
+	pos := &abci.TxResult{Height: c.height, Index: c.index}
+	filtered := results[:0]
+	for _, r := range results {
+		if (!c.desc && txPositionLess(pos, r)) || (c.desc && txPositionLess(r, pos)) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// txPositionLess orders results by height, then index.
+func txPositionLess(a, b *abci.TxResult) bool {
+	if a.Height != b.Height {
+		return a.Height < b.Height
+	}
+	return a.Index < b.Index
+}
@@ -0,0 +1,822 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tm-db"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/bytes"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	blockidxkv "github.com/tendermint/tendermint/state/indexer/block/kv"
+	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/state/txindex/kv"
+	"github.com/tendermint/tendermint/state/txindex/null"
+	"github.com/tendermint/tendermint/store"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestTxSearchSkipPrunedProofs(t *testing.T) {
+	const base, height = 3, 5
+
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	blocks := make(map[int64]*types.Block)
+	for h := int64(1); h <= height; h++ {
+		tx := types.Tx(fmt.Sprintf("tx-%d", h))
+		require.NoError(t, txIndexer.Index(&abci.TxResult{Height: h, Index: 0, Tx: tx}))
+		if h >= base {
+			blocks[h] = &types.Block{Data: types.Data{Txs: types.Txs{tx}}}
+		}
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = prunedBlockStore{
+		mockBlockStore: mockBlockStore{height: height},
+		base:           base,
+		blocks:         blocks,
+	}
+
+	// proving pruned heights fails the whole request
+	_, err := TxSearch(&rpctypes.Context{}, "tx.height >= 1", true, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.Error(t, err)
+
+	// unless pruned proofs are skipped
+	res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 1", true, nil, nil, "asc", true, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, height)
+	for _, r := range res.Txs {
+		if r.Height < base {
+			assert.Empty(t, r.Proof.Data, "height %d", r.Height)
+			continue
+		}
+		if assert.EqualValues(t, r.Tx, r.Proof.Data, "height %d", r.Height) {
+			assert.NoError(t, r.Proof.Validate(blocks[r.Height].Data.Txs.Hash()))
+		}
+	}
+
+	// retained heights can still be proven alone
+	res, err = TxSearch(&rpctypes.Context{}, "tx.height >= 3", true, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, height-base+1)
+
+	// the valid page range is reported
+	page := 2
+	_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, &page, nil, "asc", false, "", false, "", false, 0, 0, "")
+	assert.Equal(t, ErrPageOutOfRange{Page: 2, MinPage: 1, MaxPage: 1}, err)
+}
+
+func TestTxNotFoundBelowMinIndexedHeight(t *testing.T) {
+	env = &Environment{}
+	env.TxIndexer = kv.NewTxIndex(dbm.NewMemDB())
+	env.BlockIndexer = blockidxkv.New(dbm.NewMemDB())
+
+	hash := types.Tx("missing").Hash()
+
+	// nothing indexed yet
+	_, err := Tx(&rpctypes.Context{}, hash, false)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "predate")
+
+	// indexing starts from the initial height
+	require.NoError(t, env.BlockIndexer.Index(types.EventDataNewBlockHeader{Header: types.Header{Height: 1}}))
+	_, err = Tx(&rpctypes.Context{}, hash, false)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "predate")
+
+	// indexing was enabled late
+	env.BlockIndexer = blockidxkv.New(dbm.NewMemDB())
+	require.NoError(t, env.BlockIndexer.Index(types.EventDataNewBlockHeader{Header: types.Header{Height: 10}}))
+	_, err = Tx(&rpctypes.Context{}, hash, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "predate index availability")
+	assert.Contains(t, err.Error(), "below 10")
+
+	// unless the chain itself starts there
+	env.GenDoc = &types.GenesisDoc{InitialHeight: 10}
+	_, err = Tx(&rpctypes.Context{}, hash, false)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "predate")
+}
+
+func TestTxResultsExcludedEventTypes(t *testing.T) {
+	tx := types.Tx("tx")
+	events := []abci.Event{
+		{Type: "transfer", Attributes: []abci.EventAttribute{{Key: []byte("sender"), Value: []byte("addr"), Index: true}}},
+		{Type: "debug", Attributes: []abci.EventAttribute{{Key: []byte("trace"), Value: []byte("x"), Index: true}}},
+	}
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	require.NoError(t, txIndexer.Index(&abci.TxResult{
+		Height: 1,
+		Tx:     tx,
+		Result: abci.ResponseDeliverTx{Events: events},
+	}))
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 1}
+	env.Config.ExcludedEventTypes = []string{"debug"}
+
+	// the excluded events are still indexed and matched
+	res, err := TxSearch(&rpctypes.Context{}, "debug.trace = 'x'", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 1)
+	assert.Equal(t, events[:1], res.Txs[0].TxResult.Events)
+
+	r, err := Tx(&rpctypes.Context{}, tx.Hash(), false)
+	require.NoError(t, err)
+	assert.Equal(t, events[:1], r.TxResult.Events)
+
+	// the stored result is left untouched
+	env.Config.ExcludedEventTypes = nil
+	r, err = Tx(&rpctypes.Context{}, tx.Hash(), false)
+	require.NoError(t, err)
+	assert.Equal(t, events, r.TxResult.Events)
+}
+
+func TestTxCountByHeight(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for h := int64(1); h <= 5; h++ {
+		for i := int64(0); i < h%3; i++ {
+			require.NoError(t, txIndexer.Index(&abci.TxResult{
+				Height: h, Index: uint32(i), Tx: types.Tx(fmt.Sprintf("tx-%d-%d", h, i))}))
+		}
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 5}
+
+	// defaults to all the heights
+	res, err := TxCountByHeight(&rpctypes.Context{}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultTxCountByHeight{MinHeight: 1, MaxHeight: 5, Counts: []int{1, 2, 0, 1, 2}}, res)
+
+	// the max height is limited to the latest height
+	res, err = TxCountByHeight(&rpctypes.Context{}, 2, 100)
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultTxCountByHeight{MinHeight: 2, MaxHeight: 5, Counts: []int{2, 0, 1, 2}}, res)
+
+	// and the range to maxTxCountHeights
+	env.BlockStore = mockBlockStore{height: maxTxCountHeights + 10}
+	res, err = TxCountByHeight(&rpctypes.Context{}, 1, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 11, res.MinHeight)
+	assert.Len(t, res.Counts, maxTxCountHeights)
+
+	_, err = TxCountByHeight(&rpctypes.Context{}, 4, 3)
+	require.Error(t, err)
+
+	env.TxIndexer = &null.TxIndex{}
+	_, err = TxCountByHeight(&rpctypes.Context{}, 0, 0)
+	require.Error(t, err)
+}
+
+// prunedBlockStore is a mockBlockStore which only retains the given blocks,
+// from the given base height.
+type prunedBlockStore struct {
+	mockBlockStore
+	base   int64
+	blocks map[int64]*types.Block
+}
+
+func (store prunedBlockStore) Base() int64                         { return store.base }
+func (store prunedBlockStore) LoadBlock(height int64) *types.Block { return store.blocks[height] }
+
+func TestSortTxResultsCollidingPositions(t *testing.T) {
+	// a faulty indexer returned several txs at the same height and index
+	results := []*abci.TxResult{
+		{Height: 2, Index: 0, Tx: types.Tx("c")},
+		{Height: 1, Index: 1, Tx: types.Tx("b")},
+		{Height: 1, Index: 1, Tx: types.Tx("a")},
+		{Height: 1, Index: 1, Tx: types.Tx("d")},
+		{Height: 1, Index: 0, Tx: types.Tx("e")},
+	}
+
+	for _, orderBy := range []string{"asc", "desc"} {
+		var expected []*abci.TxResult
+		for i := 0; i < 10; i++ {
+			shuffled := make([]*abci.TxResult, len(results))
+			for j, k := range tmrand.Perm(len(results)) {
+				shuffled[j] = results[k]
+			}
+			require.NoError(t, sortTxResults(shuffled, orderBy))
+			if expected == nil {
+				expected = shuffled
+				continue
+			}
+			require.Equal(t, expected, shuffled, "order_by %s", orderBy)
+		}
+
+		first, last := expected[0], expected[len(expected)-1]
+		if orderBy == "desc" {
+			first, last = last, first
+		}
+		assert.EqualValues(t, "e", first.Tx)
+		assert.EqualValues(t, "c", last.Tx)
+		for i := 1; i < len(expected); i++ {
+			if orderBy == "asc" {
+				assert.True(t, txResultLess(expected[i-1], expected[i]))
+			} else {
+				assert.True(t, txResultLess(expected[i], expected[i-1]))
+			}
+		}
+	}
+}
+
+func TestTxSearchSortByTimeAttr(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	// the app backdates the txs: the times are in the reverse order of the heights
+	times := []string{"2022-01-03T00:00:00Z", "2022-01-02T00:00:00Z", "2022-01-01T00:00:00Z", "2022-01-01T00:00:00Z"}
+	for i, tm := range times {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i + 1),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i+1)),
+			Result: abci.ResponseDeliverTx{Events: []abci.Event{
+				{Type: "transfer", Attributes: []abci.EventAttribute{
+					{Key: []byte("time"), Value: []byte(tm), Index: true},
+					{Key: []byte("memo"), Value: []byte(tm), Index: false},
+				}},
+			}},
+		}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 4}
+
+	heights := func(res *ctypes.ResultTxSearch) []int64 {
+		h := make([]int64, len(res.Txs))
+		for i, tx := range res.Txs {
+			h[i] = tx.Height
+		}
+		return h
+	}
+
+	testCases := []struct {
+		orderBy  string
+		expected []int64
+	}{
+		// the txs with the same time are sorted by height
+		{"transfer.time", []int64{3, 4, 2, 1}},
+		{"transfer.time asc", []int64{3, 4, 2, 1}},
+		{"transfer.time desc", []int64{1, 2, 4, 3}},
+	}
+	for _, tc := range testCases {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, tc.orderBy, false, "time-attr", false, "", false, 0, 0, "")
+		require.NoError(t, err, tc.orderBy)
+		assert.Equal(t, tc.expected, heights(res), tc.orderBy)
+	}
+
+	res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, "desc", false, "height", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 3, 2, 1}, heights(res))
+
+	for _, orderBy := range []string{"transfer.memo", "transfer.unknown", "transfer.time up", ""} {
+		_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, orderBy, false, "time-attr", false, "", false, 0, 0, "")
+		assert.Error(t, err, orderBy)
+	}
+	_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, nil, "asc", false, "size", false, "", false, 0, 0, "")
+	assert.Error(t, err)
+}
+
+func TestTxSearchMatchInfo(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i, amount := range []string{"5", "50"} {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i + 1),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i+1)),
+			Result: abci.ResponseDeliverTx{Events: []abci.Event{
+				{Type: "transfer", Attributes: []abci.EventAttribute{
+					{Key: []byte("sender"), Value: []byte("alice"), Index: true},
+					{Key: []byte("amount"), Value: []byte(amount), Index: true},
+					{Key: []byte("memo"), Value: []byte("alice"), Index: false},
+				}},
+			}},
+		}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 2}
+
+	res, err := TxSearch(&rpctypes.Context{}, "transfer.sender = 'alice'", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	assert.Nil(t, res.Txs[0].MatchInfo)
+
+	res, err = TxSearch(&rpctypes.Context{}, "transfer.sender = 'alice'", false, nil, nil, "asc", false, "", true, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	for _, tx := range res.Txs {
+		// the memo isn't indexed, so it's not reported
+		assert.Equal(t, []ctypes.TxMatch{{Key: "transfer.sender", Value: "alice"}}, tx.MatchInfo)
+	}
+
+	res, err = TxSearch(&rpctypes.Context{}, "tx.height >= 2 AND transfer.amount > 10", false, nil, nil, "asc", false, "", true, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 1)
+	assert.Equal(t, []ctypes.TxMatch{
+		{Key: "tx.height", Value: "2"},
+		{Key: "transfer.amount", Value: "50"},
+	}, res.Txs[0].MatchInfo)
+}
+
+func TestTxSearchSubstringAndRegexp(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i, memo := range []string{"payment for invoice 42", "refund", "invoice 7"} {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i + 1),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i+1)),
+			Result: abci.ResponseDeliverTx{Events: []abci.Event{
+				{Type: "transfer", Attributes: []abci.EventAttribute{
+					{Key: []byte("memo"), Value: []byte(memo), Index: true},
+				}},
+			}},
+		}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 3}
+
+	heights := func(res *ctypes.ResultTxSearch) []int64 {
+		hs := make([]int64, 0, len(res.Txs))
+		for _, tx := range res.Txs {
+			hs = append(hs, tx.Height)
+		}
+		return hs
+	}
+
+	res, err := TxSearch(&rpctypes.Context{}, "transfer.memo CONTAINS 'invoice'", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, heights(res))
+
+	res, err = TxSearch(&rpctypes.Context{}, "transfer.memo MATCHES 'invoice [0-9]+'", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, heights(res))
+
+	_, err = TxSearch(&rpctypes.Context{}, "transfer.memo MATCHES 'invoice ([0-9]+'", false, nil, nil, "asc", false, "", false, "", false, 0, 0, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid regular expression")
+}
+
+func TestTxSearchCursor(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i := 0; i < 5; i++ {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i/2 + 1),
+			Index:  uint32(i % 2),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i)),
+		}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 3}
+
+	type position struct {
+		height int64
+		index  uint32
+	}
+	pageThrough := func(orderBy string) []position {
+		var (
+			positions []position
+			cursor    string
+			perPage   = 2
+		)
+		for {
+			res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, &perPage, orderBy, false, "",
+				false, cursor, false, 0, 0, "")
+			require.NoError(t, err)
+			assert.Equal(t, 5, res.TotalCount)
+			for _, tx := range res.Txs {
+				positions = append(positions, position{tx.Height, tx.Index})
+			}
+			if res.NextCursor == "" {
+				return positions
+			}
+			cursor = res.NextCursor
+		}
+	}
+
+	assert.Equal(t, []position{{1, 0}, {1, 1}, {2, 0}, {2, 1}, {3, 0}}, pageThrough("asc"))
+	assert.Equal(t, []position{{3, 0}, {2, 1}, {2, 0}, {1, 1}, {1, 0}}, pageThrough("desc"))
+
+	// offset pagination returns the same cursor
+	perPage, page := 2, 1
+	res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, &page, &perPage, "", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, res.NextCursor)
+	cursor := res.NextCursor
+
+	_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, &perPage, "desc", false, "", false, cursor, false, 0, 0, "")
+	assert.Error(t, err, "conflicting order")
+	_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, &page, &perPage, "asc", false, "", false, cursor, false, 0, 0, "")
+	assert.Error(t, err, "cursor and page")
+	_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, &perPage, "asc", false, "time-attr", false,
+		cursor, false, 0, 0, "")
+	assert.Error(t, err, "cursor and time-attr")
+	_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 1", false, nil, &perPage, "asc", false, "", false, "x", false, 0, 0, "")
+	assert.Error(t, err, "invalid cursor")
+}
+
+// loadCountingBlockStore counts the blocks loaded, e.g. to prove txs.
+type loadCountingBlockStore struct {
+	mockBlockStore
+	loads int
+}
+
+func (store *loadCountingBlockStore) LoadBlock(height int64) *types.Block {
+	store.loads++
+	return &types.Block{Header: types.Header{Height: height}}
+}
+
+func TestTxSearchCountOnly(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i := 0; i < 5; i++ {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i + 1),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i)),
+		}))
+	}
+
+	blockStore := &loadCountingBlockStore{mockBlockStore: mockBlockStore{height: 5}}
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = blockStore
+
+	perPage := 2
+	res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 2", false, nil, &perPage, "asc", false, "", false, "", false, 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+
+	count, err := TxSearch(&rpctypes.Context{}, "tx.height >= 2", false, nil, &perPage, "asc", false, "", false, "", true, 0, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, res.TotalCount, count.TotalCount)
+	assert.Equal(t, 4, count.TotalCount)
+	assert.Empty(t, count.Txs)
+	assert.Empty(t, count.NextCursor)
+
+	_, err = TxSearch(&rpctypes.Context{}, "tx.height >= 2", true, nil, &perPage, "asc", false, "", false, "", true, 0, 0, "")
+	assert.Error(t, err)
+	assert.Zero(t, blockStore.loads, "no tx should be proven")
+}
+
+// txsBlockStore serves blocks with the given txs.
+type txsBlockStore struct {
+	mockBlockStore
+	txs map[int64]types.Txs
+}
+
+func (store txsBlockStore) LoadBlock(height int64) *types.Block {
+	txs, ok := store.txs[height]
+	if !ok {
+		return nil
+	}
+	return &types.Block{Header: types.Header{Height: height}, Data: types.Data{Txs: txs}}
+}
+
+func TestTxBatch(t *testing.T) {
+	txs := types.Txs{types.Tx("tx-1"), types.Tx("tx-2")}
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i, tx := range txs {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{Height: 1, Index: uint32(i), Tx: tx}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = txsBlockStore{mockBlockStore: mockBlockStore{height: 1}, txs: map[int64]types.Txs{1: txs}}
+
+	missing := types.Tx("missing").Hash()
+	hashes := []bytes.HexBytes{txs[1].Hash(), missing, txs[0].Hash()}
+	res, err := TxBatch(&rpctypes.Context{}, hashes, true)
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 3)
+
+	// the results are in the order of the hashes
+	for i, entry := range res.Txs {
+		assert.Equal(t, hashes[i], entry.Hash)
+	}
+	assert.True(t, res.Txs[0].Found)
+	assert.Equal(t, txs[1], res.Txs[0].Tx.Tx)
+	assert.NoError(t, res.Txs[0].Tx.Proof.Validate(txs.Hash()))
+	assert.False(t, res.Txs[1].Found)
+	assert.Nil(t, res.Txs[1].Tx)
+	assert.True(t, res.Txs[2].Found)
+	assert.Equal(t, uint32(0), res.Txs[2].Tx.Index)
+
+	res, err = TxBatch(&rpctypes.Context{}, hashes[:1], false)
+	require.NoError(t, err)
+	assert.Empty(t, res.Txs[0].Tx.Proof.Data)
+
+	_, err = TxBatch(&rpctypes.Context{}, make([]bytes.HexBytes, maxPerPage+1), false)
+	assert.Error(t, err)
+}
+
+func TestTxSearchHeightRange(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i := 0; i < 10; i++ {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i + 1),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i)),
+		}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 10}
+
+	search := func(minHeight, maxHeight int64) (*ctypes.ResultTxSearch, error) {
+		return TxSearch(&rpctypes.Context{}, "tx.height >= 2", false, nil, nil, "asc", false, "", false, "", false,
+			minHeight, maxHeight, "")
+	}
+	heights := func(res *ctypes.ResultTxSearch) []int64 {
+		h := make([]int64, len(res.Txs))
+		for i, tx := range res.Txs {
+			h[i] = tx.Height
+		}
+		return h
+	}
+
+	testCases := []struct {
+		minHeight, maxHeight int64
+		expected             []int64
+	}{
+		{0, 0, []int64{2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		// the bounds are inclusive
+		{4, 6, []int64{4, 5, 6}},
+		{5, 5, []int64{5}},
+		{8, 0, []int64{8, 9, 10}},
+		{0, 3, []int64{2, 3}},
+		{11, 20, []int64{}},
+	}
+	for _, tc := range testCases {
+		res, err := search(tc.minHeight, tc.maxHeight)
+		require.NoError(t, err, "[%d, %d]", tc.minHeight, tc.maxHeight)
+		assert.Equal(t, tc.expected, heights(res), "[%d, %d]", tc.minHeight, tc.maxHeight)
+		assert.Equal(t, len(tc.expected), res.TotalCount, "[%d, %d]", tc.minHeight, tc.maxHeight)
+	}
+
+	_, err := search(6, 4)
+	assert.Error(t, err)
+	_, err = search(-1, 4)
+	assert.Error(t, err)
+}
+
+func TestTxProveMissingBlockPart(t *testing.T) {
+	txs := []types.Tx{types.Tx("tx-1"), types.Tx("tx-2")}
+	block := types.MakeBlock(1, txs, &types.Commit{}, nil)
+	block.ProposerAddress = tmrand.Bytes(crypto.AddressSize)
+	blockDB := dbm.NewMemDB()
+	blockStore := store.NewBlockStore(blockDB)
+	blockStore.SaveBlock(block, block.MakePartSet(types.BlockPartSizeBytes), &types.Commit{Height: 1})
+
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	require.NoError(t, txIndexer.Index(&abci.TxResult{Height: 1, Index: 1, Tx: txs[1]}))
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = blockStore
+
+	res, err := Tx(&rpctypes.Context{}, txs[1].Hash(), true)
+	require.NoError(t, err)
+	require.NoError(t, res.Proof.Validate(block.DataHash))
+
+	// the part is deleted after the block meta was saved, as when pruning
+	require.NoError(t, blockDB.Delete([]byte("P:1:0")))
+	_, err = Tx(&rpctypes.Context{}, txs[1].Hash(), true)
+	var pruned ErrBlockPruned
+	require.ErrorAs(t, err, &pruned)
+	assert.Equal(t, ErrBlockPruned{Height: 1, Base: 1}, pruned)
+
+	// the tx itself is still served without a proof
+	_, err = Tx(&rpctypes.Context{}, txs[1].Hash(), false)
+	require.NoError(t, err)
+}
+
+// cancellingBlockStore cancels the request after serving the given number of
+// blocks.
+type cancellingBlockStore struct {
+	mockBlockStore
+	cancelAfter int
+	cancel      context.CancelFunc
+	loads       int
+}
+
+func (store *cancellingBlockStore) LoadBlock(height int64) *types.Block {
+	store.loads++
+	if store.loads == store.cancelAfter {
+		store.cancel()
+	}
+	return &types.Block{Header: types.Header{Height: height}, Data: types.Data{Txs: types.Txs{types.Tx("tx")}}}
+}
+
+func TestTxSearchProveCancelled(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i := 0; i < 10; i++ {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i + 1),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i)),
+		}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blockStore := &cancellingBlockStore{mockBlockStore: mockBlockStore{height: 10}, cancelAfter: 3, cancel: cancel}
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = blockStore
+
+	// the client disconnects while the third tx is proven
+	req := (&http.Request{}).WithContext(ctx)
+	_, err := TxSearch(&rpctypes.Context{HTTPReq: req}, "tx.height >= 1", true, nil, nil, "asc", false, "", false, "",
+		false, 0, 0, "")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, blockStore.loads, "no tx should be proven once the request is cancelled")
+}
+
+func TestTxSearchOrderByEvent(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	// the amounts are numbers, but for the last two txs, and the first tx has
+	// none
+	amounts := []string{"", "20", "100", "3", "20", "abc", "10stake"}
+	for i, amount := range amounts {
+		var attrs []abci.EventAttribute
+		if amount != "" {
+			attrs = append(attrs, abci.EventAttribute{Key: []byte("amount"), Value: []byte(amount), Index: true})
+		}
+		attrs = append(attrs, abci.EventAttribute{Key: []byte("sender"), Value: []byte("alice"), Index: true})
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i + 1),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i)),
+			Result: abci.ResponseDeliverTx{Events: []abci.Event{{Type: "transfer", Attributes: attrs}}},
+		}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: int64(len(amounts))}
+
+	search := func(page, perPage *int, orderByEvent string) (*ctypes.ResultTxSearch, error) {
+		return TxSearch(&rpctypes.Context{}, "transfer.sender = 'alice'", false, page, perPage, "", false, "", false,
+			"", false, 0, 0, orderByEvent)
+	}
+	heights := func(res *ctypes.ResultTxSearch) []int64 {
+		h := make([]int64, len(res.Txs))
+		for i, tx := range res.Txs {
+			h[i] = tx.Height
+		}
+		return h
+	}
+
+	testCases := []struct {
+		orderByEvent string
+		expected     []int64
+	}{
+		// numbers first, numerically, then the other values lexicographically,
+		// ties by height, and the txs without the attribute last
+		{"transfer.amount", []int64{4, 2, 5, 3, 7, 6, 1}},
+		{"transfer.amount asc", []int64{4, 2, 5, 3, 7, 6, 1}},
+		{"transfer.amount desc", []int64{6, 7, 3, 5, 2, 4, 1}},
+	}
+	for _, tc := range testCases {
+		res, err := search(nil, nil, tc.orderByEvent)
+		require.NoError(t, err, tc.orderByEvent)
+		assert.Equal(t, tc.expected, heights(res), tc.orderByEvent)
+		assert.Empty(t, res.NextCursor, tc.orderByEvent)
+	}
+
+	// the pages follow the order
+	var paged []int64
+	perPage := 3
+	for page := 1; page <= 3; page++ {
+		page := page
+		res, err := search(&page, &perPage, "transfer.amount desc")
+		require.NoError(t, err)
+		assert.Equal(t, len(amounts), res.TotalCount)
+		paged = append(paged, heights(res)...)
+	}
+	assert.Equal(t, []int64{6, 7, 3, 5, 2, 4, 1}, paged)
+
+	for _, orderByEvent := range []string{"transfer.amount up", "transfer.amount asc x"} {
+		_, err := search(nil, nil, orderByEvent)
+		assert.Error(t, err, orderByEvent)
+	}
+	_, err := TxSearch(&rpctypes.Context{}, "transfer.sender = 'alice'", false, nil, nil, "", false, "time-attr",
+		false, "", false, 0, 0, "transfer.amount")
+	assert.Error(t, err)
+}
+
+// recordingWSConn records the responses written to it.
+type recordingWSConn struct {
+	rpctypes.WSRPCConnection
+	responses []rpctypes.RPCResponse
+}
+
+func (c *recordingWSConn) WriteRPCResponse(_ context.Context, resp rpctypes.RPCResponse) error {
+	c.responses = append(c.responses, resp)
+	return nil
+}
+
+func (c *recordingWSConn) Context() context.Context { return context.Background() }
+
+func TestTxSearchStream(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for i := 0; i < 5; i++ {
+		require.NoError(t, txIndexer.Index(&abci.TxResult{
+			Height: int64(i/2 + 1),
+			Index:  uint32(i % 2),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i)),
+		}))
+	}
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 3}
+
+	type position struct {
+		height int64
+		index  uint32
+	}
+	testCases := []struct {
+		orderBy  string
+		expected []position
+	}{
+		{"asc", []position{{1, 0}, {1, 1}, {2, 0}, {2, 1}, {3, 0}}},
+		{"desc", []position{{3, 0}, {2, 1}, {2, 0}, {1, 1}, {1, 0}}},
+	}
+	for _, tc := range testCases {
+		conn := &recordingWSConn{}
+		ctx := &rpctypes.Context{
+			JSONReq: &rpctypes.RPCRequest{ID: rpctypes.JSONRPCIntID(7)},
+			WSConn:  conn,
+		}
+		perPage := 1
+		res, err := TxSearchStream(ctx, "tx.height >= 1", false, nil, &perPage, tc.orderBy)
+		require.NoError(t, err)
+
+		// every tx is streamed, in order, whatever the page size
+		assert.Equal(t, 5, res.TotalCount)
+		assert.Empty(t, res.Txs)
+		positions := make([]position, len(conn.responses))
+		for i, resp := range conn.responses {
+			assert.Equal(t, rpctypes.JSONRPCIntID(7), resp.ID)
+			tx := new(ctypes.ResultTx)
+			require.NoError(t, tmjson.Unmarshal(resp.Result, tx))
+			positions[i] = position{tx.Height, tx.Index}
+		}
+		assert.Equal(t, tc.expected, positions, tc.orderBy)
+	}
+
+	// HTTP callers get a page
+	perPage := 2
+	res, err := TxSearchStream(&rpctypes.Context{}, "tx.height >= 1", false, nil, &perPage, "desc")
+	require.NoError(t, err)
+	assert.Equal(t, 5, res.TotalCount)
+	require.Len(t, res.Txs, 2)
+	assert.Equal(t, int64(3), res.Txs[0].Height)
+}
+
+func TestTxSearchStreamMaxResults(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	batch := txindex.NewBatch(maxTxSearchStreamResults + 1)
+	for i := 0; i < maxTxSearchStreamResults+1; i++ {
+		require.NoError(t, batch.Add(&abci.TxResult{
+			Height: 1,
+			Index:  uint32(i),
+			Tx:     types.Tx(fmt.Sprintf("tx-%d", i)),
+		}))
+	}
+	require.NoError(t, txIndexer.AddBatch(batch))
+	require.NoError(t, txIndexer.Index(&abci.TxResult{Height: 2, Tx: types.Tx("tx-last")}))
+
+	env = &Environment{}
+	env.TxIndexer = txIndexer
+	env.BlockStore = mockBlockStore{height: 2}
+
+	conn := &recordingWSConn{}
+	ctx := &rpctypes.Context{
+		JSONReq: &rpctypes.RPCRequest{ID: rpctypes.JSONRPCIntID(7)},
+		WSConn:  conn,
+	}
+	_, err := TxSearchStream(ctx, "tx.height = 1", false, nil, nil, "")
+	require.Error(t, err)
+	assert.Empty(t, conn.responses)
+
+	// narrower queries are streamed
+	res, err := TxSearchStream(ctx, "tx.height = 2", false, nil, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.TotalCount)
+	assert.Len(t, conn.responses, 1)
+}
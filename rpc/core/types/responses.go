@@ -39,6 +39,12 @@ type ResultBlock struct {
 	Block   *types.Block  `json:"block"`
 }
 
+// ResultRawBlock is the encoded block, as reassembled from its parts.
+type ResultRawBlock struct {
+	BlockID types.BlockID `json:"block_id"`
+	Block   []byte        `json:"block"`
+}
+
 // Commit and Header
 type ResultCommit struct {
 	types.SignedHeader `json:"signed_header"`
@@ -197,12 +203,46 @@ type ResultTx struct {
 	TxResult abci.ResponseDeliverTx `json:"tx_result"`
 	Tx       types.Tx               `json:"tx"`
 	Proof    types.TxProof          `json:"proof,omitempty"`
+	// MatchInfo lists the attributes which satisfied the query of a search,
+	// if requested.
+	MatchInfo []TxMatch `json:"match_info,omitempty"`
+}
+
+// TxMatch is an event attribute of a transaction which satisfied a condition
+// of a tx_search query.
+type TxMatch struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 // Result of searching for txs
 type ResultTxSearch struct {
 	Txs        []*ResultTx `json:"txs"`
 	TotalCount int         `json:"total_count"`
+	// NextCursor, if not empty, resumes the search after the last of Txs.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ResultTxBatch is the result of looking up several txs by hash, in the
+// order of the hashes.
+type ResultTxBatch struct {
+	Txs []*TxBatchEntry `json:"txs"`
+}
+
+// TxBatchEntry is the result of looking up a tx of a batch. Tx is nil if the
+// tx is not found.
+type TxBatchEntry struct {
+	Hash  bytes.HexBytes `json:"hash"`
+	Found bool           `json:"found"`
+	Tx    *ResultTx      `json:"tx,omitempty"`
+}
+
+// ResultTxCountByHeight is the number of indexed txs at each height of
+// [MinHeight, MaxHeight]: Counts[i] is the count of height MinHeight+i.
+type ResultTxCountByHeight struct {
+	MinHeight int64 `json:"min_height"`
+	MaxHeight int64 `json:"max_height"`
+	Counts    []int `json:"counts"`
 }
 
 // ResultBlockSearch defines the RPC response type for a block search by events.
@@ -211,6 +251,35 @@ type ResultBlockSearch struct {
 	TotalCount int            `json:"total_count"`
 }
 
+// ResultMinIndexedHeight defines the RPC response type for the lowest indexed
+// height. Height is 0 if nothing has been indexed yet.
+type ResultMinIndexedHeight struct {
+	Height int64 `json:"height"`
+}
+
+// ResultRPCLimits defines the RPC response type for the limits enforced by
+// the RPC server.
+type ResultRPCLimits struct {
+	// pagination
+	DefaultPerPage int `json:"default_per_page"`
+	MaxPerPage     int `json:"max_per_page"`
+
+	// queries
+	MaxQueryLength           int `json:"max_query_length"`
+	MaxTxSearchHashes        int `json:"max_tx_search_hashes"`
+	MaxTxSearchStreamResults int `json:"max_tx_search_stream_results"`
+	MaxTxBatchHashes         int `json:"max_tx_batch_hashes"`
+	MaxTxCountHeights        int `json:"max_tx_count_heights"`
+
+	// requests
+	MaxBodyBytes   int64 `json:"max_body_bytes"`
+	MaxHeaderBytes int   `json:"max_header_bytes"`
+
+	// subscriptions
+	MaxSubscriptionClients    int `json:"max_subscription_clients"`
+	MaxSubscriptionsPerClient int `json:"max_subscriptions_per_client"`
+}
+
 // List of mempool txs
 type ResultUnconfirmedTxs struct {
 	Count      int        `json:"n_txs"`
@@ -219,6 +288,23 @@ type ResultUnconfirmedTxs struct {
 	Txs        []types.Tx `json:"txs"`
 }
 
+// Mempool txs sorted by age
+type ResultUnconfirmedTxsByAge struct {
+	Count int             `json:"n_txs"`
+	Total int             `json:"total"`
+	Txs   []UnconfirmedTx `json:"txs"`
+}
+
+// UnconfirmedTx is a mempool tx along with its age
+type UnconfirmedTx struct {
+	Hash     bytes.HexBytes `json:"hash"`
+	Size     int            `json:"size"`
+	Priority int64          `json:"priority"`
+	Height   int64          `json:"height"`
+	Time     time.Time      `json:"time"`
+	Age      time.Duration  `json:"age"`
+}
+
 // Info abci msg
 type ResultABCIInfo struct {
 	Response abci.ResponseInfo `json:"response"`
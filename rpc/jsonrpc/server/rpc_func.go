@@ -71,6 +71,21 @@ func NewWSRPCFunc(f interface{}, args string, options ...Option) *RPCFunc {
 	return newRPCFunc(f, args, options...)
 }
 
+// ArgNames returns the names of the arguments of the function.
+func (f *RPCFunc) ArgNames() []string {
+	return f.argNames
+}
+
+// ArgTypes returns the types of the arguments of the function, matching
+// ArgNames. It does not include the context variable common to all RPC
+// functions.
+func (f *RPCFunc) ArgTypes() []reflect.Type {
+	if len(f.args) == 0 {
+		return nil
+	}
+	return f.args[1:]
+}
+
 // cacheableWithArgs returns whether or not a call to this function is cacheable,
 // given the specified arguments.
 func (f *RPCFunc) cacheableWithArgs(args []reflect.Value) bool {
@@ -3,6 +3,7 @@ package types
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -239,13 +240,34 @@ func RPCInvalidParamsError(id jsonrpcid, err error) RPCResponse {
 }
 
 func RPCInternalError(id jsonrpcid, err error) RPCResponse {
-	return NewRPCErrorResponse(id, -32603, "Internal error", err.Error())
+	return NewRPCErrorResponse(id, -32603, "Internal error", errorData(err))
 }
 
 func RPCServerError(id jsonrpcid, err error) RPCResponse {
 	return NewRPCErrorResponse(id, -32000, "Server error", err.Error())
 }
 
+// ErrorWithData is implemented by errors which carry structured information,
+// so that clients can act upon them programmatically.
+type ErrorWithData interface {
+	error
+	// RPCErrorData returns the information, which is JSON-encoded into the
+	// data of the error response.
+	RPCErrorData() interface{}
+}
+
+// errorData returns the data of the error response for err: the JSON-encoded
+// information of an ErrorWithData, or the error message.
+func errorData(err error) string {
+	var dataErr ErrorWithData
+	if errors.As(err, &dataErr) {
+		if bz, jsonErr := json.Marshal(dataErr.RPCErrorData()); jsonErr == nil {
+			return string(bz)
+		}
+	}
+	return err.Error()
+}
+
 //----------------------------------------
 
 // WSRPCConnection represents a websocket connection.
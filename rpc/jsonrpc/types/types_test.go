@@ -81,3 +81,18 @@ func TestRPCError(t *testing.T) {
 			Message: "Badness",
 		}))
 }
+
+type dataError struct{}
+
+func (dataError) Error() string             { return "data error" }
+func (dataError) RPCErrorData() interface{} { return map[string]int{"max": 3} }
+
+func TestRPCInternalErrorData(t *testing.T) {
+	resp := RPCInternalError(JSONRPCIntID(1), errors.New("plain error"))
+	assert.Equal(t, "plain error", resp.Error.Data)
+
+	// structured data is returned even when wrapped
+	resp = RPCInternalError(JSONRPCIntID(1), fmt.Errorf("wrapped: %w", dataError{}))
+	assert.Equal(t, -32603, resp.Error.Code)
+	assert.JSONEq(t, `{"max":3}`, resp.Error.Data)
+}
@@ -15,6 +15,10 @@ type BlockIndexer interface {
 	// upon database query failure.
 	Has(height int64) (bool, error)
 
+	// MinIndexedHeight returns the lowest indexed height, or 0 if no height has
+	// been indexed yet. An error is returned upon database query failure.
+	MinIndexedHeight() (int64, error)
+
 	// Index indexes BeginBlock and EndBlock events for a given block by its height.
 	Index(types.EventDataNewBlockHeader) error
 
@@ -43,6 +43,36 @@ func (idx *BlockerIndexer) Has(height int64) (bool, error) {
 	return idx.store.Has(key)
 }
 
+// MinIndexedHeight returns the lowest indexed height, or 0 if no height has
+// been indexed yet. The value is maintained by Index, so this does not require
+// scanning the index.
+func (idx *BlockerIndexer) MinIndexedHeight() (int64, error) {
+	bz, err := idx.store.Get(minHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	if bz != nil {
+		return int64FromBytes(bz), nil
+	}
+
+	// The store may have been populated before the lowest height was tracked,
+	// in which case it is the first primary key as heights are encoded in order.
+	prefix, err := orderedcode.Append(nil, types.BlockHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	it, err := dbm.IteratePrefix(idx.store, prefix)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	if !it.Valid() {
+		return 0, it.Error()
+	}
+	return int64FromBytes(it.Value()), nil
+}
+
 // Index indexes BeginBlock and EndBlock events for a given block by its height.
 // The following is indexed:
 //
@@ -64,6 +94,17 @@ func (idx *BlockerIndexer) Index(bh types.EventDataNewBlockHeader) error {
 		return err
 	}
 
+	// keep track of the lowest indexed height
+	minHeight, err := idx.MinIndexedHeight()
+	if err != nil {
+		return fmt.Errorf("failed to get lowest indexed height: %w", err)
+	}
+	if minHeight == 0 || height < minHeight {
+		if err := batch.Set(minHeightKey, int64ToBytes(height)); err != nil {
+			return err
+		}
+	}
+
 	// 2. index BeginBlock events
 	if err := idx.indexEvents(batch, bh.ResultBeginBlock.Events, "begin_block", height); err != nil {
 		return fmt.Errorf("failed to index BeginBlock events: %w", err)
@@ -420,6 +461,99 @@ func (idx *BlockerIndexer) match(
 			return nil, err
 		}
 
+	case c.Op == query.OpMatches:
+		re, err := query.CompileMatches(c.Operand.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		prefix, err := orderedcode.Append(nil, c.CompositeKey)
+		if err != nil {
+			return nil, err
+		}
+
+		it, err := dbm.IteratePrefix(idx.store, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create prefix iterator: %w", err)
+		}
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			eventValue, err := parseValueFromEventKey(it.Key())
+			if err != nil {
+				continue
+			}
+
+			if re.MatchString(eventValue) {
+				tmpHeights[string(it.Value())] = it.Value()
+			}
+
+			select {
+			case <-ctx.Done():
+				break
+
+			default:
+			}
+		}
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+
+	case c.Op == query.OpNotEqual:
+		// The negation can't be pushed down to the index, so every value of the
+		// composite key is scanned and heights with a value equal to the operand
+		// are filtered out afterwards.
+		prefix, err := orderedcode.Append(nil, c.CompositeKey)
+		if err != nil {
+			return nil, err
+		}
+
+		it, err := dbm.IteratePrefix(idx.store, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create prefix iterator: %w", err)
+		}
+		defer it.Close()
+
+		excludedHeights := make(map[string]struct{})
+		operand := fmt.Sprintf("%v", c.Operand)
+
+		for ; it.Valid(); it.Next() {
+			var (
+				eventValue string
+				err        error
+			)
+
+			if c.CompositeKey == types.BlockHeightKey {
+				eventValue, err = parseValueFromPrimaryKey(it.Key())
+			} else {
+				eventValue, err = parseValueFromEventKey(it.Key())
+			}
+
+			if err != nil {
+				continue
+			}
+
+			if eventValue == operand {
+				excludedHeights[string(it.Value())] = struct{}{}
+			} else {
+				tmpHeights[string(it.Value())] = it.Value()
+			}
+
+			select {
+			case <-ctx.Done():
+				break
+
+			default:
+			}
+		}
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+
+		for h := range excludedHeights {
+			delete(tmpHeights, h)
+		}
+
 	default:
 		return nil, errors.New("other operators should be handled already")
 	}
@@ -129,6 +129,26 @@ func TestBlockIndexer(t *testing.T) {
 			q:       query.MustParse("begin_event.proposer CONTAINS 'FCAA001'"),
 			results: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
 		},
+		"end_event.foo MATCHES '1[0-9]*'": {
+			q:       query.MustParse("end_event.foo MATCHES '1[0-9]*'"),
+			results: []int64{1, 10},
+		},
+		"begin_event.proposer MATCHES 'FCAA'": {
+			q:       query.MustParse("begin_event.proposer MATCHES 'FCAA'"),
+			results: []int64{},
+		},
+		"end_event.foo != 4": {
+			q:       query.MustParse("end_event.foo != 4"),
+			results: []int64{1, 2, 6, 8, 10},
+		},
+		"begin_event.proposer != 'FCAA001'": {
+			q:       query.MustParse("begin_event.proposer != 'FCAA001'"),
+			results: []int64{},
+		},
+		"end_event.foo <= 8 AND block.height != 4": {
+			q:       query.MustParse("end_event.foo <= 8 AND block.height != 4"),
+			results: []int64{2, 6, 8},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -140,3 +160,30 @@ func TestBlockIndexer(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockIndexerMinIndexedHeight(t *testing.T) {
+	store := db.NewPrefixDB(db.NewMemDB(), []byte("block_events"))
+	indexer := blockidxkv.New(store)
+
+	height, err := indexer.MinIndexedHeight()
+	require.NoError(t, err)
+	require.Zero(t, height)
+
+	for _, h := range []int64{5, 7, 3, 10} {
+		require.NoError(t, indexer.Index(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: h},
+		}))
+	}
+
+	height, err = indexer.MinIndexedHeight()
+	require.NoError(t, err)
+	require.EqualValues(t, 3, height)
+
+	// stores indexed before the lowest height was tracked fall back to the
+	// first primary key
+	require.NoError(t, store.Delete([]byte("minIndexedHeight")))
+
+	height, err = indexer.MinIndexedHeight()
+	require.NoError(t, err)
+	require.EqualValues(t, 3, height)
+}
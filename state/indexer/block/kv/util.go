@@ -11,6 +11,10 @@ import (
 	"github.com/tendermint/tendermint/types"
 )
 
+// minHeightKey stores the lowest indexed height. It cannot collide with the
+// orderedcode encoded keys as those never contain a plain ASCII string.
+var minHeightKey = []byte("minIndexedHeight")
+
 func intInSlice(a int, list []int) bool {
 	for _, b := range list {
 		if b == a {
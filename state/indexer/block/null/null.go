@@ -18,6 +18,10 @@ func (idx *BlockerIndexer) Has(height int64) (bool, error) {
 	return false, errors.New(`indexing is disabled (set 'tx_index = "kv"' in config)`)
 }
 
+func (idx *BlockerIndexer) MinIndexedHeight() (int64, error) {
+	return 0, errors.New(`indexing is disabled (set 'tx_index = "kv"' in config)`)
+}
+
 func (idx *BlockerIndexer) Index(types.EventDataNewBlockHeader) error {
 	return nil
 }
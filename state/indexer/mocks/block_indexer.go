@@ -52,6 +52,27 @@ func (_m *BlockIndexer) Index(_a0 types.EventDataNewBlockHeader) error {
 	return r0
 }
 
+// MinIndexedHeight provides a mock function with given fields:
+func (_m *BlockIndexer) MinIndexedHeight() (int64, error) {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Search provides a mock function with given fields: ctx, q
 func (_m *BlockIndexer) Search(ctx context.Context, q *query.Query) ([]int64, error) {
 	ret := _m.Called(ctx, q)
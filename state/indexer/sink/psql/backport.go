@@ -53,6 +53,18 @@ func (BackportTxIndexer) Get([]byte) (*abci.TxResult, error) {
 	return nil, errors.New("the TxIndexer.Get method is not supported")
 }
 
+// GetAll is implemented to satisfy the TxIndexer interface, but is not
+// supported by the psql event sink and reports an error for all inputs.
+func (BackportTxIndexer) GetAll([]byte) ([]*abci.TxResult, error) {
+	return nil, errors.New("the TxIndexer.GetAll method is not supported")
+}
+
+// CountByHeight is implemented to satisfy the TxIndexer interface, but is not
+// supported by the psql event sink and reports an error for all inputs.
+func (BackportTxIndexer) CountByHeight(context.Context, int64, int64) ([]int, error) {
+	return nil, errors.New("the TxIndexer.CountByHeight method is not supported")
+}
+
 // Search is implemented to satisfy the TxIndexer interface, but it is not
 // supported by the psql event sink and reports an error for all inputs.
 func (BackportTxIndexer) Search(context.Context, *query.Query) ([]*abci.TxResult, error) {
@@ -75,6 +87,12 @@ func (BackportBlockIndexer) Has(height int64) (bool, error) {
 	return false, errors.New("the BlockIndexer.Has method is not supported")
 }
 
+// MinIndexedHeight is implemented to satisfy the BlockIndexer interface, but it
+// is not supported by the psql event sink and reports an error for all inputs.
+func (BackportBlockIndexer) MinIndexedHeight() (int64, error) {
+	return 0, errors.New("the BlockIndexer.MinIndexedHeight method is not supported")
+}
+
 // Index indexes block begin and end events for the specified block.  It is
 // part of the BlockIndexer interface.
 func (b BackportBlockIndexer) Index(block types.EventDataNewBlockHeader) error {
@@ -185,7 +185,7 @@ func (es *EventSink) IndexTxEvents(txrs []*abci.TxResult) error {
 		}
 
 		// Index the hash of the underlying transaction as a hex string.
-		txHash := fmt.Sprintf("%X", types.Tx(txr.Tx).Hash())
+		txHash := fmt.Sprintf("%X", types.TxHash(txr.Tx))
 
 		if err := runInTransaction(es.store, func(dbtx *sql.Tx) error {
 			// Find the block associated with this transaction. The block header
@@ -38,26 +38,93 @@ func Rollback(bs BlockStore, ss Store) (int64, []byte, error) {
 	}
 
 	// state store height is equal to blockstore height. We're good to proceed with rolling back state
+	rolledBackState, err := rollbackState(bs, ss, invalidState)
+	if err != nil {
+		return -1, nil, err
+	}
+	return rolledBackState.LastBlockHeight, rolledBackState.AppHash, nil
+}
+
+// RollbackBlocks overwrites the current Tendermint state with the state at
+// the given number of heights below the blockstore height, rolling back one
+// height at a time as Rollback does. As with Rollback, no blocks are removed,
+// and a state one below the blockstore height counts as already rolled back by
+// one height. It fails without modifying the state if the rollback would go
+// past the base height of the blockstore.
+// Note that this function does not affect application state.
+func RollbackBlocks(bs BlockStore, ss Store, blocks int64) (int64, []byte, error) {
+	if blocks < 1 {
+		return -1, nil, fmt.Errorf("number of blocks to roll back must be positive, got %d", blocks)
+	}
+	invalidState, err := ss.Load()
+	if err != nil {
+		return -1, nil, err
+	}
+	if invalidState.IsEmpty() {
+		return -1, nil, errors.New("no state found")
+	}
+
+	height := bs.Height()
+	if height != invalidState.LastBlockHeight && height != invalidState.LastBlockHeight+1 {
+		return -1, nil, fmt.Errorf("statestore height (%d) is not one below or equal to blockstore height (%d)",
+			invalidState.LastBlockHeight, height)
+	}
+
+	// Rolling back to a height requires its block, and the validators and
+	// consensus params of the heights before. The initial height the chain
+	// started at can't be rolled back past either.
+	targetHeight := height - blocks
+	base := bs.Base()
+	if base < invalidState.InitialHeight {
+		base = invalidState.InitialHeight
+	}
+	if targetHeight < base {
+		return -1, nil, fmt.Errorf("cannot roll back %d blocks from height %d: height %d is below the base height %d",
+			blocks, height, targetHeight, base)
+	}
+
+	for invalidState.LastBlockHeight > targetHeight {
+		rolledBackState, err := rollbackState(bs, ss, invalidState)
+		if err != nil {
+			return -1, nil, fmt.Errorf("failed to roll back height %d: %w", invalidState.LastBlockHeight, err)
+		}
+		// the blockstore must stay untouched, and the state one height lower
+		if h := bs.Height(); h != height {
+			return -1, nil, fmt.Errorf("blockstore height changed from %d to %d during the rollback", height, h)
+		}
+		if rolledBackState.LastBlockHeight != invalidState.LastBlockHeight-1 {
+			return -1, nil, fmt.Errorf("rolled back state height (%d) is not one below the previous state height (%d)",
+				rolledBackState.LastBlockHeight, invalidState.LastBlockHeight)
+		}
+		invalidState = rolledBackState
+	}
+
+	return invalidState.LastBlockHeight, invalidState.AppHash, nil
+}
+
+// rollbackState builds the state at the height below the one of invalidState,
+// from the blocks of both heights, and persists it over invalidState.
+func rollbackState(bs BlockStore, ss Store, invalidState State) (State, error) {
 	rollbackHeight := invalidState.LastBlockHeight - 1
 	rollbackBlock := bs.LoadBlockMeta(rollbackHeight)
 	if rollbackBlock == nil {
-		return -1, nil, fmt.Errorf("block at height %d not found", rollbackHeight)
+		return State{}, fmt.Errorf("block at height %d not found", rollbackHeight)
 	}
 	// We also need to retrieve the latest block because the app hash and last
 	// results hash is only agreed upon in the following block.
 	latestBlock := bs.LoadBlockMeta(invalidState.LastBlockHeight)
 	if latestBlock == nil {
-		return -1, nil, fmt.Errorf("block at height %d not found", invalidState.LastBlockHeight)
+		return State{}, fmt.Errorf("block at height %d not found", invalidState.LastBlockHeight)
 	}
 
 	previousLastValidatorSet, err := ss.LoadValidators(rollbackHeight)
 	if err != nil {
-		return -1, nil, err
+		return State{}, err
 	}
 
 	previousParams, err := ss.LoadConsensusParams(rollbackHeight + 1)
 	if err != nil {
-		return -1, nil, err
+		return State{}, err
 	}
 
 	valChangeHeight := invalidState.LastHeightValidatorsChanged
@@ -105,8 +172,8 @@ func Rollback(bs BlockStore, ss Store) (int64, []byte, error) {
 	// persist the validator set and consensus params over the existing structures,
 	// but both should be the same
 	if err := ss.Save(rolledBackState); err != nil {
-		return -1, nil, fmt.Errorf("failed to save rolled back state: %w", err)
+		return State{}, fmt.Errorf("failed to save rolled back state: %w", err)
 	}
 
-	return rolledBackState.LastBlockHeight, rolledBackState.AppHash, nil
+	return rolledBackState, nil
 }
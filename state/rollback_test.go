@@ -117,6 +117,70 @@ func TestRollbackDifferentStateHeight(t *testing.T) {
 	require.Equal(t, err.Error(), "statestore height (100) is not one below or equal to blockstore height (102)")
 }
 
+func TestRollbackBlocks(t *testing.T) {
+	const height = int64(100)
+	stateStore := setupStateStore(t, height)
+	initialState, err := stateStore.Load()
+	require.NoError(t, err)
+
+	// commit two more heights, keeping the block metas of each height
+	states := []state.State{initialState}
+	metas := map[int64]*types.BlockMeta{
+		height: {BlockID: initialState.LastBlockID, Header: types.Header{Height: height}},
+	}
+	for h := height + 1; h <= height+2; h++ {
+		prev := states[len(states)-1]
+		next := prev.Copy()
+		next.LastBlockHeight = h
+		next.LastBlockID = makeBlockIDRandom()
+		next.AppHash = crypto.CRandBytes(tmhash.Size)
+		next.LastResultsHash = crypto.CRandBytes(tmhash.Size)
+		next.LastValidators = prev.Validators
+		next.Validators = prev.NextValidators
+		next.NextValidators = prev.NextValidators.CopyIncrementProposerPriority(1)
+		next.LastHeightValidatorsChanged = h + 1
+		next.LastHeightConsensusParamsChanged = h + 1
+		require.NoError(t, stateStore.Save(next))
+		states = append(states, next)
+		// the app hash and results of a height are agreed upon in the next block
+		metas[h] = &types.BlockMeta{
+			BlockID: next.LastBlockID,
+			Header: types.Header{
+				Height:          h,
+				AppHash:         prev.AppHash,
+				LastResultsHash: prev.LastResultsHash,
+			},
+		}
+	}
+
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(height + 2)
+	blockStore.On("Base").Return(height)
+	for h, meta := range metas {
+		blockStore.On("LoadBlockMeta", h).Return(meta)
+	}
+
+	// past the base height, the state is left untouched
+	_, _, err = state.RollbackBlocks(blockStore, stateStore, 3)
+	require.Error(t, err)
+	require.Equal(t, "cannot roll back 3 blocks from height 102: height 99 is below the base height 100", err.Error())
+	loadedState, err := stateStore.Load()
+	require.NoError(t, err)
+	require.Equal(t, height+2, loadedState.LastBlockHeight)
+
+	_, _, err = state.RollbackBlocks(blockStore, stateStore, 0)
+	require.Error(t, err)
+
+	// within the range, the state is rolled back by each height
+	rollbackHeight, rollbackHash, err := state.RollbackBlocks(blockStore, stateStore, 2)
+	require.NoError(t, err)
+	require.Equal(t, height, rollbackHeight)
+	require.EqualValues(t, initialState.AppHash, rollbackHash)
+	loadedState, err = stateStore.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, initialState, loadedState)
+}
+
 func setupStateStore(t *testing.T, height int64) state.Store {
 	stateStore := state.NewStore(dbm.NewMemDB(), state.StoreOptions{DiscardABCIResponses: false})
 	valSet, _ := types.RandValidatorSet(5, 10)
@@ -24,8 +24,17 @@ type TxIndexer interface {
 	// or stored.
 	Get(hash []byte) (*abci.TxResult, error)
 
+	// GetAll returns all indexed occurrences of the transaction specified by
+	// hash, sorted by height. It returns an empty slice if the transaction is
+	// not indexed.
+	GetAll(hash []byte) ([]*abci.TxResult, error)
+
 	// Search allows you to query for transactions.
 	Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error)
+
+	// CountByHeight returns the number of indexed transactions at each height
+	// of [minHeight, maxHeight]: the i-th count is the one of minHeight+i.
+	CountByHeight(ctx context.Context, minHeight, maxHeight int64) ([]int, error)
 }
 
 // Batch groups together multiple Index operations to be performed at the same time.
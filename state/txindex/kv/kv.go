@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -60,6 +61,94 @@ func (txi *TxIndex) Get(hash []byte) (*abci.TxResult, error) {
 	return txResult, nil
 }
 
+// GetAll returns every indexed occurrence of the transaction with the given
+// hash, sorted by height and index. Apps that do not enforce tx uniqueness may
+// include the same tx at several heights, in which case Get only returns the
+// most recently indexed one.
+func (txi *TxIndex) GetAll(hash []byte) ([]*abci.TxResult, error) {
+	if len(hash) == 0 {
+		return nil, txindex.ErrorEmptyHash
+	}
+
+	results := make([]*abci.TxResult, 0)
+
+	it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHashKey, fmt.Sprintf("%X", hash)))
+	if err != nil {
+		panic(err)
+	}
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		txResult := new(abci.TxResult)
+		if err := proto.Unmarshal(it.Value(), txResult); err != nil {
+			return nil, fmt.Errorf("error reading TxResult: %v", err)
+		}
+		results = append(results, txResult)
+	}
+	if err := it.Error(); err != nil {
+		panic(err)
+	}
+
+	latest, err := txi.Get(hash)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		// an older occurrence might have been re-indexed (e.g. by reindex-event)
+		// after being preserved, so skip it if it is also the latest result
+		filtered := results[:0]
+		for _, r := range results {
+			if r.Height != latest.Height || r.Index != latest.Index {
+				filtered = append(filtered, r)
+			}
+		}
+		results = append(filtered, latest)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Height == results[j].Height {
+			return results[i].Index < results[j].Index
+		}
+		return results[i].Height < results[j].Height
+	})
+
+	return results, nil
+}
+
+// CountByHeight returns the number of indexed transactions at each height of
+// [minHeight, maxHeight], from the height index. Only the keys are read, the
+// transactions are not loaded.
+func (txi *TxIndex) CountByHeight(ctx context.Context, minHeight, maxHeight int64) ([]int, error) {
+	if minHeight > maxHeight {
+		return nil, fmt.Errorf("min height %d can't be greater than max height %d", minHeight, maxHeight)
+	}
+
+	counts := make([]int, 0, maxHeight-minHeight+1)
+	for height := minHeight; height <= maxHeight; height++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey, height))
+		if err != nil {
+			return nil, err
+		}
+		count := 0
+		for ; it.Valid(); it.Next() {
+			count++
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
 // AddBatch indexes a batch of transactions using the given list of events. Each
 // key that indexed from the tx's events is a composite of the event type and
 // the respective attribute's key delimited by a "." (eg. "account.number").
@@ -68,11 +157,29 @@ func (txi *TxIndex) AddBatch(b *txindex.Batch) error {
 	storeBatch := txi.store.NewBatch()
 	defer storeBatch.Close()
 
+	// results indexed earlier in this batch are not visible to Get until the
+	// batch is written
+	batchResults := make(map[string]*abci.TxResult, len(b.Ops))
+
 	for _, result := range b.Ops {
-		hash := types.Tx(result.Tx).Hash()
+		hash := types.TxHash(result.Tx)
+
+		prevResult, ok := batchResults[string(hash)]
+		if !ok {
+			var err error
+			prevResult, err = txi.getIfIndexed(hash)
+			if err != nil {
+				return err
+			}
+		}
+		err := txi.preserveOccurrence(prevResult, result, hash, storeBatch)
+		if err != nil {
+			return err
+		}
+		batchResults[string(hash)] = result
 
 		// index tx by events
-		err := txi.indexEvents(result, hash, storeBatch)
+		err = txi.indexEvents(result, hash, storeBatch)
 		if err != nil {
 			return err
 		}
@@ -110,23 +217,26 @@ func (txi *TxIndex) Index(result *abci.TxResult) error {
 	b := txi.store.NewBatch()
 	defer b.Close()
 
-	hash := types.Tx(result.Tx).Hash()
+	hash := types.TxHash(result.Tx)
 
-	if !result.Result.IsOK() {
-		oldResult, err := txi.Get(hash)
-		if err != nil {
-			return err
-		}
+	oldResult, err := txi.getIfIndexed(hash)
+	if err != nil {
+		return err
+	}
 
-		// if the new transaction failed and it's already indexed in an older block and was successful
-		// we skip it as we want users to get the older successful transaction when they query.
-		if oldResult != nil && oldResult.Result.Code == abci.CodeTypeOK {
-			return nil
-		}
+	// if the new transaction failed and it's already indexed in an older block and was successful
+	// we skip it as we want users to get the older successful transaction when they query.
+	if !result.Result.IsOK() && oldResult != nil && oldResult.Result.Code == abci.CodeTypeOK {
+		return nil
+	}
+
+	err = txi.preserveOccurrence(oldResult, result, hash, b)
+	if err != nil {
+		return err
 	}
 
 	// index tx by events
-	err := txi.indexEvents(result, hash, b)
+	err = txi.indexEvents(result, hash, b)
 	if err != nil {
 		return err
 	}
@@ -150,6 +260,39 @@ func (txi *TxIndex) Index(result *abci.TxResult) error {
 	return b.WriteSync()
 }
 
+// getIfIndexed returns the result indexed for the hash, if any. Hashes are
+// almost never indexed already on block commit, so it first checks that the
+// key exists, sparing the unmarshaling of the result in the common case.
+func (txi *TxIndex) getIfIndexed(hash []byte) (*abci.TxResult, error) {
+	ok, err := txi.store.Has(hash)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	return txi.Get(hash)
+}
+
+// preserveOccurrence keeps a previously indexed result for the same hash
+// reachable by GetAll before it gets overwritten by the new result.
+func (txi *TxIndex) preserveOccurrence(
+	prevResult, result *abci.TxResult,
+	hash []byte,
+	store dbm.Batch,
+) error {
+	if prevResult == nil || (prevResult.Height == result.Height && prevResult.Index == result.Index) {
+		return nil
+	}
+
+	rawBytes, err := proto.Marshal(prevResult)
+	if err != nil {
+		return err
+	}
+
+	return store.Set(keyForHashOccurrence(hash, prevResult), rawBytes)
+}
+
 func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Batch) error {
 	for _, event := range result.Result.Events {
 		// only index events with a non-empty type
@@ -180,7 +323,7 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Ba
 //
 // It breaks the query into conditions (like "tx.height > 5"). For each
 // condition, it queries the DB index. One special use cases here: (1) if
-// "tx.hash" is found, it returns tx result for it (2) for range queries it is
+// "tx.hash=X" is found, it returns tx result for it (2) for range queries it is
 // better for the client to provide both lower and upper bounds, so we are not
 // performing a full scan. Results from querying indexes are then intersected
 // and returned to the caller, in no particular order.
@@ -288,9 +431,10 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResul
 	return results, nil
 }
 
+// lookForHash returns a hash if there is a "tx.hash=X" condition.
 func lookForHash(conditions []query.Condition) (hash []byte, ok bool, err error) {
 	for _, c := range conditions {
-		if c.CompositeKey == types.TxHashKey {
+		if c.CompositeKey == types.TxHashKey && c.Op == query.OpEqual {
 			decoded, err := hex.DecodeString(c.Operand.(string))
 			return decoded, true, err
 		}
@@ -329,6 +473,9 @@ func (txi *TxIndex) match(
 	tmpHashes := make(map[string][]byte)
 
 	switch {
+	case c.CompositeKey == types.TxHashKey:
+		tmpHashes = txi.matchTxHash(ctx, c)
+
 	case c.Op == query.OpEqual:
 		it, err := dbm.IteratePrefix(txi.store, startKeyBz)
 		if err != nil {
@@ -402,6 +549,76 @@ func (txi *TxIndex) match(
 		if err := it.Error(); err != nil {
 			panic(err)
 		}
+	case c.Op == query.OpMatches:
+		// XXX: startKey does not apply here, as with CONTAINS.
+		re, err := query.CompileMatches(c.Operand.(string))
+		if err != nil {
+			panic(err)
+		}
+		it, err := dbm.IteratePrefix(txi.store, startKey(c.CompositeKey))
+		if err != nil {
+			panic(err)
+		}
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			if !isTagKey(it.Key()) {
+				continue
+			}
+
+			if re.MatchString(extractValueFromKey(it.Key())) {
+				tmpHashes[string(it.Value())] = it.Value()
+			}
+
+			// Potentially exit early.
+			select {
+			case <-ctx.Done():
+				break
+			default:
+			}
+		}
+		if err := it.Error(); err != nil {
+			panic(err)
+		}
+	case c.Op == query.OpNotEqual:
+		// XXX: startKey does not apply here. The negation can't be pushed down
+		// to the index, so every value of the composite key is scanned and txs
+		// with a value equal to the operand are filtered out afterwards.
+		excludedHashes := make(map[string]struct{})
+		operand := fmt.Sprintf("%v", c.Operand)
+
+		it, err := dbm.IteratePrefix(txi.store, startKey(c.CompositeKey))
+		if err != nil {
+			panic(err)
+		}
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			if !isTagKey(it.Key()) {
+				continue
+			}
+
+			if extractValueFromKey(it.Key()) == operand {
+				excludedHashes[string(it.Value())] = struct{}{}
+			} else {
+				tmpHashes[string(it.Value())] = it.Value()
+			}
+
+			// Potentially exit early.
+			select {
+			case <-ctx.Done():
+				break
+			default:
+			}
+		}
+		if err := it.Error(); err != nil {
+			panic(err)
+		}
+
+		for h := range excludedHashes {
+			delete(tmpHashes, h)
+		}
+
 	default:
 		panic("other operators should be handled already")
 	}
@@ -435,6 +652,59 @@ func (txi *TxIndex) match(
 	return filteredHashes
 }
 
+// matchTxHash returns the hashes of all the txs whose hash meets a given
+// "tx.hash" condition, other than "=" which is looked up directly. Hashes are
+// not indexed as events, so the height index, which holds the hash of every
+// tx, is scanned.
+func (txi *TxIndex) matchTxHash(ctx context.Context, c query.Condition) map[string][]byte {
+	var matches func(hash string) bool
+	switch c.Op {
+	case query.OpExists:
+		matches = func(string) bool { return true }
+	case query.OpNotEqual:
+		operand := c.Operand.(string)
+		matches = func(hash string) bool { return !strings.EqualFold(hash, operand) }
+	case query.OpContains:
+		operand := strings.ToUpper(c.Operand.(string))
+		matches = func(hash string) bool { return strings.Contains(hash, operand) }
+	case query.OpMatches:
+		re, err := query.CompileMatches(c.Operand.(string))
+		if err != nil {
+			panic(err)
+		}
+		matches = re.MatchString
+	default:
+		panic("other operators should be handled already")
+	}
+
+	tmpHashes := make(map[string][]byte)
+
+	it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey))
+	if err != nil {
+		panic(err)
+	}
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		// hashes are reported in uppercase hex
+		if matches(fmt.Sprintf("%X", it.Value())) {
+			tmpHashes[string(it.Value())] = it.Value()
+		}
+
+		// Potentially exit early.
+		select {
+		case <-ctx.Done():
+			break
+		default:
+		}
+	}
+	if err := it.Error(); err != nil {
+		panic(err)
+	}
+
+	return tmpHashes
+}
+
 // matchRange returns all matching txs by hash that meet a given queryRange and
 // start key. An already filtered result (filteredHashes) is provided such that
 // any non-intersecting matches are removed.
@@ -565,6 +835,15 @@ func keyForHeight(result *abci.TxResult) []byte {
 	))
 }
 
+func keyForHashOccurrence(hash []byte, result *abci.TxResult) []byte {
+	return []byte(fmt.Sprintf("%s/%X/%d/%d",
+		types.TxHashKey,
+		hash,
+		result.Height,
+		result.Index,
+	))
+}
+
 func startKeyForCondition(c query.Condition, height int64) []byte {
 	if height > 0 {
 		return startKey(c.CompositeKey, c.Operand, height)
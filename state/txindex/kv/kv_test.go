@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
@@ -65,6 +66,29 @@ func TestTxIndex(t *testing.T) {
 	assert.True(t, proto.Equal(txResult2, loadedTxResult2))
 }
 
+func TestTxIndexCustomHasher(t *testing.T) {
+	types.RegisterTxHasher(func(tx types.Tx) []byte { return append([]byte("custom-"), tx...) })
+	t.Cleanup(func() { types.RegisterTxHasher(nil) })
+
+	indexer := NewTxIndex(db.NewMemDB())
+	tx := types.Tx("HELLO WORLD")
+	txResult := &abci.TxResult{Height: 1, Index: 0, Tx: tx}
+	require.NoError(t, indexer.Index(txResult))
+
+	loadedTxResult, err := indexer.Get([]byte("custom-HELLO WORLD"))
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(txResult, loadedTxResult))
+
+	loadedTxResult, err = indexer.Get(tx.Hash())
+	require.NoError(t, err)
+	assert.Nil(t, loadedTxResult)
+
+	results, err := indexer.Search(context.Background(), query.MustParse("tx.height = 1"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, proto.Equal(txResult, results[0]))
+}
+
 func TestTxSearch(t *testing.T) {
 	indexer := NewTxIndex(db.NewMemDB())
 
@@ -115,10 +139,24 @@ func TestTxSearch(t *testing.T) {
 		{"account.owner CONTAINS 'Vlad'", 0},
 		// search using the wrong key (of numeric type) using CONTAINS
 		{"account.number CONTAINS 'Iv'", 0},
+		// search using MATCHES
+		{"account.owner MATCHES 'I[a-z]+'", 1},
+		// search using MATCHES, which is anchored
+		{"account.owner MATCHES 'va'", 0},
+		// search using MATCHES combined with an exact match
+		{"account.number = 1 AND account.owner MATCHES 'Vl.*'", 0},
 		// search using EXISTS
 		{"account.number EXISTS", 1},
 		// search using EXISTS for non existing key
 		{"account.date EXISTS", 0},
+		// search using != for a different value
+		{"account.owner != 'Vlad'", 1},
+		// search using != for the stored value
+		{"account.number != 1", 0},
+		// search using != combined with an exact match
+		{"account.number = 1 AND account.owner != 'Ivan'", 0},
+		// search using != for non existing key
+		{"account.date != 'today'", 0},
 	}
 
 	ctx := context.Background()
@@ -139,6 +177,62 @@ func TestTxSearch(t *testing.T) {
 	}
 }
 
+func TestTxSearchByHash(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB())
+
+	txResult1 := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []abci.EventAttribute{{Key: []byte("number"), Value: []byte("1"), Index: true}}},
+	})
+	txResult2 := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []abci.EventAttribute{{Key: []byte("number"), Value: []byte("2"), Index: true}}},
+	})
+	txResult2.Tx = types.Tx("HELLO WORLD 2")
+	txResult2.Index = 1
+	hash1 := types.TxHash(txResult1.Tx)
+	hash2 := types.TxHash(txResult2.Tx)
+
+	require.NoError(t, indexer.Index(txResult1))
+	require.NoError(t, indexer.Index(txResult2))
+
+	testCases := []struct {
+		q       string
+		results []*abci.TxResult
+	}{
+		{fmt.Sprintf("tx.hash = '%X'", hash1), []*abci.TxResult{txResult1}},
+		// search using != for a hash
+		{fmt.Sprintf("tx.hash != '%X'", hash1), []*abci.TxResult{txResult2}},
+		// hashes are case insensitive
+		{fmt.Sprintf("tx.hash != '%x'", hash1), []*abci.TxResult{txResult2}},
+		// search using != combined with another condition
+		{fmt.Sprintf("tx.hash != '%X' AND account.number = 1", hash1), []*abci.TxResult{}},
+		{fmt.Sprintf("tx.hash != '%X' AND account.number = 2", hash1), []*abci.TxResult{txResult2}},
+		// search using MATCHES
+		{fmt.Sprintf("tx.hash MATCHES '%X.*'", hash2[:4]), []*abci.TxResult{txResult2}},
+		{"tx.hash MATCHES '[0-9A-F]{64}'", []*abci.TxResult{txResult1, txResult2}},
+		{"tx.hash MATCHES 'XYZ'", []*abci.TxResult{}},
+		// search using CONTAINS
+		{fmt.Sprintf("tx.hash CONTAINS '%x'", hash1[4:8]), []*abci.TxResult{txResult1}},
+		// search using EXISTS
+		{"tx.hash EXISTS", []*abci.TxResult{txResult1, txResult2}},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.q, func(t *testing.T) {
+			results, err := indexer.Search(ctx, query.MustParse(tc.q))
+			require.NoError(t, err)
+
+			require.Len(t, results, len(tc.results))
+			sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+			for i, txr := range results {
+				assert.True(t, proto.Equal(tc.results[i], txr))
+			}
+		})
+	}
+}
+
 func TestTxSearchWithCancelation(t *testing.T) {
 	indexer := NewTxIndex(db.NewMemDB())
 
@@ -355,6 +449,89 @@ func TestTxIndexDuplicatePreviouslySuccessful(t *testing.T) {
 	}
 }
 
+func TestTxIndexGetAll(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB())
+
+	mockTx := types.Tx("MOCK_TX_HASH")
+	hash := mockTx.Hash()
+
+	txResult := func(height int64, index uint32) *abci.TxResult {
+		return &abci.TxResult{
+			Height: height,
+			Index:  index,
+			Tx:     mockTx,
+			Result: abci.ResponseDeliverTx{Code: abci.CodeTypeOK},
+		}
+	}
+
+	results, err := indexer.GetAll(hash)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// index the same tx at several heights, out of order and partly in a batch
+	require.NoError(t, indexer.Index(txResult(5, 1)))
+	require.NoError(t, indexer.Index(txResult(2, 0)))
+
+	batch := txindex.NewBatch(2)
+	require.NoError(t, batch.Add(txResult(10, 0)))
+	require.NoError(t, batch.Add(txResult(10, 1)))
+	require.NoError(t, indexer.AddBatch(batch))
+
+	// re-indexing an already indexed occurrence must not duplicate it
+	require.NoError(t, indexer.Index(txResult(5, 1)))
+
+	results, err = indexer.GetAll(hash)
+	require.NoError(t, err)
+	require.Equal(t, []*abci.TxResult{
+		txResult(2, 0),
+		txResult(5, 1),
+		txResult(10, 0),
+		txResult(10, 1),
+	}, results)
+
+	// Get still returns the most recently indexed occurrence
+	res, err := indexer.Get(hash)
+	require.NoError(t, err)
+	require.Equal(t, txResult(5, 1), res)
+
+	_, err = indexer.GetAll(nil)
+	require.ErrorIs(t, err, txindex.ErrorEmptyHash)
+}
+
+func TestTxIndexCountByHeight(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB())
+
+	// heights 1 and 10 must not be confused, their keys share a prefix
+	txCounts := map[int64]int{1: 2, 3: 1, 10: 3}
+	for height, count := range txCounts {
+		batch := txindex.NewBatch(int64(count))
+		for i := 0; i < count; i++ {
+			require.NoError(t, batch.Add(&abci.TxResult{
+				Height: height,
+				Index:  uint32(i),
+				Tx:     types.Tx(fmt.Sprintf("tx-%d-%d", height, i)),
+			}))
+		}
+		require.NoError(t, indexer.AddBatch(batch))
+	}
+
+	counts, err := indexer.CountByHeight(context.Background(), 1, 11)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 0, 1, 0, 0, 0, 0, 0, 0, 3, 0}, counts)
+
+	counts, err = indexer.CountByHeight(context.Background(), 10, 10)
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, counts)
+
+	_, err = indexer.CountByHeight(context.Background(), 3, 2)
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = indexer.CountByHeight(ctx, 1, 11)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 func TestTxSearchMultipleTxs(t *testing.T) {
 	indexer := NewTxIndex(db.NewMemDB())
 
@@ -469,3 +646,38 @@ func BenchmarkTxIndex500(b *testing.B)   { benchmarkTxIndex(500, b) }
 func BenchmarkTxIndex1000(b *testing.B)  { benchmarkTxIndex(1000, b) }
 func BenchmarkTxIndex2000(b *testing.B)  { benchmarkTxIndex(2000, b) }
 func BenchmarkTxIndex10000(b *testing.B) { benchmarkTxIndex(10000, b) }
+
+// BenchmarkTxIndexNewTxs indexes txs which were never indexed before, as on
+// block commit, where duplicate hashes are rare.
+func BenchmarkTxIndexNewTxs(b *testing.B) {
+	dir := b.TempDir()
+	store, err := db.NewDB("tx_index", "goleveldb", dir)
+	require.NoError(b, err)
+	defer store.Close()
+	indexer := NewTxIndex(store)
+
+	const txsPerBatch = 100
+	results := make([]*abci.TxResult, b.N*txsPerBatch)
+	for i := range results {
+		results[i] = &abci.TxResult{
+			Height: int64(i/txsPerBatch + 1),
+			Index:  uint32(i % txsPerBatch),
+			Tx:     tmrand.Bytes(250),
+			Result: abci.ResponseDeliverTx{Code: abci.CodeTypeOK},
+		}
+	}
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		batch := txindex.NewBatch(txsPerBatch)
+		for _, result := range results[n*txsPerBatch : (n+1)*txsPerBatch] {
+			if err := batch.Add(result); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := indexer.AddBatch(batch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
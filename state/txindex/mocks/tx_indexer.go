@@ -33,6 +33,29 @@ func (_m *TxIndexer) AddBatch(b *txindex.Batch) error {
 	return r0
 }
 
+// CountByHeight provides a mock function with given fields: ctx, minHeight, maxHeight
+func (_m *TxIndexer) CountByHeight(ctx context.Context, minHeight int64, maxHeight int64) ([]int, error) {
+	ret := _m.Called(ctx, minHeight, maxHeight)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []int); ok {
+		r0 = rf(ctx, minHeight, maxHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, minHeight, maxHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: hash
 func (_m *TxIndexer) Get(hash []byte) (*types.TxResult, error) {
 	ret := _m.Called(hash)
@@ -56,6 +79,29 @@ func (_m *TxIndexer) Get(hash []byte) (*types.TxResult, error) {
 	return r0, r1
 }
 
+// GetAll provides a mock function with given fields: hash
+func (_m *TxIndexer) GetAll(hash []byte) ([]*types.TxResult, error) {
+	ret := _m.Called(hash)
+
+	var r0 []*types.TxResult
+	if rf, ok := ret.Get(0).(func([]byte) []*types.TxResult); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.TxResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Index provides a mock function with given fields: result
 func (_m *TxIndexer) Index(result *types.TxResult) error {
 	ret := _m.Called(result)
@@ -19,6 +19,16 @@ func (txi *TxIndex) Get(hash []byte) (*abci.TxResult, error) {
 	return nil, errors.New(`indexing is disabled (set 'tx_index = "kv"' in config)`)
 }
 
+// GetAll on a TxIndex is disabled and returns an error when invoked.
+func (txi *TxIndex) GetAll(hash []byte) ([]*abci.TxResult, error) {
+	return nil, errors.New(`indexing is disabled (set 'tx_index = "kv"' in config)`)
+}
+
+// CountByHeight on a TxIndex is disabled and returns an error when invoked.
+func (txi *TxIndex) CountByHeight(ctx context.Context, minHeight, maxHeight int64) ([]int, error) {
+	return nil, errors.New(`indexing is disabled (set 'tx_index = "kv"' in config)`)
+}
+
 // AddBatch is a noop and always returns nil.
 func (txi *TxIndex) AddBatch(batch *txindex.Batch) error {
 	return nil
@@ -7,6 +7,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	dbm "github.com/tendermint/tm-db"
 
+	"github.com/tendermint/tendermint/crypto/merkle"
 	tmsync "github.com/tendermint/tendermint/libs/sync"
 	tmstore "github.com/tendermint/tendermint/proto/tendermint/store"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
@@ -246,19 +247,9 @@ func (bs *BlockStore) LoadSeenCommit(height int64) *types.Commit {
 
 // PruneBlocks removes block up to (but not including) a height. It returns number of blocks pruned.
 func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
-	if height <= 0 {
-		return 0, fmt.Errorf("height must be greater than 0")
-	}
-	bs.mtx.RLock()
-	if height > bs.height {
-		bs.mtx.RUnlock()
-		return 0, fmt.Errorf("cannot prune beyond the latest height %v", bs.height)
-	}
-	base := bs.base
-	bs.mtx.RUnlock()
-	if height < base {
-		return 0, fmt.Errorf("cannot prune to height %v, it is lower than base height %v",
-			height, base)
+	base, err := bs.pruneBase(height)
+	if err != nil {
+		return 0, err
 	}
 
 	pruned := uint64(0)
@@ -315,13 +306,226 @@ func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
 		}
 	}
 
-	err := flush(batch, height)
+	err = flush(batch, height)
 	if err != nil {
 		return 0, err
 	}
 	return pruned, nil
 }
 
+// PrunePreview reports what PruneBlocks would remove for the given height,
+// without removing anything: the number of blocks pruned and the total size,
+// in bytes, of the keys and values deleted. It fails in the same cases as
+// PruneBlocks.
+func (bs *BlockStore) PrunePreview(height int64) (uint64, int64, error) {
+	base, err := bs.pruneBase(height)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		pruned uint64
+		size   int64
+	)
+	addSize := func(key []byte) error {
+		bz, err := bs.db.Get(key)
+		if err != nil {
+			return err
+		}
+		if bz != nil {
+			size += int64(len(key) + len(bz))
+		}
+		return nil
+	}
+	for h := base; h < height; h++ {
+		meta := bs.LoadBlockMeta(h)
+		if meta == nil { // assume already deleted
+			continue
+		}
+		keys := [][]byte{
+			calcBlockMetaKey(h),
+			calcBlockHashKey(meta.BlockID.Hash),
+			calcBlockCommitKey(h),
+			calcSeenCommitKey(h),
+		}
+		for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
+			keys = append(keys, calcBlockPartKey(h, p))
+		}
+		for _, key := range keys {
+			if err := addSize(key); err != nil {
+				return 0, 0, err
+			}
+		}
+		pruned++
+	}
+	return pruned, size, nil
+}
+
+// pruneBase checks that the store can be pruned up to the given height, and
+// returns the current base.
+func (bs *BlockStore) pruneBase(height int64) (int64, error) {
+	if height <= 0 {
+		return 0, fmt.Errorf("height must be greater than 0")
+	}
+	bs.mtx.RLock()
+	if height > bs.height {
+		bs.mtx.RUnlock()
+		return 0, fmt.Errorf("cannot prune beyond the latest height %v", bs.height)
+	}
+	base := bs.base
+	bs.mtx.RUnlock()
+	if height < base {
+		return 0, fmt.Errorf("cannot prune to height %v, it is lower than base height %v",
+			height, base)
+	}
+	return base, nil
+}
+
+// BlockIndexRebuild reports the outcome of RebuildBlockIndex.
+type BlockIndexRebuild struct {
+	// Rebuilt lists the heights whose block meta or hash index was missing or
+	// corrupted, and has been (or, on a dry run, would be) rebuilt.
+	Rebuilt []int64
+	// Unrecoverable lists the heights whose block can't be reassembled from
+	// the stored parts.
+	Unrecoverable []int64
+}
+
+// RebuildBlockIndex checks, for every height from base to height, that the
+// block meta is readable and that the block hash maps to the height. A
+// missing or corrupted entry is rebuilt from the stored block parts, unless
+// dryRun is true. The store must not be in use by a node.
+func (bs *BlockStore) RebuildBlockIndex(dryRun bool) (BlockIndexRebuild, error) {
+	var result BlockIndexRebuild
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	for h := bs.Base(); h > 0 && h <= bs.Height(); h++ {
+		meta, err := bs.loadBlockMetaSafe(h)
+		if err != nil || meta == nil {
+			meta, err = bs.reassembleBlockMeta(h)
+			if err != nil {
+				result.Unrecoverable = append(result.Unrecoverable, h)
+				continue
+			}
+			metaBytes, err := proto.Marshal(meta.ToProto())
+			if err != nil {
+				return result, err
+			}
+			if err := batch.Set(calcBlockMetaKey(h), metaBytes); err != nil {
+				return result, err
+			}
+		} else {
+			bz, err := bs.db.Get(calcBlockHashKey(meta.BlockID.Hash))
+			if err != nil {
+				return result, err
+			}
+			if string(bz) == fmt.Sprintf("%d", h) {
+				continue
+			}
+		}
+
+		if err := batch.Set(calcBlockHashKey(meta.BlockID.Hash), []byte(fmt.Sprintf("%d", h))); err != nil {
+			return result, err
+		}
+		result.Rebuilt = append(result.Rebuilt, h)
+	}
+
+	if dryRun || len(result.Rebuilt) == 0 {
+		return result, nil
+	}
+	if err := batch.WriteSync(); err != nil {
+		return result, fmt.Errorf("failed to write the rebuilt index: %w", err)
+	}
+	return result, nil
+}
+
+// loadBlockMetaSafe is like LoadBlockMeta, but returns an error instead of
+// panicking if the stored meta is corrupted.
+func (bs *BlockStore) loadBlockMetaSafe(height int64) (*types.BlockMeta, error) {
+	bz, err := bs.db.Get(calcBlockMetaKey(height))
+	if err != nil || len(bz) == 0 {
+		return nil, err
+	}
+	pbbm := new(tmproto.BlockMeta)
+	if err := proto.Unmarshal(bz, pbbm); err != nil {
+		return nil, err
+	}
+	meta, err := types.BlockMetaFromProto(pbbm)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Header.Height != height {
+		return nil, fmt.Errorf("block meta of height %d stored at height %d", meta.Header.Height, height)
+	}
+	return meta, nil
+}
+
+// reassembleBlockMeta rebuilds the block meta of the given height from the
+// stored block parts, which must all be present and valid.
+func (bs *BlockStore) reassembleBlockMeta(height int64) (*types.BlockMeta, error) {
+	var (
+		parts []*types.Part
+		total = uint32(1)
+	)
+	// the number of parts is only known from the proof of the first part
+	for i := uint32(0); i < total; i++ {
+		bz, err := bs.db.Get(calcBlockPartKey(height, int(i)))
+		if err != nil {
+			return nil, err
+		}
+		if len(bz) == 0 {
+			return nil, fmt.Errorf("part %d of block %d is missing", i, height)
+		}
+		pbpart := new(tmproto.Part)
+		if err := proto.Unmarshal(bz, pbpart); err != nil {
+			return nil, err
+		}
+		part, err := types.PartFromProto(pbpart)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if part.Proof.Total <= 0 {
+				return nil, fmt.Errorf("part 0 of block %d has an invalid proof", height)
+			}
+			total = uint32(part.Proof.Total)
+		}
+		parts = append(parts, part)
+	}
+
+	partBytes := make([][]byte, len(parts))
+	for i, part := range parts {
+		partBytes[i] = part.Bytes
+	}
+	partSet := types.NewPartSetFromHeader(types.PartSetHeader{
+		Total: total,
+		Hash:  merkle.HashFromByteSlices(partBytes),
+	})
+	for _, part := range parts {
+		if _, err := partSet.AddPart(part); err != nil {
+			return nil, fmt.Errorf("invalid part %d of block %d: %w", part.Index, height, err)
+		}
+	}
+
+	pbb := new(tmproto.Block)
+	buf := make([]byte, 0, partSet.ByteSize())
+	for _, b := range partBytes {
+		buf = append(buf, b...)
+	}
+	if err := proto.Unmarshal(buf, pbb); err != nil {
+		return nil, err
+	}
+	block, err := types.BlockFromProto(pbb)
+	if err != nil {
+		return nil, err
+	}
+	if block.Height != height {
+		return nil, fmt.Errorf("block of height %d stored at height %d", block.Height, height)
+	}
+	return types.NewBlockMeta(block, partSet), nil
+}
+
 // SaveBlock persists the given block, blockParts, and seenCommit to the underlying db.
 // blockParts: Must be parts of the block
 // seenCommit: The +2/3 precommits that were seen which committed at height.
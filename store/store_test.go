@@ -426,6 +426,117 @@ func TestLoadBlockPart(t *testing.T) {
 		"expecting successful retrieval of previously saved block")
 }
 
+func TestRebuildBlockIndex(t *testing.T) {
+	config := cfg.ResetTestRoot("blockchain_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{
+		DiscardABCIResponses: false,
+	})
+	state, err := stateStore.LoadFromDBOrGenesisFile(config.GenesisFile())
+	require.NoError(t, err)
+	db := dbm.NewMemDB()
+	bs := NewBlockStore(db)
+
+	metas := make(map[int64]*types.BlockMeta)
+	for h := int64(1); h <= 10; h++ {
+		block := makeBlock(h, state, new(types.Commit))
+		partSet := block.MakePartSet(2)
+		seenCommit := makeTestCommit(h, tmtime.Now())
+		bs.SaveBlock(block, partSet, seenCommit)
+		metas[h] = bs.LoadBlockMeta(h)
+	}
+
+	result, err := bs.RebuildBlockIndex(false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Rebuilt)
+	assert.Empty(t, result.Unrecoverable)
+
+	// 3 has lost its meta, 5 has a corrupted meta, 7 has lost its hash index
+	// and 8 has lost both its meta and one of its parts
+	require.NoError(t, db.Delete(calcBlockMetaKey(3)))
+	require.NoError(t, db.Set(calcBlockMetaKey(5), []byte("garbage")))
+	require.NoError(t, db.Delete(calcBlockHashKey(metas[7].BlockID.Hash)))
+	require.NoError(t, db.Delete(calcBlockMetaKey(8)))
+	require.NoError(t, db.Delete(calcBlockPartKey(8, 1)))
+
+	result, err = bs.RebuildBlockIndex(true)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 5, 7}, result.Rebuilt)
+	assert.Equal(t, []int64{8}, result.Unrecoverable)
+	assert.Nil(t, bs.LoadBlockMeta(3), "a dry run must not modify the store")
+
+	result, err = bs.RebuildBlockIndex(false)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 5, 7}, result.Rebuilt)
+	assert.Equal(t, []int64{8}, result.Unrecoverable)
+	for _, h := range []int64{3, 5, 7} {
+		assert.Equal(t, metas[h], bs.LoadBlockMeta(h), "height %d", h)
+		block := bs.LoadBlockByHash(metas[h].BlockID.Hash)
+		require.NotNil(t, block, "height %d", h)
+		assert.Equal(t, h, block.Height)
+	}
+
+	result, err = bs.RebuildBlockIndex(false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Rebuilt)
+	assert.Equal(t, []int64{8}, result.Unrecoverable)
+}
+
+func TestPrunePreview(t *testing.T) {
+	config := cfg.ResetTestRoot("blockchain_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{
+		DiscardABCIResponses: false,
+	})
+	state, err := stateStore.LoadFromDBOrGenesisFile(config.GenesisFile())
+	require.NoError(t, err)
+	db := dbm.NewMemDB()
+	bs := NewBlockStore(db)
+
+	_, _, err = bs.PrunePreview(1)
+	require.Error(t, err)
+
+	for h := int64(1); h <= 20; h++ {
+		block := makeBlock(h, state, new(types.Commit))
+		partSet := block.MakePartSet(2)
+		seenCommit := makeTestCommit(h, tmtime.Now())
+		bs.SaveBlock(block, partSet, seenCommit)
+	}
+
+	// dbSize is the total size of the keys and values, except for the store
+	// state, which is rewritten when pruning
+	dbSize := func() int64 {
+		var size int64
+		it, err := db.Iterator(nil, nil)
+		require.NoError(t, err)
+		defer it.Close()
+		for ; it.Valid(); it.Next() {
+			if !bytes.Equal(it.Key(), blockStoreKey) {
+				size += int64(len(it.Key()) + len(it.Value()))
+			}
+		}
+		return size
+	}
+
+	before := dbSize()
+	previewed, size, err := bs.PrunePreview(10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 9, previewed)
+	assert.Equal(t, before, dbSize(), "previewing must not prune")
+	assert.EqualValues(t, 1, bs.Base())
+
+	pruned, err := bs.PruneBlocks(10)
+	require.NoError(t, err)
+	assert.Equal(t, previewed, pruned)
+	assert.Equal(t, before-dbSize(), size)
+
+	// the preview fails like pruning does
+	_, _, err = bs.PrunePreview(9)
+	require.Error(t, err)
+	_, _, err = bs.PrunePreview(21)
+	require.Error(t, err)
+}
+
 func TestPruneBlocks(t *testing.T) {
 	config := cfg.ResetTestRoot("blockchain_reactor_test")
 	defer os.RemoveAll(config.RootDir)
@@ -15,14 +15,16 @@ type emptyMempool struct{}
 
 var _ mempl.Mempool = emptyMempool{}
 
-func (emptyMempool) Lock()            {}
-func (emptyMempool) Unlock()          {}
-func (emptyMempool) Size() int        { return 0 }
-func (emptyMempool) SizeBytes() int64 { return 0 }
+func (emptyMempool) Lock()                         {}
+func (emptyMempool) Unlock()                       {}
+func (emptyMempool) Size() int                     { return 0 }
+func (emptyMempool) SizeBytes() int64              { return 0 }
+func (emptyMempool) PendingTxs() []mempl.PendingTx { return nil }
 func (emptyMempool) CheckTx(_ types.Tx, _ func(*abci.Response), _ mempl.TxInfo) error {
 	return nil
 }
 func (emptyMempool) RemoveTxByKey(txKey types.TxKey) error   { return nil }
+func (emptyMempool) RemoveTxByHash(hash []byte) error        { return nil }
 func (emptyMempool) ReapMaxBytesMaxGas(_, _ int64) types.Txs { return types.Txs{} }
 func (emptyMempool) ReapMaxTxs(n int) types.Txs              { return types.Txs{} }
 func (emptyMempool) Update(
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	tmnet "github.com/tendermint/tendermint/libs/net"
+)
+
+// RunConfig holds the parameters of the run command, which can be read from a
+// JSON or TOML configuration file.
+type RunConfig struct {
+	BindAddr           string   `json:"addr" toml:"addr"`
+	DialAddr           string   `json:"dial" toml:"dial"`
+	TMHome             string   `json:"tmhome" toml:"tmhome"`
+	AcceptRetries      int      `json:"accept_retries" toml:"accept_retries"`
+	MaxConcurrentConns int      `json:"max_concurrent_conns" toml:"max_concurrent_conns"`
+	AcceptDeadline     Duration `json:"accept_deadline" toml:"accept_deadline"`
+	ConnDeadline       Duration `json:"conn_deadline" toml:"conn_deadline"`
+	PingCount          int      `json:"ping_count" toml:"ping_count"`
+	Timeout            Duration `json:"timeout" toml:"timeout"`
+	SecondChainID      string   `json:"second_chain_id" toml:"second_chain_id"`
+	ReconnectInterval  Duration `json:"reconnect_interval" toml:"reconnect_interval"`
+	ReconnectCount     int      `json:"reconnect_count" toml:"reconnect_count"`
+	DumpProtocol       string   `json:"dump_protocol" toml:"dump_protocol"`
+	MaxSignLatency     Duration `json:"max_sign_latency" toml:"max_sign_latency"`
+	BenchVotes         int      `json:"bench_votes" toml:"bench_votes"`
+	Output             string   `json:"output" toml:"output"`
+}
+
+// The formats of the outcome of the run command.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// Duration is a time.Duration read from its string representation (e.g.
+// "30s") in configuration files.
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// ValidateBasic performs basic validation of the configuration.
+func (cfg RunConfig) ValidateBasic() error {
+	if cfg.BindAddr == "" {
+		return errors.New("addr can't be empty")
+	}
+	if proto, addr := tmnet.ProtocolAndAddress(cfg.BindAddr); proto != "tcp" && proto != "unix" {
+		return fmt.Errorf("addr must be a tcp:// or unix:// address, got %s", cfg.BindAddr)
+	} else if addr == "" {
+		return errors.New("addr can't have an empty host or path")
+	}
+	if cfg.AcceptRetries <= 0 {
+		return errors.New("accept_retries must be positive")
+	}
+	if cfg.MaxConcurrentConns < 0 {
+		return errors.New("max_concurrent_conns can't be negative")
+	}
+	if cfg.AcceptDeadline <= 0 {
+		return errors.New("accept_deadline must be positive")
+	}
+	if cfg.ConnDeadline <= 0 {
+		return errors.New("conn_deadline must be positive")
+	}
+	if cfg.PingCount < 0 {
+		return errors.New("ping_count can't be negative")
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("timeout can't be negative")
+	}
+	if cfg.ReconnectInterval < 0 {
+		return errors.New("reconnect_interval can't be negative")
+	}
+	if cfg.ReconnectCount < 0 {
+		return errors.New("reconnect_count can't be negative")
+	}
+	if cfg.MaxSignLatency < 0 {
+		return errors.New("max_sign_latency can't be negative")
+	}
+	if cfg.BenchVotes < 0 {
+		return errors.New("bench_votes can't be negative")
+	}
+	if cfg.Output != "" && cfg.Output != OutputText && cfg.Output != OutputJSON {
+		return fmt.Errorf("output must be %s or %s, got %q", OutputText, OutputJSON, cfg.Output)
+	}
+	return nil
+}
+
+// LoadRunConfig reads the configuration file at the given path on top of the
+// given configuration, so that the keys missing from the file keep their
+// value. The format of the file is inferred from its extension (.json or
+// .toml), and unknown keys are reported as errors. The values are not
+// validated, as they may still be overridden: see ValidateBasic.
+func LoadRunConfig(path string, cfg RunConfig) (RunConfig, error) {
+	bz, err := os.ReadFile(ExpandPath(path))
+	if err != nil {
+		return cfg, err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(bz))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return cfg, fmt.Errorf("invalid config file %v: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(bz), &cfg)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid config file %v: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return cfg, fmt.Errorf("invalid config file %v: unknown keys %v", path, strings.Join(keys, ", "))
+		}
+	default:
+		return cfg, fmt.Errorf("unsupported config file extension %q (must be .json or .toml)", ext)
+	}
+
+	return cfg, nil
+}
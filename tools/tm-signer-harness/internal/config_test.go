@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRunConfig(t *testing.T) {
+	defaults := RunConfig{
+		BindAddr:       "tcp://127.0.0.1:0",
+		TMHome:         "~/.tendermint",
+		AcceptRetries:  100,
+		AcceptDeadline: Duration(time.Second),
+		ConnDeadline:   Duration(3 * time.Second),
+	}
+	expected := RunConfig{
+		BindAddr:       "tcp://127.0.0.1:61219",
+		TMHome:         "~/.tendermint",
+		AcceptRetries:  10,
+		AcceptDeadline: Duration(time.Second),
+		ConnDeadline:   Duration(3 * time.Second),
+		PingCount:      5,
+		Timeout:        Duration(30 * time.Second),
+	}
+
+	testCases := []struct {
+		name     string
+		file     string
+		contents string
+		errMsg   string
+	}{
+		{
+			"json",
+			"config.json",
+			`{"addr": "tcp://127.0.0.1:61219", "accept_retries": 10, "ping_count": 5, "timeout": "30s"}`,
+			"",
+		},
+		{
+			"toml",
+			"config.toml",
+			"addr = \"tcp://127.0.0.1:61219\"\naccept_retries = 10\nping_count = 5\ntimeout = \"30s\"\n",
+			"",
+		},
+		{
+			"json unknown key",
+			"config.json",
+			`{"addr": "tcp://127.0.0.1:61219", "retries": 10}`,
+			`unknown field "retries"`,
+		},
+		{
+			"toml unknown keys",
+			"config.toml",
+			"retries = 10\nping = 5\n",
+			"unknown keys retries, ping",
+		},
+		{
+			"invalid timeout",
+			"config.json",
+			`{"timeout": "soon"}`,
+			"invalid duration",
+		},
+		{
+			"unsupported extension",
+			"config.yaml",
+			"addr: tcp://127.0.0.1:61219\n",
+			"unsupported config file extension",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, os.WriteFile(path, []byte(tc.contents), 0o600))
+
+			cfg, err := LoadRunConfig(path, defaults)
+			if tc.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, expected, cfg)
+			assert.NoError(t, cfg.ValidateBasic())
+		})
+	}
+
+	cfg := defaults
+	cfg.AcceptRetries = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg = defaults
+	cfg.Timeout = Duration(-time.Second)
+	assert.Error(t, cfg.ValidateBasic())
+	cfg = defaults
+	cfg.Output = "yaml"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg = defaults
+	cfg.AcceptDeadline = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg = defaults
+	cfg.ConnDeadline = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg = defaults
+	cfg.MaxConcurrentConns = -1
+	assert.Error(t, cfg.ValidateBasic())
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"net"
+	"sync"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// connLimitListener bounds the number of signer connections open at once.
+// The signer listener endpoint only accepts a connection when it needs one,
+// so the connections of a signer which reconnects while a test is in flight
+// would otherwise queue up, and be served once the current connection is
+// dropped. Instead, the connections are accepted in the background, and those
+// beyond the limit are closed as soon as they are accepted.
+type connLimitListener struct {
+	net.Listener
+	max    int
+	logger log.Logger
+
+	mtx    sync.Mutex
+	active int
+
+	accepted  chan net.Conn
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+var _ net.Listener = (*connLimitListener)(nil)
+
+func newConnLimitListener(ln net.Listener, max int, logger log.Logger) *connLimitListener {
+	cl := &connLimitListener{
+		Listener: ln,
+		max:      max,
+		logger:   logger,
+		accepted: make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+	go cl.acceptLoop()
+	return cl
+}
+
+func (cl *connLimitListener) acceptLoop() {
+	for {
+		conn, err := cl.Listener.Accept()
+		if err != nil {
+			select {
+			case <-cl.closed:
+				return
+			default:
+			}
+			// accept timeouts and failed handshakes, which the endpoint
+			// retries anyway
+			continue
+		}
+
+		cl.mtx.Lock()
+		if cl.active >= cl.max {
+			active := cl.active
+			cl.mtx.Unlock()
+			cl.logger.Error("Rejecting signer connection: too many concurrent connections",
+				"remote", conn.RemoteAddr(), "active", active, "max", cl.max)
+			_ = conn.Close()
+			continue
+		}
+		cl.active++
+		cl.mtx.Unlock()
+
+		lc := &limitedConn{Conn: conn, release: cl.release}
+		select {
+		case cl.accepted <- lc:
+		case <-cl.closed:
+			_ = lc.Close()
+			return
+		}
+	}
+}
+
+func (cl *connLimitListener) release() {
+	cl.mtx.Lock()
+	cl.active--
+	cl.mtx.Unlock()
+}
+
+// Accept returns the next connection accepted within the limit.
+func (cl *connLimitListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-cl.accepted:
+		return conn, nil
+	case <-cl.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close implements net.Listener.
+func (cl *connLimitListener) Close() error {
+	var err error
+	cl.closeOnce.Do(func() {
+		close(cl.closed)
+		err = cl.Listener.Close()
+	})
+	return err
+}
+
+// limitedConn releases its slot of the connection limit once closed.
+type limitedConn struct {
+	net.Conn
+	release   func()
+	closeOnce sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	c.closeOnce.Do(c.release)
+	return c.Conn.Close()
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestConnLimitListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	cl := newConnLimitListener(ln, 1, log.TestingLogger())
+	defer cl.Close()
+
+	first, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer first.Close()
+	accepted, err := cl.Accept()
+	require.NoError(t, err)
+
+	// a second concurrent dial is refused while the first connection is open
+	second, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	require.Error(t, err)
+	if netErr, ok := err.(net.Error); ok {
+		assert.False(t, netErr.Timeout(), "the second connection was not closed by the listener")
+	}
+
+	// once the first connection is closed, a new one is accepted
+	require.NoError(t, accepted.Close())
+	third, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer third.Close()
+	acceptedc := make(chan net.Conn, 1)
+	go func() {
+		conn, err := cl.Accept()
+		if err == nil {
+			acceptedc <- conn
+		}
+	}()
+	select {
+	case conn := <-acceptedc:
+		conn.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("the third connection was not accepted")
+	}
+
+	require.NoError(t, cl.Close())
+	_, err = cl.Accept()
+	assert.ErrorIs(t, err, net.ErrClosed)
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	tmnet "github.com/tendermint/tendermint/libs/net"
+	p2pconn "github.com/tendermint/tendermint/p2p/conn"
+)
+
+// dialListener is a net.Listener which, instead of accepting the connections
+// of a signer, dials a signer listening for connections. It lets the harness
+// test signers which don't dial out, with the same flow as the signers which
+// do.
+type dialListener struct {
+	proto, addr    string
+	secretConnKey  ed25519.PrivKey
+	acceptDeadline time.Duration
+	connDeadline   time.Duration
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+var _ net.Listener = (*dialListener)(nil)
+
+func newDialListener(cfg TestHarnessConfig) (*dialListener, error) {
+	proto, addr := tmnet.ProtocolAndAddress(cfg.DialAddr)
+	if proto != "tcp" && proto != "unix" {
+		return nil, newTestHarnessError(ErrInvalidParameters, nil, fmt.Sprintf("Unsupported protocol: %s", proto))
+	}
+	return &dialListener{
+		proto:          proto,
+		addr:           addr,
+		secretConnKey:  cfg.SecretConnKey,
+		acceptDeadline: cfg.AcceptDeadline,
+		connDeadline:   cfg.ConnDeadline,
+		closed:         make(chan struct{}),
+	}, nil
+}
+
+// Accept dials the signer. A failed attempt takes at least the accept
+// deadline, as with a listener, so that the attempts are paced.
+func (ln *dialListener) Accept() (net.Conn, error) {
+	select {
+	case <-ln.closed:
+		return nil, net.ErrClosed
+	default:
+	}
+
+	start := time.Now()
+	conn, err := ln.dial()
+	if err != nil {
+		select {
+		case <-time.After(time.Until(start.Add(ln.acceptDeadline))):
+		case <-ln.closed:
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (ln *dialListener) dial() (net.Conn, error) {
+	conn, err := net.DialTimeout(ln.proto, ln.addr, ln.acceptDeadline)
+	if err != nil {
+		return nil, err
+	}
+	if ln.proto == "tcp" {
+		if err := conn.SetDeadline(time.Now().Add(ln.connDeadline)); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		sc, err := p2pconn.MakeSecretConnection(conn, ln.secretConnKey)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = sc
+	}
+	return &deadlineConn{Conn: conn, timeout: ln.connDeadline}, nil
+}
+
+// Close implements net.Listener. Dialing stops, but the established
+// connection is left open.
+func (ln *dialListener) Close() error {
+	ln.closeOnce.Do(func() { close(ln.closed) })
+	return nil
+}
+
+// Addr returns the address of the signer.
+func (ln *dialListener) Addr() net.Addr {
+	return dialAddr{proto: ln.proto, addr: ln.addr}
+}
+
+type dialAddr struct {
+	proto, addr string
+}
+
+func (a dialAddr) Network() string { return a.proto }
+func (a dialAddr) String() string  { return a.addr }
+
+// deadlineConn sets the deadline of each read and write, as the listeners of
+// the privval package do for the connections they accept.
+type deadlineConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *deadlineConn) Read(b []byte) (int, error) {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *deadlineConn) Write(b []byte) (int, error) {
+	if err := c.Conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
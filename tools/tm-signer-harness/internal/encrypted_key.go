@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/armor"
+	"github.com/tendermint/tendermint/crypto/xsalsa20symmetric"
+)
+
+// PassphraseEnvVar is the environment variable from which the passphrase of
+// encrypted keys is read, if set.
+const PassphraseEnvVar = "TM_SIGNER_HARNESS_PASSPHRASE"
+
+const (
+	encryptedKeyBlockType = "TENDERMINT SIGNING KEY"
+	encryptedKeyKDF       = "scrypt"
+
+	// the parameters recommended for interactive logins in 2017
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	scryptSaltLen = 16
+	// the length of the secret of xsalsa20symmetric
+	scryptKeyLen = 32
+)
+
+// EncryptKey encrypts the raw signing key of the given type with a secret
+// derived from the passphrase with scrypt. The encrypted key is ASCII armored,
+// with the type of the key, the KDF and its salt in the headers.
+func EncryptKey(raw []byte, keyType, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase")
+	}
+	salt := crypto.CRandBytes(scryptSaltLen)
+	secret, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"type": keyType,
+		"kdf":  encryptedKeyKDF,
+		"salt": hex.EncodeToString(salt),
+	}
+	return []byte(armor.EncodeArmor(encryptedKeyBlockType, headers, xsalsa20symmetric.EncryptSymmetric(raw, secret))), nil
+}
+
+// DecryptKey decrypts a key encrypted by EncryptKey, returning the raw
+// signing key and its type.
+func DecryptKey(bz []byte, passphrase string) (raw []byte, keyType string, err error) {
+	blockType, headers, ciphertext, err := armor.DecodeArmor(string(bz))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid encrypted key: %w", err)
+	}
+	if blockType != encryptedKeyBlockType {
+		return nil, "", fmt.Errorf("unexpected block type %q", blockType)
+	}
+	if kdf := headers["kdf"]; kdf != encryptedKeyKDF {
+		return nil, "", fmt.Errorf("unsupported KDF %q", kdf)
+	}
+	salt, err := hex.DecodeString(headers["salt"])
+	if err != nil || len(salt) == 0 {
+		return nil, "", errors.New("invalid salt")
+	}
+	secret, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, "", err
+	}
+	raw, err = xsalsa20symmetric.DecryptSymmetric(ciphertext, secret)
+	if err != nil {
+		// most likely a wrong passphrase
+		return nil, "", fmt.Errorf("failed to decrypt the key: %w", err)
+	}
+	return raw, headers["type"], nil
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+func TestEncryptKeyRoundTrip(t *testing.T) {
+	key := ed25519.GenPrivKeyFromSecret([]byte("known key"))
+	bz, err := EncryptKey(key[:32], KeyTypeEd25519, "correct horse")
+	require.NoError(t, err)
+	assert.NotContains(t, string(bz), string(key[:32]))
+
+	raw, keyType, err := DecryptKey(bz, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(key[:32]), raw)
+	assert.Equal(t, KeyTypeEd25519, keyType)
+
+	_, _, err = DecryptKey(bz, "wrong horse")
+	assert.Error(t, err)
+	_, _, err = DecryptKey([]byte("not an encrypted key"), "correct horse")
+	assert.Error(t, err)
+	_, err = EncryptKey(key[:32], KeyTypeEd25519, "")
+	assert.Error(t, err)
+}
+
+func TestExtractKeyEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	key := ed25519.GenPrivKey()
+	tmhome := makeTMHome(t, dir, "val0", key)
+
+	output := filepath.Join(dir, "val0.key")
+	require.NoError(t, ExtractKey(tmhome, output, KeyTypeAuto, "passphrase"))
+	bz, err := os.ReadFile(output)
+	require.NoError(t, err)
+	raw, keyType, err := DecryptKey(bz, "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(key[:32]), raw)
+	assert.Equal(t, KeyTypeEd25519, keyType)
+}
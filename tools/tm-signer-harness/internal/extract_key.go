@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmos "github.com/tendermint/tendermint/libs/os"
+	"github.com/tendermint/tendermint/privval"
+)
+
+// KeyExtraction is the outcome of the extraction of the signing key of a
+// Tendermint home directory.
+type KeyExtraction struct {
+	TMHome string
+	Output string
+	Err    error
+}
+
+// Key types which can be given to ExtractKey.
+const (
+	// KeyTypeAuto detects the type of the key from the key file.
+	KeyTypeAuto      = "auto"
+	KeyTypeEd25519   = ed25519.KeyType
+	KeyTypeSecp256k1 = secp256k1.KeyType
+)
+
+// ExtractKey writes the signing key of the Tendermint instance at tmhome to
+// outputPath, in the format expected by the remote signers: the 32-byte seed
+// of an ed25519 private key, or the 32-byte secret of a secp256k1 private
+// key. Unless keyType is KeyTypeAuto, the key must be of the given type. If
+// passphrase is not empty, the key is written encrypted with it instead (see
+// EncryptKey).
+func ExtractKey(tmhome, outputPath, keyType, passphrase string) error {
+	keyFile := filepath.Join(ExpandPath(tmhome), "config", "priv_validator_key.json")
+	bz, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	// unlike privval.LoadFilePV, doesn't exit on an invalid key file
+	var pvKey privval.FilePVKey
+	if err := tmjson.Unmarshal(bz, &pvKey); err != nil {
+		return fmt.Errorf("error reading PrivValidator key from %v: %w", keyFile, err)
+	}
+
+	var raw []byte
+	switch pk := pvKey.PrivKey.(type) {
+	case ed25519.PrivKey:
+		raw = pk[:32]
+	case secp256k1.PrivKey:
+		raw = pk
+	case nil:
+		return fmt.Errorf("no private key in %v", keyFile)
+	default:
+		return fmt.Errorf("unsupported key type %s in %v", pk.Type(), keyFile)
+	}
+	if keyType != KeyTypeAuto && keyType != pvKey.PrivKey.Type() {
+		return fmt.Errorf("expected key type %s, got %s in %v", keyType, pvKey.PrivKey.Type(), keyFile)
+	}
+	if passphrase != "" {
+		if raw, err = EncryptKey(raw, pvKey.PrivKey.Type(), passphrase); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(ExpandPath(outputPath), raw, 0o600)
+}
+
+// ValidateKeyType checks that keyType can be given to ExtractKey.
+func ValidateKeyType(keyType string) error {
+	switch keyType {
+	case KeyTypeAuto, KeyTypeEd25519, KeyTypeSecp256k1:
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %q (expected %s, %s or %s)",
+			keyType, KeyTypeAuto, KeyTypeEd25519, KeyTypeSecp256k1)
+	}
+}
+
+// ExtractKeys extracts the signing key of each of the Tendermint home
+// directories to outputDir, named after the base name of the home directory
+// (e.g. ~/nodes/val0 is written to <outputDir>/val0.key). Each extraction is
+// independent: the failure of one doesn't prevent the others. The keys are
+// encrypted if passphrase is not empty, as with ExtractKey.
+func ExtractKeys(tmhomes []string, outputDir, keyType, passphrase string) []KeyExtraction {
+	results := make([]KeyExtraction, len(tmhomes))
+	outputs := make(map[string]string, len(tmhomes))
+	for i, tmhome := range tmhomes {
+		name := filepath.Base(filepath.Clean(ExpandPath(tmhome)))
+		output := filepath.Join(outputDir, name+".key")
+		results[i] = KeyExtraction{TMHome: tmhome, Output: output}
+		if other, ok := outputs[output]; ok {
+			results[i].Err = fmt.Errorf("%v has the same name as %v", tmhome, other)
+			continue
+		}
+		outputs[output] = tmhome
+		results[i].Err = ExtractKey(tmhome, output, keyType, passphrase)
+	}
+	return results
+}
+
+// ListTMHomes returns the subdirectories of dir which are Tendermint home
+// directories, i.e. which hold a validator key.
+func ListTMHomes(dir string) ([]string, error) {
+	dir = ExpandPath(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var tmhomes []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		tmhome := filepath.Join(dir, entry.Name())
+		if tmos.FileExists(filepath.Join(tmhome, "config", "priv_validator_key.json")) {
+			tmhomes = append(tmhomes, tmhome)
+		}
+	}
+	return tmhomes, nil
+}
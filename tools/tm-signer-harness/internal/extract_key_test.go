@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/sr25519"
+	"github.com/tendermint/tendermint/privval"
+)
+
+func makeTMHome(t *testing.T, dir, name string, privKey crypto.PrivKey) string {
+	tmhome := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmhome, "config"), 0o700))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmhome, "data"), 0o700))
+	privval.NewFilePV(
+		privKey,
+		filepath.Join(tmhome, "config", "priv_validator_key.json"),
+		filepath.Join(tmhome, "data", "priv_validator_state.json"),
+	).Save()
+	return tmhome
+}
+
+func TestExtractKeys(t *testing.T) {
+	dir := t.TempDir()
+	key0, key1 := ed25519.GenPrivKey(), ed25519.GenPrivKey()
+	makeTMHome(t, dir, "val0", key0)
+	makeTMHome(t, dir, "val1", key1)
+	makeTMHome(t, dir, "val2", sr25519.GenPrivKey())
+	broken := filepath.Join(dir, "val3", "config")
+	require.NoError(t, os.MkdirAll(broken, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(broken, "priv_validator_key.json"), []byte("{"), 0o600))
+	// not a Tendermint home directory
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "other"), 0o700))
+
+	tmhomes, err := ListTMHomes(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "val0"),
+		filepath.Join(dir, "val1"),
+		filepath.Join(dir, "val2"),
+		filepath.Join(dir, "val3"),
+	}, tmhomes)
+
+	// the same home directory twice can't be written to the same output
+	tmhomes = append(tmhomes, filepath.Join(dir, "val0")+"/")
+	outputDir := t.TempDir()
+	results := ExtractKeys(tmhomes, outputDir, KeyTypeAuto, "")
+	require.Len(t, results, 5)
+
+	for i, key := range []ed25519.PrivKey{key0, key1} {
+		require.NoError(t, results[i].Err)
+		bz, err := os.ReadFile(results[i].Output)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(key[:32]), bz)
+	}
+	assert.Equal(t, filepath.Join(outputDir, "val0.key"), results[0].Output)
+	assert.ErrorContains(t, results[2].Err, "unsupported key type")
+	assert.Error(t, results[3].Err)
+	assert.ErrorContains(t, results[4].Err, "same name")
+	for _, res := range results[2:4] {
+		assert.NoFileExists(t, res.Output)
+	}
+}
+
+func TestExtractKeySecp256k1(t *testing.T) {
+	dir := t.TempDir()
+	key := secp256k1.GenPrivKey()
+	tmhome := makeTMHome(t, dir, "val0", key)
+	edHome := makeTMHome(t, dir, "val1", ed25519.GenPrivKey())
+
+	for _, keyType := range []string{KeyTypeAuto, KeyTypeSecp256k1} {
+		output := filepath.Join(dir, keyType+".key")
+		require.NoError(t, ExtractKey(tmhome, output, keyType, ""), keyType)
+		bz, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(key), bz, keyType)
+	}
+
+	// the key must be of the given type
+	output := filepath.Join(dir, "mismatch.key")
+	assert.ErrorContains(t, ExtractKey(tmhome, output, KeyTypeEd25519, ""), "expected key type ed25519")
+	assert.ErrorContains(t, ExtractKey(edHome, output, KeyTypeSecp256k1, ""), "expected key type secp256k1")
+	assert.NoFileExists(t, output)
+
+	assert.NoError(t, ValidateKeyType(KeyTypeSecp256k1))
+	assert.Error(t, ValidateKeyType("rsa"))
+}
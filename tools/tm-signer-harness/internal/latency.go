@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"sort"
+	"time"
+)
+
+// latencyStats summarizes a set of observed request latencies.
+type latencyStats struct {
+	Count int
+	Min   time.Duration
+	Max   time.Duration
+	Avg   time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+func newLatencyStats(latencies []time.Duration) latencyStats {
+	if len(latencies) == 0 {
+		return latencyStats{}
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+
+	return latencyStats{
+		Count: len(sorted),
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		Avg:   total / time.Duration(len(sorted)),
+		P50:   percentile(sorted, 50),
+		P95:   percentile(sorted, 95),
+		P99:   percentile(sorted, 99),
+	}
+}
+
+// percentile returns the p-th percentile of the given sorted latencies using
+// the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyStats(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	// insert in reverse order to make sure the stats don't rely on ordering
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := newLatencyStats(latencies)
+	assert.Equal(t, 100, stats.Count)
+	assert.Equal(t, 1*time.Millisecond, stats.Min)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.Equal(t, 50500*time.Microsecond, stats.Avg)
+	assert.Equal(t, 50*time.Millisecond, stats.P50)
+	assert.Equal(t, 95*time.Millisecond, stats.P95)
+	assert.Equal(t, 99*time.Millisecond, stats.P99)
+
+	assert.Equal(t, latencyStats{}, newLatencyStats(nil))
+	assert.Equal(t, 7*time.Millisecond, newLatencyStats([]time.Duration{7 * time.Millisecond}).P99)
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+
+	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
+)
+
+// Directions of the dumped messages, from the point of view of the harness.
+const (
+	protocolDumpSent     = "sent"
+	protocolDumpReceived = "received"
+)
+
+// protocolDumpEntry is a line of the protocol dump.
+type protocolDumpEntry struct {
+	Time      time.Time       `json:"time"`
+	Direction string          `json:"direction"`
+	Type      string          `json:"type"`
+	Message   json.RawMessage `json:"message,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// protocolDumper writes the privval messages exchanged with the signer to a
+// writer, one JSON object per line. The messages are decoded from the
+// plaintext stream, i.e. after decryption by the SecretConnection.
+type protocolDumper struct {
+	mtx sync.Mutex
+	w   io.Writer
+}
+
+func newProtocolDumper(w io.Writer) *protocolDumper {
+	return &protocolDumper{w: w}
+}
+
+func (d *protocolDumper) dump(direction string, frame []byte) {
+	entry := protocolDumpEntry{Time: time.Now().UTC(), Direction: direction}
+	var msg privvalproto.Message
+	if err := msg.Unmarshal(frame); err != nil {
+		entry.Type = "unknown"
+		entry.Error = fmt.Sprintf("failed to decode %d bytes: %v", len(frame), err)
+	} else {
+		entry.Type = protocolMessageType(&msg)
+		js, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(&msg)
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Message = json.RawMessage(js)
+		}
+	}
+
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	_, _ = d.w.Write(append(bz, '\n'))
+}
+
+// protocolMessageType returns the name of the type of the message (e.g.
+// "SignVoteRequest").
+func protocolMessageType(msg *privvalproto.Message) string {
+	if msg.Sum == nil {
+		return "empty"
+	}
+	// the oneof wrappers are named Message_<Type>
+	t := reflect.TypeOf(msg.Sum).Elem()
+	if t.NumField() == 1 {
+		return t.Field(0).Name
+	}
+	return t.Name()
+}
+
+// frameDecoder splits a stream of length-delimited messages (as written by
+// protoio.NewDelimitedWriter) into frames.
+type frameDecoder struct {
+	buf []byte
+}
+
+// write appends data to the stream and returns the frames which have been
+// completed.
+func (fd *frameDecoder) write(data []byte) [][]byte {
+	fd.buf = append(fd.buf, data...)
+	var frames [][]byte
+	for {
+		length, n := binary.Uvarint(fd.buf)
+		if n <= 0 || uint64(len(fd.buf)-n) < length {
+			return frames
+		}
+		end := n + int(length)
+		frames = append(frames, append([]byte(nil), fd.buf[n:end]...))
+		fd.buf = fd.buf[end:]
+	}
+}
+
+// protocolDumpListener wraps the connections it accepts so that their messages
+// are dumped.
+type protocolDumpListener struct {
+	net.Listener
+	dumper *protocolDumper
+}
+
+var _ net.Listener = (*protocolDumpListener)(nil)
+
+// Accept implements net.Listener.
+func (ln *protocolDumpListener) Accept() (net.Conn, error) {
+	conn, err := ln.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &protocolDumpConn{Conn: conn, dumper: ln.dumper}, nil
+}
+
+// protocolDumpConn dumps the messages written to and read from a connection.
+type protocolDumpConn struct {
+	net.Conn
+	dumper   *protocolDumper
+	sent     frameDecoder
+	received frameDecoder
+	// reads and writes may happen concurrently
+	readMtx  sync.Mutex
+	writeMtx sync.Mutex
+}
+
+// Read implements net.Conn.
+func (c *protocolDumpConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.readMtx.Lock()
+		for _, frame := range c.received.write(b[:n]) {
+			c.dumper.dump(protocolDumpReceived, frame)
+		}
+		c.readMtx.Unlock()
+	}
+	return n, err
+}
+
+// Write implements net.Conn.
+func (c *protocolDumpConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.writeMtx.Lock()
+		for _, frame := range c.sent.write(b[:n]) {
+			c.dumper.dump(protocolDumpSent, frame)
+		}
+		c.writeMtx.Unlock()
+	}
+	return n, err
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/protoio"
+	"github.com/tendermint/tendermint/privval"
+	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
+)
+
+func TestFrameDecoder(t *testing.T) {
+	var stream bytes.Buffer
+	w := protoio.NewDelimitedWriter(&stream)
+	msgs := []privvalproto.Message{
+		{Sum: &privvalproto.Message_PingRequest{PingRequest: &privvalproto.PingRequest{}}},
+		{Sum: &privvalproto.Message_PubKeyRequest{PubKeyRequest: &privvalproto.PubKeyRequest{ChainId: "test-chain"}}},
+	}
+	for i := range msgs {
+		_, err := w.WriteMsg(&msgs[i])
+		require.NoError(t, err)
+	}
+
+	// frames are only returned once complete, whatever the chunking
+	var (
+		fd     frameDecoder
+		frames [][]byte
+	)
+	for _, b := range stream.Bytes() {
+		frames = append(frames, fd.write([]byte{b})...)
+	}
+	require.Len(t, frames, len(msgs))
+	require.Empty(t, fd.buf)
+	for i, frame := range frames {
+		var msg privvalproto.Message
+		require.NoError(t, msg.Unmarshal(frame))
+		assert.Equal(t, msgs[i], msg)
+	}
+}
+
+func TestRemoteSignerProtocolDump(t *testing.T) {
+	var dump bytes.Buffer
+	cfg := makeConfig(t, 100, 3)
+	cfg.ProtocolDump = &dump
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		NoError,
+	)
+
+	sent := make(map[string]int)
+	received := make(map[string]int)
+	scanner := bufio.NewScanner(&dump)
+	for scanner.Scan() {
+		var entry protocolDumpEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		require.Empty(t, entry.Error)
+		switch entry.Direction {
+		case protocolDumpSent:
+			sent[entry.Type]++
+		case protocolDumpReceived:
+			received[entry.Type]++
+		default:
+			t.Fatalf("unexpected direction %q", entry.Direction)
+		}
+	}
+	require.NoError(t, scanner.Err())
+
+	assert.Positive(t, sent["PubKeyRequest"])
+	assert.Equal(t, sent["PubKeyRequest"], received["PubKeyResponse"])
+	assert.Positive(t, sent["SignProposalRequest"])
+	assert.Equal(t, sent["SignProposalRequest"], received["SignedProposalResponse"])
+	assert.Positive(t, sent["SignVoteRequest"])
+	assert.Equal(t, sent["SignVoteRequest"], received["SignedVoteResponse"])
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Report is the outcome of a run of the test harness, in a form which can be
+// consumed by scripts.
+type Report struct {
+	Passed bool `json:"passed"`
+	// ExitCode is the TestHarnessError code of the failure, or NoError.
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+	// SecretConnKeyType is the type of the key with which the harness
+	// authenticates the secret connection.
+	SecretConnKeyType string       `json:"secret_conn_key_type"`
+	Steps             []StepReport `json:"steps"`
+}
+
+// StepReport is the outcome of a step of the run: establishing the connection
+// to the signer, or one of the tests.
+type StepReport struct {
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Duration Duration `json:"duration"`
+}
+
+// runStep runs a step of the run and records its outcome.
+func (th *TestHarness) runStep(name string, step func() error) error {
+	start := time.Now()
+	err := step()
+	th.recordStep(name, err == nil, time.Since(start))
+	return err
+}
+
+func (th *TestHarness) recordStep(name string, passed bool, d time.Duration) {
+	th.mtx.Lock()
+	defer th.mtx.Unlock()
+	th.steps = append(th.steps, StepReport{Name: name, Passed: passed, Duration: Duration(d)})
+}
+
+// Report returns the outcome of the run. It's only complete once the harness
+// has shut down.
+func (th *TestHarness) Report() Report {
+	th.mtx.Lock()
+	defer th.mtx.Unlock()
+	r := Report{
+		Passed:            th.exitCode == NoError,
+		ExitCode:          th.exitCode,
+		SecretConnKeyType: th.connKeyType,
+		Steps:             append([]StepReport{}, th.steps...),
+	}
+	if th.err != nil {
+		r.Error = th.err.Error()
+	}
+	return r
+}
+
+func writeReport(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
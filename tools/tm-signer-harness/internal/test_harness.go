@@ -3,9 +3,11 @@ package internal
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/signal"
+	"sync"
 	"time"
 
 	"github.com/tendermint/tendermint/crypto/tmhash"
@@ -16,24 +18,30 @@ import (
 
 	"github.com/tendermint/tendermint/libs/log"
 	tmnet "github.com/tendermint/tendermint/libs/net"
-	tmos "github.com/tendermint/tendermint/libs/os"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
 )
 
 // Test harness error codes (which act as exit codes when the test harness fails).
 const (
-	NoError                    int = iota // 0
-	ErrInvalidParameters                  // 1
-	ErrMaxAcceptRetriesReached            // 2
-	ErrFailedToLoadGenesisFile            // 3
-	ErrFailedToCreateListener             // 4
-	ErrFailedToStartListener              // 5
-	ErrInterrupted                        // 6
-	ErrOther                              // 7
-	ErrTestPublicKeyFailed                // 8
-	ErrTestSignProposalFailed             // 9
-	ErrTestSignVoteFailed                 // 10
+	NoError                       int = iota // 0
+	ErrInvalidParameters                     // 1
+	ErrMaxAcceptRetriesReached               // 2
+	ErrFailedToLoadGenesisFile               // 3
+	ErrFailedToCreateListener                // 4
+	ErrFailedToStartListener                 // 5
+	ErrInterrupted                           // 6
+	ErrOther                                 // 7
+	ErrTestPublicKeyFailed                   // 8
+	ErrTestSignProposalFailed                // 9
+	ErrTestSignVoteFailed                    // 10
+	ErrTestPingFailed                        // 11
+	ErrTestGenesisPublicKeyFailed            // 12
+	ErrTimedOut                              // 13
+	ErrTestSecondChainIDFailed               // 14
+	ErrTestReconnectFailed                   // 15
+	ErrTestSignLatencyFailed                 // 16
+	ErrTestBenchVotesFailed                  // 17
 )
 
 var voteTypes = []tmproto.SignedMsgType{tmproto.PrevoteType, tmproto.PrecommitType}
@@ -52,13 +60,36 @@ var _ error = (*TestHarnessError)(nil)
 // with this version of Tendermint.
 type TestHarness struct {
 	addr             string
+	dial             bool
 	signerClient     *privval.SignerClient
+	signerListener   *privval.SignerListenerEndpoint
 	fpv              *privval.FilePV
 	chainID          string
+	secondChainID    string
+	genValidators    []types.GenesisValidator
 	acceptRetries    int
+	pingCount        int
+	timeout          time.Duration
+	acceptDeadline   time.Duration
+	reconnectCount   int
+	reconnectEvery   time.Duration
+	maxSignLatency   time.Duration
+	benchVotes       int
+	signLatencies    []signLatency
 	logger           log.Logger
 	exitWhenComplete bool
-	exitCode         int
+	report           io.Writer
+
+	// mtx guards the outcome of the run, which is recorded by Run and
+	// Shutdown, possibly from different goroutines
+	mtx         sync.Mutex
+	exitCode    int
+	err         error
+	steps       []StepReport
+	connKeyType string
+
+	shutdownOnce sync.Once
+	quit         chan struct{}
 }
 
 // TestHarnessConfig provides configuration to set up a remote signer test
@@ -66,6 +97,10 @@ type TestHarness struct {
 type TestHarnessConfig struct {
 	BindAddr string
 
+	// DialAddr, if not empty, is the address of a signer listening for
+	// connections, which the harness dials instead of listening on BindAddr.
+	DialAddr string
+
 	KeyFile     string
 	StateFile   string
 	GenesisFile string
@@ -74,8 +109,50 @@ type TestHarnessConfig struct {
 	ConnDeadline   time.Duration
 	AcceptRetries  int
 
+	// MaxConcurrentConns is the number of signer connections accepted at
+	// once: a connection beyond it, such as one of a signer reconnecting
+	// while a test is in flight, is rejected. It only applies when listening.
+	// Zero disables the limit.
+	MaxConcurrentConns int
+
 	SecretConnKey ed25519.PrivKey
 
+	// PingCount is the number of ping requests sent to the signer to measure
+	// its round-trip latency. Zero disables the ping test.
+	PingCount int
+
+	// Timeout bounds the whole run, from accepting the connection of the
+	// signer to the end of the last test. Zero disables the timeout.
+	Timeout time.Duration
+
+	// SecondChainID is a chain ID other than the one of the genesis file, for
+	// which the signer is expected to refuse to sign. Empty disables the test.
+	SecondChainID string
+
+	// ReconnectInterval is the interval at which the connection to the signer
+	// is dropped and re-established, ReconnectCount times, while requesting
+	// signatures. Zero disables the reconnect test.
+	ReconnectInterval time.Duration
+	ReconnectCount    int
+
+	// ProtocolDump, if not nil, receives every privval message exchanged with
+	// the signer, decoded as JSON, one per line.
+	ProtocolDump io.Writer
+
+	// MaxSignLatency is the latency budget of a signing request: the harness
+	// fails if any request takes longer, even if the signature is valid. Zero
+	// disables the budget.
+	MaxSignLatency time.Duration
+
+	// BenchVotes is the number of votes the signer is requested to sign, once
+	// the tests have passed, to measure its signing latency under load. Zero
+	// disables the benchmark.
+	BenchVotes int
+
+	// Report, if not nil, receives the JSON report of the run once it has
+	// completed (see Report).
+	Report io.Writer
+
 	ExitWhenComplete bool // Whether or not to call os.Exit when the harness has completed.
 }
 
@@ -103,6 +180,10 @@ func NewTestHarness(logger log.Logger, cfg TestHarnessConfig) (*TestHarness, err
 		return nil, newTestHarnessError(ErrFailedToLoadGenesisFile, err, genesisFile)
 	}
 	logger.Info("Loaded genesis file", "chainID", st.ChainID)
+	if cfg.SecondChainID == st.ChainID {
+		return nil, newTestHarnessError(ErrInvalidParameters, nil,
+			fmt.Sprintf("second chain ID must differ from the genesis chain ID %s", st.ChainID))
+	}
 
 	spv, err := newTestHarnessListener(logger, cfg)
 	if err != nil {
@@ -116,13 +197,27 @@ func NewTestHarness(logger log.Logger, cfg TestHarnessConfig) (*TestHarness, err
 
 	return &TestHarness{
 		addr:             cfg.BindAddr,
+		dial:             cfg.DialAddr != "",
 		signerClient:     signerClient,
+		signerListener:   spv,
 		fpv:              fpv,
 		chainID:          st.ChainID,
+		secondChainID:    cfg.SecondChainID,
+		genValidators:    st.Validators,
 		acceptRetries:    cfg.AcceptRetries,
+		pingCount:        cfg.PingCount,
+		timeout:          cfg.Timeout,
+		acceptDeadline:   cfg.AcceptDeadline,
+		reconnectCount:   cfg.ReconnectCount,
+		reconnectEvery:   cfg.ReconnectInterval,
+		maxSignLatency:   cfg.MaxSignLatency,
+		benchVotes:       cfg.BenchVotes,
 		logger:           logger,
 		exitWhenComplete: cfg.ExitWhenComplete,
+		report:           cfg.Report,
 		exitCode:         0,
+		connKeyType:      cfg.SecretConnKey.Type(),
+		quit:             make(chan struct{}),
 	}, nil
 }
 
@@ -140,12 +235,37 @@ func (th *TestHarness) Run() {
 		}
 	}()
 
+	if th.timeout > 0 {
+		// shutting down closes the connection and the listener, which aborts
+		// any in-flight accept or request
+		timer := time.AfterFunc(th.timeout, func() {
+			th.logger.Error("Test harness timed out", "timeout", th.timeout)
+			th.Shutdown(newTestHarnessError(ErrTimedOut, nil, fmt.Sprintf("timeout=%v", th.timeout)))
+		})
+		defer timer.Stop()
+	}
+
 	th.logger.Info("Starting test harness")
 	accepted := false
 	var startErr error
+	mode, connectStart := "accept", time.Now()
+	if th.dial {
+		mode = "dial"
+	}
 
 	for acceptRetries := th.acceptRetries; acceptRetries > 0; acceptRetries-- {
-		th.logger.Info("Attempting to accept incoming connection", "acceptRetries", acceptRetries)
+		select {
+		case <-th.quit:
+			// already shut down, this only waits for the shutdown to complete
+			th.Shutdown(nil)
+			return
+		default:
+		}
+		if th.dial {
+			th.logger.Info("Attempting to connect to the signer", "acceptRetries", acceptRetries)
+		} else {
+			th.logger.Info("Attempting to accept incoming connection", "acceptRetries", acceptRetries)
+		}
 
 		if err := th.signerClient.WaitForConnection(10 * time.Millisecond); err != nil {
 			// if it wasn't a timeout error
@@ -159,30 +279,69 @@ func (th *TestHarness) Run() {
 			}
 			startErr = err
 		} else {
-			th.logger.Info("Accepted external connection")
+			// the connection is reported apart from the tests, so that a
+			// failure to connect isn't mistaken for a signing failure
+			th.logger.Info("Connection to the signer established", "mode", mode,
+				"attempts", th.acceptRetries-acceptRetries+1, "elapsed", time.Since(connectStart))
+			th.recordStep("connect", true, time.Since(connectStart))
 			accepted = true
 			break
 		}
 	}
 	if !accepted {
+		th.recordStep("connect", false, time.Since(connectStart))
+		th.logger.Error("Failed to establish the connection to the signer", "mode", mode,
+			"elapsed", time.Since(connectStart))
 		th.logger.Error("Maximum accept retries reached", "acceptRetries", th.acceptRetries)
 		th.Shutdown(newTestHarnessError(ErrMaxAcceptRetriesReached, startErr, ""))
 		return
 	}
 
 	// Run the tests
-	if err := th.TestPublicKey(); err != nil {
+	if err := th.runStep("public_key", th.TestPublicKey); err != nil {
+		th.Shutdown(err)
+		return
+	}
+	if err := th.runStep("genesis_public_key", th.TestGenesisPublicKey); err != nil {
+		th.Shutdown(err)
+		return
+	}
+	if err := th.runStep("sign_proposal", th.TestSignProposal); err != nil {
 		th.Shutdown(err)
 		return
 	}
-	if err := th.TestSignProposal(); err != nil {
+	if err := th.runStep("sign_vote", th.TestSignVote); err != nil {
 		th.Shutdown(err)
 		return
 	}
-	if err := th.TestSignVote(); err != nil {
+	if th.secondChainID != "" {
+		if err := th.runStep("second_chain_id", th.TestSecondChainID); err != nil {
+			th.Shutdown(err)
+			return
+		}
+	}
+	if th.reconnectEvery > 0 && th.reconnectCount > 0 {
+		if err := th.runStep("reconnect", th.TestReconnect); err != nil {
+			th.Shutdown(err)
+			return
+		}
+	}
+	if err := th.runStep("sign_latency", th.TestSignLatency); err != nil {
 		th.Shutdown(err)
 		return
 	}
+	if th.pingCount > 0 {
+		if err := th.runStep("ping", th.TestPing); err != nil {
+			th.Shutdown(err)
+			return
+		}
+	}
+	if th.benchVotes > 0 {
+		if err := th.runStep("bench_votes", th.TestBenchVotes); err != nil {
+			th.Shutdown(err)
+			return
+		}
+	}
 	th.logger.Info("SUCCESS! All tests passed.")
 	th.Shutdown(nil)
 }
@@ -209,6 +368,34 @@ func (th *TestHarness) TestPublicKey() error {
 	return nil
 }
 
+// TestGenesisPublicKey validates that the public key of the remote signer
+// belongs to one of the validators in the genesis file. It is skipped if the
+// genesis file does not list any validators (i.e. they are set by the
+// application on InitChain).
+func (th *TestHarness) TestGenesisPublicKey() error {
+	th.logger.Info("TEST: Public key of remote signer against genesis validators")
+	if len(th.genValidators) == 0 {
+		th.logger.Info("SKIPPED: No validators in genesis file")
+		return nil
+	}
+	sck, err := th.signerClient.GetPubKey()
+	if err != nil {
+		return err
+	}
+	for _, val := range th.genValidators {
+		if bytes.Equal(val.PubKey.Bytes(), sck.Bytes()) {
+			th.logger.Info("Remote public key found in genesis", "pubKey", sck, "name", val.Name)
+			return nil
+		}
+	}
+	for _, val := range th.genValidators {
+		th.logger.Error("Genesis", "pubKey", val.PubKey, "name", val.Name)
+	}
+	th.logger.Error("Remote", "pubKey", sck)
+	th.logger.Error("FAILED: Remote public key does not match any genesis validator")
+	return newTestHarnessError(ErrTestGenesisPublicKeyFailed, nil, "")
+}
+
 // TestSignProposal makes sure the remote signer can successfully sign
 // proposals.
 func (th *TestHarness) TestSignProposal() error {
@@ -231,7 +418,7 @@ func (th *TestHarness) TestSignProposal() error {
 	}
 	p := prop.ToProto()
 	propBytes := types.ProposalSignBytes(th.chainID, p)
-	if err := th.signerClient.SignProposal(th.chainID, p); err != nil {
+	if err := th.signProposal(th.chainID, p); err != nil {
 		th.logger.Error("FAILED: Signing of proposal", "err", err)
 		return newTestHarnessError(ErrTestSignProposalFailed, err, "")
 	}
@@ -281,7 +468,7 @@ func (th *TestHarness) TestSignVote() error {
 		v := vote.ToProto()
 		voteBytes := types.VoteSignBytes(th.chainID, v)
 		// sign the vote
-		if err := th.signerClient.SignVote(th.chainID, v); err != nil {
+		if err := th.signVote(th.chainID, v); err != nil {
 			th.logger.Error("FAILED: Signing of vote", "err", err)
 			return newTestHarnessError(ErrTestSignVoteFailed, err, fmt.Sprintf("voteType=%d", voteType))
 		}
@@ -308,11 +495,292 @@ func (th *TestHarness) TestSignVote() error {
 	return nil
 }
 
+// TestSecondChainID makes sure the remote signer, which has just signed for
+// the chain of the genesis file, refuses to sign a proposal and a vote for
+// another chain ID. A signer bound to a single chain must reject such requests,
+// as signing them would allow the key to be used on a chain it does not
+// validate.
+func (th *TestHarness) TestSecondChainID() error {
+	th.logger.Info("TEST: Signing for a different chain ID", "chainID", th.secondChainID)
+	hash := tmhash.Sum([]byte("hash"))
+	blockID := types.BlockID{
+		Hash: hash,
+		PartSetHeader: types.PartSetHeader{
+			Hash:  hash,
+			Total: 1000000,
+		},
+	}
+
+	prop := &types.Proposal{
+		Type:      tmproto.ProposalType,
+		Height:    102,
+		Round:     0,
+		POLRound:  -1,
+		BlockID:   blockID,
+		Timestamp: time.Now(),
+	}
+	if err := th.signProposal(th.secondChainID, prop.ToProto()); err != nil {
+		th.logger.Info("Signer refused to sign proposal", "chainID", th.secondChainID, "err", err)
+	} else {
+		th.logger.Error("FAILED: Signer signed proposal", "chainID", th.secondChainID)
+		return newTestHarnessError(ErrTestSecondChainIDFailed, nil, "signed proposal for chain "+th.secondChainID)
+	}
+
+	for _, voteType := range voteTypes {
+		vote := &types.Vote{
+			Type:             voteType,
+			Height:           102,
+			Round:            0,
+			BlockID:          blockID,
+			ValidatorIndex:   0,
+			ValidatorAddress: tmhash.SumTruncated([]byte("addr")),
+			Timestamp:        time.Now(),
+		}
+		if err := th.signVote(th.secondChainID, vote.ToProto()); err != nil {
+			th.logger.Info("Signer refused to sign vote", "chainID", th.secondChainID, "type", voteType, "err", err)
+		} else {
+			th.logger.Error("FAILED: Signer signed vote", "chainID", th.secondChainID, "type", voteType)
+			return newTestHarnessError(ErrTestSecondChainIDFailed, nil,
+				fmt.Sprintf("signed vote for chain %s (voteType=%d)", th.secondChainID, voteType))
+		}
+	}
+	return nil
+}
+
+// TestReconnect repeatedly drops the connection to the remote signer, waits
+// for it to reconnect and requests a vote signature at a new height. After each
+// reconnection, it also requests a conflicting vote for the previous height,
+// which the signer must refuse to sign: its double signing protection must
+// survive reconnections.
+func (th *TestHarness) TestReconnect() error {
+	th.logger.Info("TEST: Reconnect churn", "count", th.reconnectCount, "interval", th.reconnectEvery)
+	var (
+		succeeded, failed int
+		prev              *types.Vote
+	)
+	for i := 0; i < th.reconnectCount; i++ {
+		time.Sleep(th.reconnectEvery)
+		th.signerListener.DropConnection()
+		if err := th.waitForReconnection(); err != nil {
+			th.logger.Error("Signer did not reconnect", "iteration", i, "err", err)
+			failed++
+			continue
+		}
+
+		if prev != nil {
+			conflicting := *prev
+			conflicting.BlockID = testBlockID(fmt.Sprintf("conflicting-%d", i))
+			if err := th.signVote(th.chainID, conflicting.ToProto()); err == nil {
+				th.logger.Error("FAILED: Signer double signed across a reconnection", "height", prev.Height)
+				return newTestHarnessError(ErrTestReconnectFailed, nil,
+					fmt.Sprintf("double signed at height %d", prev.Height))
+			}
+		}
+
+		vote := &types.Vote{
+			Type:             tmproto.PrecommitType,
+			Height:           int64(110 + i),
+			Round:            0,
+			BlockID:          testBlockID(fmt.Sprintf("block-%d", i)),
+			ValidatorIndex:   0,
+			ValidatorAddress: tmhash.SumTruncated([]byte("addr")),
+			Timestamp:        time.Now(),
+		}
+		v := vote.ToProto()
+		if err := th.signVote(th.chainID, v); err != nil {
+			th.logger.Error("Signing of vote failed after reconnection", "iteration", i, "err", err)
+			failed++
+			continue
+		}
+		vote.Signature = v.Signature
+		vote.Timestamp = v.Timestamp
+		prev = vote
+		succeeded++
+	}
+
+	th.logger.Info("Reconnect churn", "succeeded", succeeded, "failed", failed)
+	if failed > 0 {
+		th.logger.Error("FAILED: Signer did not recover from every reconnection", "failed", failed)
+		return newTestHarnessError(ErrTestReconnectFailed, nil,
+			fmt.Sprintf("%d of %d reconnections failed", failed, th.reconnectCount))
+	}
+	return nil
+}
+
+// waitForReconnection waits for the signer to re-establish the connection,
+// for as long as the accept retries allow.
+func (th *TestHarness) waitForReconnection() error {
+	var err error
+	for retries := th.acceptRetries; retries > 0; retries-- {
+		if err = th.signerClient.WaitForConnection(th.acceptDeadline); err == nil {
+			return nil
+		}
+		if _, ok := err.(timeoutError); !ok {
+			return err
+		}
+	}
+	return err
+}
+
+func testBlockID(seed string) types.BlockID {
+	hash := tmhash.Sum([]byte(seed))
+	return types.BlockID{
+		Hash: hash,
+		PartSetHeader: types.PartSetHeader{
+			Hash:  hash,
+			Total: 1000000,
+		},
+	}
+}
+
+// signLatency is the latency of a signing request.
+type signLatency struct {
+	op      string
+	latency time.Duration
+}
+
+// signProposal requests the signature of a proposal, recording the latency of
+// the request.
+func (th *TestHarness) signProposal(chainID string, p *tmproto.Proposal) error {
+	start := time.Now()
+	err := th.signerClient.SignProposal(chainID, p)
+	th.signLatencies = append(th.signLatencies, signLatency{
+		op:      fmt.Sprintf("proposal (height=%d, chainID=%s)", p.Height, chainID),
+		latency: time.Since(start),
+	})
+	return err
+}
+
+// signVote requests the signature of a vote, recording the latency of the
+// request.
+func (th *TestHarness) signVote(chainID string, v *tmproto.Vote) error {
+	start := time.Now()
+	err := th.signerClient.SignVote(chainID, v)
+	th.signLatencies = append(th.signLatencies, signLatency{
+		op:      fmt.Sprintf("vote (voteType=%d, height=%d, chainID=%s)", v.Type, v.Height, chainID),
+		latency: time.Since(start),
+	})
+	return err
+}
+
+// TestSignLatency reports the latency of the signing requests of the previous
+// tests, whether they were signed or refused, and fails if the worst one
+// exceeds the latency budget, if any.
+func (th *TestHarness) TestSignLatency() error {
+	th.logger.Info("TEST: Signing latency", "budget", th.maxSignLatency)
+	if len(th.signLatencies) == 0 {
+		th.logger.Info("SKIPPED: No signing requests")
+		return nil
+	}
+
+	latencies := make([]time.Duration, len(th.signLatencies))
+	worst := th.signLatencies[0]
+	for i, l := range th.signLatencies {
+		latencies[i] = l.latency
+		if l.latency > worst.latency {
+			worst = l
+		}
+	}
+	stats := newLatencyStats(latencies)
+	th.logger.Info(
+		"Signing latency",
+		"count", stats.Count,
+		"min", stats.Min,
+		"max", stats.Max,
+		"p50", stats.P50,
+		"p95", stats.P95,
+		"p99", stats.P99,
+		"worst", worst.op,
+	)
+
+	if th.maxSignLatency > 0 && worst.latency > th.maxSignLatency {
+		th.logger.Error("FAILED: Signing request exceeded the latency budget",
+			"op", worst.op, "latency", worst.latency, "budget", th.maxSignLatency)
+		return newTestHarnessError(ErrTestSignLatencyFailed, nil,
+			fmt.Sprintf("%s took %v, budget %v", worst.op, worst.latency, th.maxSignLatency))
+	}
+	return nil
+}
+
+// TestPing sends a number of lightweight ping requests to the remote signer
+// and reports the round-trip latency percentiles. This measures the latency of
+// the connection and the signer itself, independent of any signing work.
+func (th *TestHarness) TestPing() error {
+	th.logger.Info("TEST: Ping round-trip latency", "count", th.pingCount)
+	latencies := make([]time.Duration, 0, th.pingCount)
+	for i := 0; i < th.pingCount; i++ {
+		start := time.Now()
+		if err := th.signerClient.Ping(); err != nil {
+			th.logger.Error("FAILED: Ping", "iteration", i, "err", err)
+			return newTestHarnessError(ErrTestPingFailed, err, fmt.Sprintf("iteration=%d", i))
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	stats := newLatencyStats(latencies)
+	th.logger.Info(
+		"Ping round-trip latency",
+		"count", stats.Count,
+		"min", stats.Min,
+		"max", stats.Max,
+		"p50", stats.P50,
+		"p95", stats.P95,
+		"p99", stats.P99,
+	)
+	return nil
+}
+
+// TestBenchVotes requests the remote signer to sign a number of sequential
+// votes and reports the latency percentiles of the signing requests. The
+// heights of the votes are strictly increasing and above those of the previous
+// tests, so that the double signing protection of the signer doesn't refuse
+// any of them. It stops at the first failed request.
+func (th *TestHarness) TestBenchVotes() error {
+	th.logger.Info("TEST: Vote signing benchmark", "count", th.benchVotes)
+	// the reconnect test signs votes at heights starting at 110
+	startHeight := int64(110 + th.reconnectCount)
+	latencies := make([]time.Duration, 0, th.benchVotes)
+	for i := 0; i < th.benchVotes; i++ {
+		vote := &types.Vote{
+			Type:             tmproto.PrecommitType,
+			Height:           startHeight + int64(i),
+			Round:            0,
+			BlockID:          testBlockID(fmt.Sprintf("bench-%d", i)),
+			ValidatorIndex:   0,
+			ValidatorAddress: tmhash.SumTruncated([]byte("addr")),
+			Timestamp:        time.Now(),
+		}
+		start := time.Now()
+		if err := th.signerClient.SignVote(th.chainID, vote.ToProto()); err != nil {
+			th.logger.Error("FAILED: Signing of vote", "iteration", i, "height", vote.Height, "err", err)
+			return newTestHarnessError(ErrTestBenchVotesFailed, err,
+				fmt.Sprintf("iteration=%d, height=%d", i, vote.Height))
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	stats := newLatencyStats(latencies)
+	th.logger.Info(
+		"Vote signing latency",
+		"count", stats.Count,
+		"min", stats.Min,
+		"max", stats.Max,
+		"avg", stats.Avg,
+		"p99", stats.P99,
+	)
+	return nil
+}
+
 // Shutdown will kill the test harness and attempt to close all open sockets
 // gracefully. If the supplied error is nil, it is assumed that the exit code
 // should be 0. If err is not nil, it will exit with an exit code related to the
-// error.
+// error. Only the first call has any effect, so the exit code is the one of the
+// first error.
 func (th *TestHarness) Shutdown(err error) {
+	th.shutdownOnce.Do(func() { th.shutdown(err) })
+}
+
+func (th *TestHarness) shutdown(err error) {
+	close(th.quit)
+
 	var exitCode int
 
 	if err == nil {
@@ -322,7 +790,10 @@ func (th *TestHarness) Shutdown(err error) {
 	} else {
 		exitCode = ErrOther
 	}
+	th.mtx.Lock()
 	th.exitCode = exitCode
+	th.err = err
+	th.mtx.Unlock()
 
 	// in case sc.Stop() takes too long
 	if th.exitWhenComplete {
@@ -337,6 +808,15 @@ func (th *TestHarness) Shutdown(err error) {
 	if err != nil {
 		th.logger.Error("Failed to cleanly stop listener: %s", err.Error())
 	}
+	if err := th.signerListener.Stop(); err != nil {
+		th.logger.Error("Failed to stop listener", "err", err)
+	}
+
+	if th.report != nil {
+		if err := writeReport(th.report, th.Report()); err != nil {
+			th.logger.Error("Failed to write the report", "err", err)
+		}
+	}
 
 	if th.exitWhenComplete {
 		os.Exit(exitCode)
@@ -345,14 +825,25 @@ func (th *TestHarness) Shutdown(err error) {
 
 // newTestHarnessListener creates our client instance which we will use for testing.
 func newTestHarnessListener(logger log.Logger, cfg TestHarnessConfig) (*privval.SignerListenerEndpoint, error) {
+	if cfg.DialAddr != "" {
+		dl, err := newDialListener(cfg)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Dialing signer", "proto", dl.proto, "addr", dl.addr)
+		var svln net.Listener = dl
+		if cfg.ProtocolDump != nil {
+			svln = &protocolDumpListener{Listener: svln, dumper: newProtocolDumper(cfg.ProtocolDump)}
+		}
+		return privval.NewSignerListenerEndpoint(logger, svln), nil
+	}
+
 	proto, addr := tmnet.ProtocolAndAddress(cfg.BindAddr)
 	if proto == "unix" {
-		// make sure the socket doesn't exist - if so, try to delete it
-		if tmos.FileExists(addr) {
-			if err := os.Remove(addr); err != nil {
-				logger.Error("Failed to remove existing Unix domain socket", "addr", addr)
-				return nil, err
-			}
+		// a socket left by a crashed run would make the bind fail
+		if err := removeStaleSocket(addr); err != nil {
+			logger.Error("Failed to remove existing Unix domain socket", "addr", addr, "err", err)
+			return nil, err
 		}
 	}
 	ln, err := net.Listen(proto, addr)
@@ -378,9 +869,31 @@ func newTestHarnessListener(logger log.Logger, cfg TestHarnessConfig) (*privval.
 		logger.Error("Unsupported protocol (must be unix:// or tcp://)", "proto", proto)
 		return nil, newTestHarnessError(ErrInvalidParameters, nil, fmt.Sprintf("Unsupported protocol: %s", proto))
 	}
+	if cfg.MaxConcurrentConns > 0 {
+		svln = newConnLimitListener(svln, cfg.MaxConcurrentConns, logger)
+	}
+	if cfg.ProtocolDump != nil {
+		svln = &protocolDumpListener{Listener: svln, dumper: newProtocolDumper(cfg.ProtocolDump)}
+	}
 	return privval.NewSignerListenerEndpoint(logger, svln), nil
 }
 
+// removeStaleSocket removes the Unix domain socket at the given path, if any.
+// The socket is removed when the listener is closed, so it only exists if a
+// previous run didn't shut down cleanly. Any other kind of file is left alone.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a Unix domain socket", path)
+	}
+	return os.Remove(path)
+}
+
 func newTestHarnessError(code int, err error, info string) *TestHarnessError {
 	return &TestHarnessError{
 		Code: code,
@@ -410,6 +923,20 @@ func (e *TestHarnessError) Error() string {
 		msg = "Proposal signing validation test failed"
 	case ErrTestSignVoteFailed:
 		msg = "Vote signing validation test failed"
+	case ErrTestPingFailed:
+		msg = "Ping latency test failed"
+	case ErrTestGenesisPublicKeyFailed:
+		msg = "Genesis public key validation test failed"
+	case ErrTimedOut:
+		msg = "Test harness timed out"
+	case ErrTestSecondChainIDFailed:
+		msg = "Second chain ID signing test failed"
+	case ErrTestReconnectFailed:
+		msg = "Reconnect churn test failed"
+	case ErrTestSignLatencyFailed:
+		msg = "Signing latency test failed"
+	case ErrTestBenchVotesFailed:
+		msg = "Vote signing benchmark failed"
 	default:
 		msg = "Unknown error"
 	}
@@ -1,8 +1,15 @@
 package internal
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,7 +19,10 @@ import (
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/libs/log"
+	tmnet "github.com/tendermint/tendermint/libs/net"
+	p2pconn "github.com/tendermint/tendermint/p2p/conn"
 	"github.com/tendermint/tendermint/privval"
+	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -82,6 +92,20 @@ func TestRemoteSignerTestHarnessMaxAcceptRetriesReached(t *testing.T) {
 	assert.Equal(t, ErrMaxAcceptRetriesReached, th.exitCode)
 }
 
+func TestRemoteSignerTestHarnessTimedOut(t *testing.T) {
+	// without a timeout, waiting for the signer would take 10s
+	cfg := makeConfig(t, 1, 1000)
+	cfg.Timeout = 200 * time.Millisecond
+	defer cleanup(cfg)
+
+	th, err := NewTestHarness(log.TestingLogger(), cfg)
+	require.NoError(t, err)
+	start := time.Now()
+	th.Run()
+	assert.Equal(t, ErrTimedOut, th.exitCode)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
 func TestRemoteSignerTestHarnessSuccessfulRun(t *testing.T) {
 	harnessTest(
 		t,
@@ -102,6 +126,27 @@ func TestRemoteSignerPublicKeyCheckFailed(t *testing.T) {
 	)
 }
 
+func TestRemoteSignerGenesisPublicKeyCheckFailed(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	otherKey := ed25519.GenPrivKey().PubKey()
+	genesis := strings.ReplaceAll(
+		genesisFileContents,
+		"ZCsuTjaczEyon70nmKxwvwu+jqrbq5OH3yQjcK0SFxc=",
+		base64.StdEncoding.EncodeToString(otherKey.Bytes()),
+	)
+	genesis = strings.ReplaceAll(genesis, "D08FCA3BA74CF17CBFC15E64F9505302BB0E2748", otherKey.Address().String())
+	os.Remove(cfg.GenesisFile)
+	cfg.GenesisFile = makeTempFile("tm-testharness-genesisfile", genesis)
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		ErrTestGenesisPublicKeyFailed,
+	)
+}
+
 func TestRemoteSignerProposalSigningFailed(t *testing.T) {
 	harnessTest(
 		t,
@@ -122,6 +167,296 @@ func TestRemoteSignerVoteSigningFailed(t *testing.T) {
 	)
 }
 
+func TestRemoteSignerPingLatency(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.PingCount = 10
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		NoError,
+	)
+}
+
+func TestRemoteSignerSecondChainIDRefused(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.SecondChainID = "other-chain"
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		NoError,
+	)
+}
+
+func TestRemoteSignerSecondChainIDSigned(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.SecondChainID = "other-chain"
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			ss := newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+			// sign for whichever chain ID is requested
+			ss.SetRequestHandler(func(
+				privVal types.PrivValidator,
+				req privvalproto.Message,
+				chainID string,
+			) (privvalproto.Message, error) {
+				switch r := req.Sum.(type) {
+				case *privvalproto.Message_SignVoteRequest:
+					chainID = r.SignVoteRequest.ChainId
+				case *privvalproto.Message_SignProposalRequest:
+					chainID = r.SignProposalRequest.ChainId
+				}
+				return privval.DefaultValidationRequestHandler(privVal, req, chainID)
+			})
+			return ss
+		},
+		ErrTestSecondChainIDFailed,
+	)
+}
+
+func TestRemoteSignerSecondChainIDSameAsGenesis(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	defer cleanup(cfg)
+	cfg.SecondChainID = "test-chain-0XwP5E"
+
+	_, err := NewTestHarness(log.TestingLogger(), cfg)
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidParameters, err.(*TestHarnessError).Code)
+}
+
+func TestRemoteSignerReconnectChurn(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.ReconnectInterval = 10 * time.Millisecond
+	cfg.ReconnectCount = 3
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			// the file signer has double signing protection
+			return newSignerServer(th, th.fpv)
+		},
+		NoError,
+	)
+}
+
+func TestRemoteSignerReconnectChurnDoubleSign(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.ReconnectInterval = 10 * time.Millisecond
+	cfg.ReconnectCount = 3
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			// the mock signer signs anything
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		ErrTestReconnectFailed,
+	)
+}
+
+func TestRemoteSignerSignLatency(t *testing.T) {
+	// the signer takes 30ms to sign votes
+	slowSigner := func(th *TestHarness) *privval.SignerServer {
+		ss := newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		ss.SetRequestHandler(func(
+			privVal types.PrivValidator,
+			req privvalproto.Message,
+			chainID string,
+		) (privvalproto.Message, error) {
+			if _, ok := req.Sum.(*privvalproto.Message_SignVoteRequest); ok {
+				time.Sleep(30 * time.Millisecond)
+			}
+			return privval.DefaultValidationRequestHandler(privVal, req, chainID)
+		})
+		return ss
+	}
+
+	cfg := makeConfig(t, 100, 3)
+	cfg.MaxSignLatency = time.Second
+	harnessTestWithConfig(t, cfg, slowSigner, NoError)
+
+	cfg = makeConfig(t, 100, 3)
+	cfg.MaxSignLatency = 10 * time.Millisecond
+	harnessTestWithConfig(t, cfg, slowSigner, ErrTestSignLatencyFailed)
+}
+
+func TestRemoteSignerBenchVotes(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.BenchVotes = 5
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		NoError,
+	)
+
+	// the signer fails to sign the third vote of the benchmark, after which
+	// no more votes are requested
+	var benchVotes int32
+	cfg = makeConfig(t, 100, 3)
+	cfg.BenchVotes = 5
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			ss := newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+			ss.SetRequestHandler(func(
+				privVal types.PrivValidator,
+				req privvalproto.Message,
+				chainID string,
+			) (privvalproto.Message, error) {
+				if r, ok := req.Sum.(*privvalproto.Message_SignVoteRequest); ok && r.SignVoteRequest.Vote.Height >= 110 {
+					if atomic.AddInt32(&benchVotes, 1) == 3 {
+						return privval.DefaultValidationRequestHandler(privVal, req, "another-chain")
+					}
+				}
+				return privval.DefaultValidationRequestHandler(privVal, req, chainID)
+			})
+			return ss
+		},
+		ErrTestBenchVotesFailed,
+	)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&benchVotes))
+}
+
+func TestRemoteSignerTestHarnessReport(t *testing.T) {
+	var report bytes.Buffer
+	cfg := makeConfig(t, 100, 3)
+	cfg.Report = &report
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+		},
+		NoError,
+	)
+
+	var r Report
+	require.NoError(t, json.Unmarshal(report.Bytes(), &r))
+	assert.True(t, r.Passed)
+	assert.Equal(t, NoError, r.ExitCode)
+	assert.Empty(t, r.Error)
+	assert.Equal(t, ed25519.KeyType, r.SecretConnKeyType)
+	names := make([]string, len(r.Steps))
+	for i, step := range r.Steps {
+		names[i] = step.Name
+		assert.True(t, step.Passed, step.Name)
+	}
+	assert.Equal(t, []string{"connect", "public_key", "genesis_public_key", "sign_proposal", "sign_vote", "sign_latency"},
+		names)
+
+	// the failed step is the last one
+	report.Reset()
+	cfg = makeConfig(t, 100, 3)
+	cfg.Report = &report
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, true)
+		},
+		ErrTestSignVoteFailed,
+	)
+
+	r = Report{}
+	require.NoError(t, json.Unmarshal(report.Bytes(), &r))
+	assert.False(t, r.Passed)
+	assert.Equal(t, ErrTestSignVoteFailed, r.ExitCode)
+	assert.NotEmpty(t, r.Error)
+	require.NotEmpty(t, r.Steps)
+	last := r.Steps[len(r.Steps)-1]
+	assert.Equal(t, "sign_vote", last.Name)
+	assert.False(t, last.Passed)
+}
+
+func TestRemoteSignerTestHarnessUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "harness.sock")
+	// a socket left by a crashed run
+	ln, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, ln.Close())
+	require.FileExists(t, socketPath)
+
+	cfg := makeConfig(t, 100, 3)
+	cfg.BindAddr = "unix://" + socketPath
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			endpoint := privval.NewSignerDialerEndpoint(th.logger, privval.DialUnixFn(socketPath))
+			return privval.NewSignerServer(endpoint, th.chainID, types.NewMockPVWithParams(th.fpv.Key.PrivKey, false, false))
+		},
+		NoError,
+	)
+	assert.NoFileExists(t, socketPath)
+
+	// any other kind of file is not removed
+	require.NoError(t, os.WriteFile(socketPath, []byte("not a socket"), 0o600))
+	cfg = makeConfig(t, 100, 3)
+	cfg.BindAddr = "unix://" + socketPath
+	defer cleanup(cfg)
+	_, err = NewTestHarness(log.TestingLogger(), cfg)
+	require.Error(t, err)
+	assert.FileExists(t, socketPath)
+}
+
+func TestRemoteSignerTestHarnessMaxConcurrentConns(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.MaxConcurrentConns = 1
+	harnessTestWithConfig(t, cfg, func(th *TestHarness) *privval.SignerServer {
+		return newMockSignerServer(t, th, th.fpv.Key.PrivKey, false, false)
+	}, NoError)
+}
+
+func TestRemoteSignerTestHarnessDial(t *testing.T) {
+	cfg := makeConfig(t, 100, 3)
+	cfg.DialAddr = privval.GetFreeLocalhostAddrPort()
+	proto, addr := tmnet.ProtocolAndAddress(cfg.DialAddr)
+	ln, err := net.Listen(proto, addr)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	harnessTestWithConfig(
+		t,
+		cfg,
+		func(th *TestHarness) *privval.SignerServer {
+			// the signer listens, and the harness dials it
+			endpoint := privval.NewSignerDialerEndpoint(th.logger, func() (net.Conn, error) {
+				conn, err := ln.Accept()
+				if err != nil {
+					return nil, err
+				}
+				return p2pconn.MakeSecretConnection(conn, ed25519.GenPrivKey())
+			})
+			return privval.NewSignerServer(endpoint, th.chainID, th.fpv)
+		},
+		NoError,
+	)
+}
+
+func TestRemoteSignerTestHarnessDialFailed(t *testing.T) {
+	cfg := makeConfig(t, 1, 2)
+	defer cleanup(cfg)
+	// nothing listens on this address
+	cfg.DialAddr = privval.GetFreeLocalhostAddrPort()
+
+	th, err := NewTestHarness(log.TestingLogger(), cfg)
+	require.NoError(t, err)
+	th.Run()
+	assert.Equal(t, ErrMaxAcceptRetriesReached, th.exitCode)
+}
+
 func newMockSignerServer(
 	t *testing.T,
 	th *TestHarness,
@@ -130,7 +465,10 @@ func newMockSignerServer(
 	breakVoteSigning bool,
 ) *privval.SignerServer {
 	mockPV := types.NewMockPVWithParams(privKey, breakProposalSigning, breakVoteSigning)
+	return newSignerServer(th, mockPV)
+}
 
+func newSignerServer(th *TestHarness, pv types.PrivValidator) *privval.SignerServer {
 	dialerEndpoint := privval.NewSignerDialerEndpoint(
 		th.logger,
 		privval.DialTCPFn(
@@ -140,12 +478,21 @@ func newMockSignerServer(
 		),
 	)
 
-	return privval.NewSignerServer(dialerEndpoint, th.chainID, mockPV)
+	return privval.NewSignerServer(dialerEndpoint, th.chainID, pv)
 }
 
 // For running relatively standard tests.
 func harnessTest(t *testing.T, signerServerMaker func(th *TestHarness) *privval.SignerServer, expectedExitCode int) {
-	cfg := makeConfig(t, 100, 3)
+	harnessTestWithConfig(t, makeConfig(t, 100, 3), signerServerMaker, expectedExitCode)
+}
+
+// For running standard tests with a customized configuration.
+func harnessTestWithConfig(
+	t *testing.T,
+	cfg TestHarnessConfig,
+	signerServerMaker func(th *TestHarness) *privval.SignerServer,
+	expectedExitCode int,
+) {
 	defer cleanup(cfg)
 
 	th, err := NewTestHarness(log.TestingLogger(), cfg)
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"golang.org/x/term"
+
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/libs/log"
-	"github.com/tendermint/tendermint/privval"
 	"github.com/tendermint/tendermint/tools/tm-signer-harness/internal"
 	"github.com/tendermint/tendermint/version"
 )
@@ -18,9 +20,11 @@ const (
 	defaultAcceptRetries    = 100
 	defaultBindAddr         = "tcp://127.0.0.1:0"
 	defaultTMHome           = "~/.tendermint"
-	defaultAcceptDeadline   = 1
-	defaultConnDeadline     = 3
+	defaultAcceptDeadline   = time.Second
+	defaultConnDeadline     = 3 * time.Second
 	defaultExtractKeyOutput = "./signing.key"
+	defaultExtractKeyDir    = "."
+	defaultReconnectCount   = 10
 )
 
 var logger = log.NewTMLogger(log.NewSyncWriter(os.Stdout))
@@ -28,16 +32,49 @@ var logger = log.NewTMLogger(log.NewSyncWriter(os.Stdout))
 // Command line flags
 var (
 	flagAcceptRetries int
+	flagMaxConns      int
+	flagAcceptDl      time.Duration
+	flagConnDl        time.Duration
 	flagBindAddr      string
+	flagDialAddr      string
 	flagTMHome        string
 	flagKeyOutputPath string
+	flagPingCount     int
+	flagTimeout       time.Duration
+	flagConfigFile    string
+	flagSecondChainID string
+	flagReconnectInt  time.Duration
+	flagReconnectCnt  int
+	flagDumpProtocol  string
+	flagMaxSignLat    time.Duration
+	flagBenchVotes    int
+	flagOutput        string
+	flagKeyTMHomes    stringsFlag
+	flagKeyHomesDir   string
+	flagKeyOutputDir  string
+	flagKeyStrict     bool
+	flagKeyType       string
+	flagKeyEncrypt    bool
+	flagDecryptInput  string
+	flagDecryptOutput string
 )
 
+// stringsFlag is a flag which may be given several times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 // Command line commands
 var (
 	rootCmd       *flag.FlagSet
 	runCmd        *flag.FlagSet
 	extractKeyCmd *flag.FlagSet
+	decryptKeyCmd *flag.FlagSet
 	versionCmd    *flag.FlagSet
 )
 
@@ -50,6 +87,7 @@ Usage:
   tm-signer-harness <command> [flags]
 
 Available Commands:
+  decrypt_key        Decrypts a signing key encrypted by extract_key
   extract_key        Extracts a signing key from a local Tendermint instance
   help               Help on the available commands
   run                Runs the test harness
@@ -64,8 +102,67 @@ Use "tm-signer-harness help <command>" for more information about that command.`
 		"accept-retries",
 		defaultAcceptRetries,
 		"The number of attempts to listen for incoming connections")
-	runCmd.StringVar(&flagBindAddr, "addr", defaultBindAddr, "Bind to this address for the testing")
+	runCmd.IntVar(&flagMaxConns,
+		"max-concurrent-conns",
+		0,
+		"The number of signer connections accepted at once, beyond which new connections are rejected (0 disables the limit)")
+	runCmd.DurationVar(&flagAcceptDl,
+		"accept-deadline",
+		defaultAcceptDeadline,
+		"The maximum duration of an attempt to accept the connection of the signer")
+	runCmd.DurationVar(&flagConnDl,
+		"conn-deadline",
+		defaultConnDeadline,
+		"The maximum duration of a read or write on the connection to the signer")
+	runCmd.StringVar(&flagBindAddr,
+		"addr",
+		defaultBindAddr,
+		"Bind to this address for the testing (tcp://<host>:<port> or unix://<path>)")
+	runCmd.StringVar(&flagDialAddr,
+		"dial",
+		"",
+		"Connect to a signer listening on this address instead of binding to -addr (empty disables dialing)")
 	runCmd.StringVar(&flagTMHome, "tmhome", defaultTMHome, "Path to the Tendermint home directory")
+	runCmd.IntVar(&flagPingCount,
+		"ping-count",
+		0,
+		"The number of pings to send to measure the signer's round-trip latency (0 disables the ping test)")
+	runCmd.DurationVar(&flagTimeout,
+		"timeout",
+		0,
+		"The maximum duration of the whole run, after which the harness fails (0 disables the timeout)")
+	runCmd.StringVar(&flagSecondChainID,
+		"second-chain-id",
+		"",
+		"A chain ID other than the genesis one, for which the signer must refuse to sign (empty disables the test)")
+	runCmd.DurationVar(&flagReconnectInt,
+		"reconnect-interval",
+		0,
+		"The interval at which to drop and re-establish the connection to the signer (0 disables the reconnect test)")
+	runCmd.IntVar(&flagReconnectCnt,
+		"reconnect-count",
+		defaultReconnectCount,
+		"The number of reconnections of the reconnect test")
+	runCmd.StringVar(&flagDumpProtocol,
+		"dump-protocol",
+		"",
+		"Path to a file to which the messages exchanged with the signer are written, decoded as JSON (empty disables the dump)")
+	runCmd.DurationVar(&flagMaxSignLat,
+		"max-sign-latency",
+		0,
+		"The latency budget of a signing request, beyond which the harness fails even if the signature is valid (0 disables the budget)")
+	runCmd.IntVar(&flagBenchVotes,
+		"bench-votes",
+		0,
+		"The number of sequential votes to sign once the tests have passed, to measure the signing latency under load (0 disables the benchmark)")
+	runCmd.StringVar(&flagOutput,
+		"output",
+		internal.OutputText,
+		"The format of the outcome of the run (text or json): with json, a JSON report is printed to stdout and the logs go to stderr")
+	runCmd.StringVar(&flagConfigFile,
+		"config",
+		"",
+		"Path to a JSON or TOML file with the run parameters (flags given on the command line override its values)")
 	runCmd.Usage = func() {
 		fmt.Println(`Runs the remote signer test harness for Tendermint.
 
@@ -82,11 +179,43 @@ Flags:`)
 		"output",
 		defaultExtractKeyOutput,
 		"Path to which signing key should be written")
-	extractKeyCmd.StringVar(&flagTMHome, "tmhome", defaultTMHome, "Path to the Tendermint home directory")
+	extractKeyCmd.Var(&flagKeyTMHomes,
+		"tmhome",
+		"Path to the Tendermint home directory (may be given several times, default \""+defaultTMHome+"\")")
+	extractKeyCmd.StringVar(&flagKeyHomesDir,
+		"homes-dir",
+		"",
+		"Path to a directory whose subdirectories are Tendermint home directories, the keys of which are all extracted")
+	extractKeyCmd.StringVar(&flagKeyOutputDir,
+		"output-dir",
+		defaultExtractKeyDir,
+		"Path to the directory to which the keys are written when extracting several keys")
+	extractKeyCmd.BoolVar(&flagKeyStrict,
+		"strict",
+		false,
+		"When extracting several keys, fail if any of the extractions fails")
+	extractKeyCmd.StringVar(&flagKeyType,
+		"key-type",
+		internal.KeyTypeAuto,
+		"The type of the keys (auto, ed25519 or secp256k1): a key of another type fails to be extracted (auto accepts both)")
+	extractKeyCmd.BoolVar(&flagKeyEncrypt,
+		"encrypt",
+		false,
+		"Encrypt the keys with a passphrase, read from $"+internal.PassphraseEnvVar+" or prompted, instead of writing them in plaintext")
 	extractKeyCmd.Usage = func() {
 		fmt.Println(`Extracts a signing key from a local Tendermint instance for use in the remote
 signer under test.
 
+Several keys are extracted in one invocation by giving -tmhome several times,
+or with -homes-dir. Each key is then written to the output directory, named
+after its home directory (e.g. ~/nodes/val0 is written to val0.key). The
+outcome of each extraction is reported; the command only fails because of a
+failed extraction with -strict.
+
+With -encrypt, the keys are encrypted with a secret derived from a passphrase,
+which is read from $` + internal.PassphraseEnvVar + ` if set, and prompted
+otherwise. They can be decrypted with decrypt_key.
+
 Usage:
   tm-signer-harness extract_key [flags]
 
@@ -95,6 +224,28 @@ Flags:`)
 		fmt.Println("")
 	}
 
+	decryptKeyCmd = flag.NewFlagSet("decrypt_key", flag.ExitOnError)
+	decryptKeyCmd.StringVar(&flagDecryptInput,
+		"input",
+		defaultExtractKeyOutput,
+		"Path to the signing key encrypted by extract_key -encrypt")
+	decryptKeyCmd.StringVar(&flagDecryptOutput,
+		"output",
+		"",
+		"Path to which the decrypted signing key should be written (required)")
+	decryptKeyCmd.Usage = func() {
+		fmt.Println(`Decrypts a signing key encrypted by extract_key -encrypt, and writes it in
+plaintext for the remote signer under test. The passphrase is read from
+$` + internal.PassphraseEnvVar + ` if set, and prompted otherwise.
+
+Usage:
+  tm-signer-harness decrypt_key [flags]
+
+Flags:`)
+		decryptKeyCmd.PrintDefaults()
+		fmt.Println("")
+	}
+
 	versionCmd = flag.NewFlagSet("version", flag.ExitOnError)
 	versionCmd.Usage = func() {
 		fmt.Println(`
@@ -106,18 +257,116 @@ Usage:
 	}
 }
 
-func runTestHarness(acceptRetries int, bindAddr, tmhome string) {
-	tmhome = internal.ExpandPath(tmhome)
-	cfg := internal.TestHarnessConfig{
-		BindAddr:         bindAddr,
-		KeyFile:          filepath.Join(tmhome, "config", "priv_validator_key.json"),
-		StateFile:        filepath.Join(tmhome, "data", "priv_validator_state.json"),
-		GenesisFile:      filepath.Join(tmhome, "config", "genesis.json"),
-		AcceptDeadline:   time.Duration(defaultAcceptDeadline) * time.Second,
-		AcceptRetries:    acceptRetries,
-		ConnDeadline:     time.Duration(defaultConnDeadline) * time.Second,
-		SecretConnKey:    ed25519.GenPrivKey(),
-		ExitWhenComplete: true,
+// runConfig returns the parameters of the run command. The values of the
+// configuration file, if any, override the defaults and are in turn overridden
+// by the flags set on the command line.
+func runConfig() (internal.RunConfig, error) {
+	rc := internal.RunConfig{
+		BindAddr:           flagBindAddr,
+		DialAddr:           flagDialAddr,
+		TMHome:             flagTMHome,
+		AcceptRetries:      flagAcceptRetries,
+		MaxConcurrentConns: flagMaxConns,
+		AcceptDeadline:     internal.Duration(flagAcceptDl),
+		ConnDeadline:       internal.Duration(flagConnDl),
+		PingCount:          flagPingCount,
+		Timeout:            internal.Duration(flagTimeout),
+		SecondChainID:      flagSecondChainID,
+		ReconnectInterval:  internal.Duration(flagReconnectInt),
+		ReconnectCount:     flagReconnectCnt,
+		DumpProtocol:       flagDumpProtocol,
+		MaxSignLatency:     internal.Duration(flagMaxSignLat),
+		BenchVotes:         flagBenchVotes,
+		Output:             flagOutput,
+	}
+	if flagConfigFile == "" {
+		return rc, rc.ValidateBasic()
+	}
+
+	rc, err := internal.LoadRunConfig(flagConfigFile, rc)
+	if err != nil {
+		return rc, err
+	}
+	runCmd.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			rc.BindAddr = flagBindAddr
+		case "dial":
+			rc.DialAddr = flagDialAddr
+		case "tmhome":
+			rc.TMHome = flagTMHome
+		case "accept-retries":
+			rc.AcceptRetries = flagAcceptRetries
+		case "max-concurrent-conns":
+			rc.MaxConcurrentConns = flagMaxConns
+		case "accept-deadline":
+			rc.AcceptDeadline = internal.Duration(flagAcceptDl)
+		case "conn-deadline":
+			rc.ConnDeadline = internal.Duration(flagConnDl)
+		case "ping-count":
+			rc.PingCount = flagPingCount
+		case "timeout":
+			rc.Timeout = internal.Duration(flagTimeout)
+		case "second-chain-id":
+			rc.SecondChainID = flagSecondChainID
+		case "reconnect-interval":
+			rc.ReconnectInterval = internal.Duration(flagReconnectInt)
+		case "reconnect-count":
+			rc.ReconnectCount = flagReconnectCnt
+		case "dump-protocol":
+			rc.DumpProtocol = flagDumpProtocol
+		case "max-sign-latency":
+			rc.MaxSignLatency = internal.Duration(flagMaxSignLat)
+		case "bench-votes":
+			rc.BenchVotes = flagBenchVotes
+		case "output":
+			rc.Output = flagOutput
+		}
+	})
+	return rc, rc.ValidateBasic()
+}
+
+// harnessConfig returns the configuration of the test harness for the given
+// run parameters.
+func harnessConfig(rc internal.RunConfig) internal.TestHarnessConfig {
+	tmhome := internal.ExpandPath(rc.TMHome)
+	return internal.TestHarnessConfig{
+		BindAddr:           rc.BindAddr,
+		DialAddr:           rc.DialAddr,
+		KeyFile:            filepath.Join(tmhome, "config", "priv_validator_key.json"),
+		StateFile:          filepath.Join(tmhome, "data", "priv_validator_state.json"),
+		GenesisFile:        filepath.Join(tmhome, "config", "genesis.json"),
+		AcceptDeadline:     time.Duration(rc.AcceptDeadline),
+		AcceptRetries:      rc.AcceptRetries,
+		MaxConcurrentConns: rc.MaxConcurrentConns,
+		ConnDeadline:       time.Duration(rc.ConnDeadline),
+		SecretConnKey:      ed25519.GenPrivKey(),
+		PingCount:          rc.PingCount,
+		Timeout:            time.Duration(rc.Timeout),
+		SecondChainID:      rc.SecondChainID,
+		ReconnectInterval:  time.Duration(rc.ReconnectInterval),
+		ReconnectCount:     rc.ReconnectCount,
+		MaxSignLatency:     time.Duration(rc.MaxSignLatency),
+		BenchVotes:         rc.BenchVotes,
+		ExitWhenComplete:   true,
+	}
+}
+
+func runTestHarness(rc internal.RunConfig) {
+	cfg := harnessConfig(rc)
+	if rc.Output == internal.OutputJSON {
+		// stdout only holds the report, so that it can be parsed
+		logger = log.NewFilter(log.NewTMLogger(log.NewSyncWriter(os.Stderr)), log.AllowInfo())
+		cfg.Report = os.Stdout
+	}
+	if rc.DumpProtocol != "" {
+		f, err := os.Create(internal.ExpandPath(rc.DumpProtocol))
+		if err != nil {
+			logger.Error("Failed to create the protocol dump file", "path", rc.DumpProtocol, "err", err)
+			os.Exit(internal.ErrInvalidParameters)
+		}
+		defer f.Close()
+		cfg.ProtocolDump = f
 	}
 	harness, err := internal.NewTestHarness(logger, cfg)
 	if err != nil {
@@ -130,18 +379,93 @@ func runTestHarness(acceptRetries int, bindAddr, tmhome string) {
 	harness.Run()
 }
 
-func extractKey(tmhome, outputPath string) {
-	keyFile := filepath.Join(internal.ExpandPath(tmhome), "config", "priv_validator_key.json")
-	stateFile := filepath.Join(internal.ExpandPath(tmhome), "data", "priv_validator_state.json")
-	fpv := privval.LoadFilePV(keyFile, stateFile)
-	pkb := []byte(fpv.Key.PrivKey.(ed25519.PrivKey))
-	if err := os.WriteFile(internal.ExpandPath(outputPath), pkb[:32], 0o600); err != nil {
+// readPassphrase returns the passphrase of encrypted keys, from the
+// environment or prompted on the terminal. It returns an empty passphrase if
+// none is available.
+func readPassphrase() (string, error) {
+	if passphrase := os.Getenv(internal.PassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", nil
+	}
+	fmt.Fprint(os.Stderr, "Passphrase: ")
+	bz, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return string(bz), err
+}
+
+// mustReadPassphrase returns the passphrase of encrypted keys, and exits if
+// none is available.
+func mustReadPassphrase() string {
+	passphrase, err := readPassphrase()
+	if err != nil {
+		logger.Error("Failed to read the passphrase", "err", err)
+		os.Exit(1)
+	}
+	if passphrase == "" {
+		logger.Error("No passphrase: set $" + internal.PassphraseEnvVar + " or run in a terminal")
+		os.Exit(1)
+	}
+	return passphrase
+}
+
+func extractKey(tmhome, outputPath, keyType, passphrase string) {
+	if err := internal.ExtractKey(tmhome, outputPath, keyType, passphrase); err != nil {
 		logger.Info("Failed to write private key", "output", outputPath, "err", err)
 		os.Exit(1)
 	}
 	logger.Info("Successfully wrote private key", "output", outputPath)
 }
 
+func extractKeys(tmhomes []string, homesDir, outputDir, keyType, passphrase string, strict bool) {
+	if homesDir != "" {
+		homes, err := internal.ListTMHomes(homesDir)
+		if err != nil {
+			logger.Error("Failed to list the Tendermint home directories", "dir", homesDir, "err", err)
+			os.Exit(1)
+		}
+		tmhomes = append(tmhomes, homes...)
+	}
+	if err := os.MkdirAll(internal.ExpandPath(outputDir), 0o700); err != nil {
+		logger.Error("Failed to create the output directory", "dir", outputDir, "err", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, res := range internal.ExtractKeys(tmhomes, internal.ExpandPath(outputDir), keyType, passphrase) {
+		if res.Err != nil {
+			failed++
+			logger.Error("Failed to extract private key", "tmhome", res.TMHome, "err", res.Err)
+			continue
+		}
+		logger.Info("Successfully wrote private key", "tmhome", res.TMHome, "output", res.Output)
+	}
+	logger.Info("Extracted private keys", "extracted", len(tmhomes)-failed, "failed", failed)
+	if strict && failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func decryptKey(inputPath, outputPath, passphrase string) {
+	bz, err := os.ReadFile(internal.ExpandPath(inputPath))
+	if err != nil {
+		logger.Error("Failed to read the encrypted key", "input", inputPath, "err", err)
+		os.Exit(1)
+	}
+	raw, keyType, err := internal.DecryptKey(bz, passphrase)
+	if err != nil {
+		logger.Error("Failed to decrypt the key", "input", inputPath, "err", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(internal.ExpandPath(outputPath), raw, 0o600); err != nil {
+		logger.Error("Failed to write private key", "output", outputPath, "err", err)
+		os.Exit(1)
+	}
+	logger.Info("Successfully wrote private key", "output", outputPath, "type", keyType)
+}
+
 func main() {
 	if err := rootCmd.Parse(os.Args[1:]); err != nil {
 		fmt.Printf("Error parsing flags: %v\n", err)
@@ -161,6 +485,8 @@ func main() {
 			runCmd.Usage()
 		case "extract_key":
 			extractKeyCmd.Usage()
+		case "decrypt_key":
+			decryptKeyCmd.Usage()
 		case "version":
 			versionCmd.Usage()
 		default:
@@ -172,13 +498,45 @@ func main() {
 			fmt.Printf("Error parsing flags: %v\n", err)
 			os.Exit(1)
 		}
-		runTestHarness(flagAcceptRetries, flagBindAddr, flagTMHome)
+		rc, err := runConfig()
+		if err != nil {
+			logger.Error("Invalid run parameters", "err", err)
+			os.Exit(internal.ErrInvalidParameters)
+		}
+		runTestHarness(rc)
 	case "extract_key":
 		if err := extractKeyCmd.Parse(os.Args[2:]); err != nil {
 			fmt.Printf("Error parsing flags: %v\n", err)
 			os.Exit(1)
 		}
-		extractKey(flagTMHome, flagKeyOutputPath)
+		if err := internal.ValidateKeyType(flagKeyType); err != nil {
+			logger.Error("Invalid key type", "err", err)
+			os.Exit(1)
+		}
+		// never fall back to plaintext without a passphrase
+		var passphrase string
+		if flagKeyEncrypt {
+			passphrase = mustReadPassphrase()
+		}
+		if len(flagKeyTMHomes) <= 1 && flagKeyHomesDir == "" {
+			tmhome := defaultTMHome
+			if len(flagKeyTMHomes) == 1 {
+				tmhome = flagKeyTMHomes[0]
+			}
+			extractKey(tmhome, flagKeyOutputPath, flagKeyType, passphrase)
+			break
+		}
+		extractKeys(flagKeyTMHomes, flagKeyHomesDir, flagKeyOutputDir, flagKeyType, passphrase, flagKeyStrict)
+	case "decrypt_key":
+		if err := decryptKeyCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			os.Exit(1)
+		}
+		if flagDecryptOutput == "" {
+			logger.Error("The output path is required")
+			os.Exit(1)
+		}
+		decryptKey(flagDecryptInput, flagDecryptOutput, mustReadPassphrase())
 	case "version":
 		fmt.Println(version.TMCoreSemVer)
 	default:
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConfigDeadlines(t *testing.T) {
+	require.NoError(t, runCmd.Parse([]string{"-accept-deadline", "5s", "-conn-deadline", "1m30s"}))
+	rc, err := runConfig()
+	require.NoError(t, err)
+	cfg := harnessConfig(rc)
+	assert.Equal(t, 5*time.Second, cfg.AcceptDeadline)
+	assert.Equal(t, 90*time.Second, cfg.ConnDeadline)
+
+	require.NoError(t, runCmd.Parse([]string{"-accept-deadline", "0s"}))
+	_, err = runConfig()
+	assert.Error(t, err)
+
+	require.NoError(t, runCmd.Parse([]string{"-accept-deadline", "1s", "-conn-deadline", "-1s"}))
+	_, err = runConfig()
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/merkle"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmsync "github.com/tendermint/tendermint/libs/sync"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 )
 
@@ -30,6 +31,39 @@ func (tx Tx) Hash() []byte {
 	return tmhash.Sum(tx)
 }
 
+// TxHasher computes the hash by which a transaction is indexed and looked up.
+type TxHasher func(Tx) []byte
+
+var (
+	txHasherMtx tmsync.RWMutex
+	txHasher    TxHasher
+)
+
+// RegisterTxHasher registers the hash function of the application, so that
+// transactions are indexed, looked up and reported by RPC by the hash clients
+// compute. A nil h restores the default, Tx.Hash. It must be called before the
+// node is started: the transactions indexed with another hash function are not
+// found anymore.
+func RegisterTxHasher(h TxHasher) {
+	txHasherMtx.Lock()
+	defer txHasherMtx.Unlock()
+	txHasher = h
+}
+
+// TxHash returns the hash by which tx is indexed and looked up: the hash
+// computed by the registered TxHasher, or tx.Hash() if none is registered.
+// Unlike tx.Hash(), it isn't part of consensus: the data hash of blocks and the
+// proofs of transactions always use tx.Hash().
+func TxHash(tx Tx) []byte {
+	txHasherMtx.RLock()
+	h := txHasher
+	txHasherMtx.RUnlock()
+	if h == nil {
+		return tx.Hash()
+	}
+	return h(tx)
+}
+
 func (tx Tx) Key() TxKey {
 	return sha256.Sum256(tx)
 }
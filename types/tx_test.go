@@ -51,6 +51,20 @@ func TestTxIndexByHash(t *testing.T) {
 	}
 }
 
+func TestTxHash(t *testing.T) {
+	tx := Tx("tx")
+	assert.Equal(t, tx.Hash(), TxHash(tx))
+
+	RegisterTxHasher(func(tx Tx) []byte { return append([]byte("custom-"), tx...) })
+	t.Cleanup(func() { RegisterTxHasher(nil) })
+	assert.Equal(t, []byte("custom-tx"), TxHash(tx))
+	// the consensus hash is left untouched
+	assert.NotEqual(t, tx.Hash(), TxHash(tx))
+
+	RegisterTxHasher(nil)
+	assert.Equal(t, tx.Hash(), TxHash(tx))
+}
+
 func TestValidTxProof(t *testing.T) {
 	cases := []struct {
 		txs Txs